@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Login authenticates with email and password and keeps the returned tokens.
+func (c *Client) Login(ctx context.Context, lr LoginRequest) (UserWithToken, error) {
+	var u UserWithToken
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/auth/login", body: lr}, &u)
+	if err != nil {
+		return u, err
+	}
+	c.setTokens(u.Token, u.RefreshToken)
+	return u, nil
+}
+
+// Refresh exchanges the refresh token for a new access token. It is called
+// automatically when a request is rejected with 401.
+func (c *Client) Refresh(ctx context.Context) error {
+	var res struct {
+		Token string `json:"token"`
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/auth/refresh", auth: authRefresh}, &res)
+	if err != nil {
+		return err
+	}
+	c.setTokens(res.Token, "")
+	return nil
+}
+
+// Revoke invalidates the refresh token on the server and forgets both tokens.
+func (c *Client) Revoke(ctx context.Context) error {
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/auth/revoke", auth: authRefresh}, nil)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.token, c.refreshToken = "", ""
+	c.mu.Unlock()
+	return nil
+}
+
+// Health calls /v1/healthz and returns an error if the server is not ready.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodGet, path: "/v1/healthz"}, nil)
+}
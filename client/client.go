@@ -0,0 +1,292 @@
+// Package client is a typed Go client for the meal-org /v1 API.
+//
+// It keeps track of the access and refresh tokens returned by Login and
+// CreateUser, refreshes the access token through /v1/auth/refresh when the
+// server rejects it, and retries idempotent requests on transient failures.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 2
+	defaultBackoff    = 200 * time.Millisecond
+)
+
+var ErrNotAuthenticated = errors.New("client is not authenticated")
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+
+	mu           sync.Mutex
+	token        string
+	refreshToken string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying http.Client, e.g. to configure timeouts
+// or a custom transport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRetries sets how many times an idempotent request is retried after a
+// network error or a 5xx/429 response, and the base delay between attempts.
+// The delay doubles after each attempt.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithTokens starts the client with tokens obtained elsewhere.
+func WithTokens(token, refreshToken string) Option {
+	return func(c *Client) {
+		c.token = token
+		c.refreshToken = refreshToken
+	}
+}
+
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base url: %s", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Tokens returns the access and refresh tokens currently held by the client.
+func (c *Client) Tokens() (token, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token, c.refreshToken
+}
+
+func (c *Client) setTokens(token, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	if refreshToken != "" {
+		c.refreshToken = refreshToken
+	}
+}
+
+// APIError is returned when the server responds with a non 2xx status.
+type APIError struct {
+	StatusCode int
+	// Message holds the "error" field of the response body. It can be a
+	// string or an object of validation errors, so it is kept raw.
+	Message json.RawMessage
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("meal-org api: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), string(e.Message))
+}
+
+// IsStatus reports whether err is an APIError with the given status code.
+func IsStatus(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
+
+type authMode int
+
+const (
+	authNone authMode = iota
+	authAccess
+	authRefresh
+)
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	header http.Header
+	auth   authMode
+}
+
+// do sends the request and decodes a successful response into out, if out
+// is not nil. An expired access token is refreshed once per call.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var body []byte
+	switch b := req.body.(type) {
+	case nil:
+	case []byte:
+		// Uploads are sent as they are, with their own Content-Type
+		body = b
+	default:
+		var err error
+		body, err = json.Marshal(req.body)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.doWithRetry(ctx, req, body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && req.auth == authAccess {
+		_, refreshToken := c.Tokens()
+		if refreshToken != "" {
+			resp.Body.Close()
+			if err := c.Refresh(ctx); err != nil {
+				return err
+			}
+			resp, err = c.doWithRetry(ctx, req, body)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	defer resp.Body.Close()
+
+	return decodeResponse(resp, out)
+}
+
+func (c *Client) doWithRetry(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	retries := 0
+	if isIdempotent(req.method) {
+		retries = c.maxRetries
+	}
+
+	delay := c.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req, body)
+		if attempt >= retries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body) // #nosec G104
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	u := c.baseURL.JoinPath(req.path)
+	if len(req.query) > 0 {
+		u.RawQuery = req.query.Encode()
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range req.header {
+		httpReq.Header[k] = v
+	}
+
+	token, refreshToken := c.Tokens()
+	switch req.auth {
+	case authAccess:
+		if token == "" {
+			return nil, ErrNotAuthenticated
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	case authRefresh:
+		if refreshToken == "" {
+			return nil, ErrNotAuthenticated
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+refreshToken)
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+func decodeResponse(resp *http.Response, out any) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var errBody struct {
+			Error json.RawMessage `json:"error"`
+		}
+		if json.Unmarshal(data, &errBody) == nil && len(errBody.Error) > 0 {
+			apiErr.Message = errBody.Error
+		} else {
+			// Some middlewares respond with plain text
+			msg, _ := json.Marshal(strings.TrimSpace(string(data)))
+			apiErr.Message = msg
+		}
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent || len(data) == 0 {
+		return nil
+	}
+	// Files, such as exports, are handed back as they are
+	if raw, ok := out.(*[]byte); ok {
+		*raw = data
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded) &&
+			!errors.Is(err, ErrNotAuthenticated)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
@@ -0,0 +1,381 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quangd42/meal-org/client"
+	"github.com/quangd42/meal-org/internal/blob"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/fetcher"
+	"github.com/quangd42/meal-org/internal/handlers"
+	"github.com/quangd42/meal-org/internal/planner"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+// flaky answers 503 to the next failures requests before passing them on,
+// and counts the requests it sees.
+type flaky struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	failures int
+	requests map[string]int
+}
+
+func (f *flaky) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests[r.Method+" "+r.URL.Path]++
+	fail := f.failures > 0
+	if fail {
+		f.failures--
+	}
+	f.mu.Unlock()
+
+	if fail {
+		http.Error(w, "try again", http.StatusServiceUnavailable)
+		return
+	}
+	f.next.ServeHTTP(w, r)
+}
+
+func (f *flaky) failNext(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = n
+	f.requests = map[string]int{}
+}
+
+func (f *flaky) count(method, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[method+" "+path]
+}
+
+type testServer struct {
+	*httptest.Server
+	*flaky
+	users services.UserService
+}
+
+// newTestServer serves the routes of the app over the database at
+// $TEST_DATABASE_URL. The test is skipped without one.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := pgxpool.New(context.Background(), dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	store := database.NewStore(db)
+
+	ms, err := services.NewMigrationService(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ms.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	blobs, err := blob.NewDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	us := services.NewUserService(store)
+	sm := services.NewSessionManager(store)
+	r := chi.NewRouter()
+	handlers.AddRoutes(r, sm,
+		services.NewRendererService(),
+		us,
+		services.NewAuthService(store, "test-secret"),
+		services.NewRecipeService(store, fetcher.New(), blobs, planner.Suggester{}),
+		services.NewHealthService(store, ms, sm.Store),
+	)
+
+	f := &flaky{next: r, requests: map[string]int{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return &testServer{Server: srv, flaky: f, users: us}
+}
+
+// newTestClient signs up a new user, deleted when the test ends. The user
+// is an admin, so that it may create cuisines.
+func newTestClient(t *testing.T, srv *testServer) *client.Client {
+	t.Helper()
+	ctx := context.Background()
+	c, err := client.New(srv.URL, client.WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := c.CreateUser(ctx, client.CreateUserRequest{
+		Email:    uuid.NewString() + "@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.users.UpdateUserAdminByID(ctx, user.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.DeleteUser(ctx) })
+	return c
+}
+
+func TestClient(t *testing.T) {
+	srv := newTestServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	cuisine, err := c.CreateCuisine(ctx, client.CuisineRequest{Name: "Client " + uuid.NewString()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.DeleteCuisine(ctx, cuisine.ID) })
+	ingredient, err := c.CreateIngredient(ctx, client.IngredientRequest{Name: "Client " + uuid.NewString()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.DeleteIngredient(ctx, ingredient.ID) })
+
+	url := "https://example.com/soup"
+	created, err := c.CreateRecipe(ctx, client.RecipeRequest{
+		Name:              "Soup",
+		ExternalURL:       &url,
+		Servings:          2,
+		CookTimeInMinutes: 20,
+		Cuisines:          []uuid.UUID{cuisine.ID},
+		Ingredients:       []client.IngredientInRecipe{{ID: ingredient.ID, Amount: "1 cup"}},
+		Instructions:      []client.InstructionInRecipe{{StepNo: 1, Instruction: "Boil"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "Soup" || len(created.Ingredients) != 1 {
+		t.Errorf("CreateRecipe: got %+v", created)
+	}
+
+	got, err := c.GetRecipe(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != created.ID || got.Ingredients[0].Amount != "1 cup" {
+		t.Errorf("GetRecipe: got %+v, want %+v", got, created)
+	}
+
+	if err := c.DeleteRecipe(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetRecipe(ctx, created.ID)
+	if !client.IsStatus(err, http.StatusNotFound) {
+		t.Errorf("GetRecipe of a deleted recipe: got %v, want 404", err)
+	}
+	trash, err := c.DeletedRecipes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 1 || trash[0].ID != created.ID {
+		t.Errorf("DeletedRecipes: got %+v", trash)
+	}
+	if err := c.RestoreDeletedRecipe(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRecipe(ctx, created.ID); err != nil {
+		t.Errorf("GetRecipe of a restored recipe: %v", err)
+	}
+}
+
+func TestClientRefresh(t *testing.T) {
+	srv := newTestServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	// A client whose access token the server no longer accepts.
+	_, refreshToken := c.Tokens()
+	expired, err := client.New(srv.URL, client.WithTokens("expired", refreshToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.failNext(0)
+	if _, err := expired.ListCuisines(ctx); err != nil {
+		t.Fatalf("ListCuisines: %v", err)
+	}
+	if n := srv.count(http.MethodPost, "/v1/auth/refresh"); n != 1 {
+		t.Errorf("refreshed %d times, want 1", n)
+	}
+	if n := srv.count(http.MethodGet, "/v1/cuisines"); n != 2 {
+		t.Errorf("sent ListCuisines %d times, want 2", n)
+	}
+	token, _ := expired.Tokens()
+	if token == "expired" || token == "" {
+		t.Errorf("access token was not replaced: %q", token)
+	}
+
+	// Without a refresh token the 401 is returned as is.
+	noRefresh, err := client.New(srv.URL, client.WithTokens("expired", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = noRefresh.ListCuisines(ctx)
+	if !client.IsStatus(err, http.StatusUnauthorized) {
+		t.Errorf("ListCuisines without a refresh token: got %v, want 401", err)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	srv := newTestServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		failures int
+		call     func() error
+		attempts int
+		wantErr  bool
+	}{
+		{
+			name:     "GET is retried",
+			method:   http.MethodGet,
+			path:     "/v1/cuisines",
+			failures: 2,
+			call:     func() error { _, err := c.ListCuisines(ctx); return err },
+			attempts: 3,
+		},
+		{
+			name:     "GET gives up after the retries",
+			method:   http.MethodGet,
+			path:     "/v1/cuisines",
+			failures: 3,
+			call:     func() error { _, err := c.ListCuisines(ctx); return err },
+			attempts: 3,
+			wantErr:  true,
+		},
+		{
+			name:     "PUT is retried",
+			method:   http.MethodPut,
+			path:     "/v1/users",
+			failures: 1,
+			call: func() error {
+				_, err := c.UpdateUser(ctx, client.UpdateUserRequest{Password: "password456"})
+				return err
+			},
+			attempts: 2,
+		},
+		{
+			name:     "POST is not retried",
+			method:   http.MethodPost,
+			path:     "/v1/cuisines",
+			failures: 1,
+			call: func() error {
+				_, err := c.CreateCuisine(ctx, client.CuisineRequest{Name: "Client " + uuid.NewString()})
+				return err
+			},
+			attempts: 1,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv.failNext(tt.failures)
+			err := tt.call()
+			if tt.wantErr && !client.IsStatus(err, http.StatusServiceUnavailable) {
+				t.Errorf("got %v, want 503", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("got %v", err)
+			}
+			if n := srv.count(tt.method, tt.path); n != tt.attempts {
+				t.Errorf("sent %d times, want %d", n, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRecipeIterator(t *testing.T) {
+	srv := newTestServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	cuisine, err := c.CreateCuisine(ctx, client.CuisineRequest{Name: "Client " + uuid.NewString()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.DeleteCuisine(ctx, cuisine.ID) })
+	ingredient, err := c.CreateIngredient(ctx, client.IngredientRequest{Name: "Client " + uuid.NewString()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.DeleteIngredient(ctx, ingredient.ID) })
+
+	url := "https://example.com/"
+	want := map[uuid.UUID]bool{}
+	for i := 0; i < 5; i++ {
+		r, err := c.CreateRecipe(ctx, client.RecipeRequest{
+			Name:              fmt.Sprintf("Recipe %d", i),
+			ExternalURL:       &url,
+			Servings:          1,
+			CookTimeInMinutes: 10,
+			Cuisines:          []uuid.UUID{cuisine.ID},
+			Ingredients:       []client.IngredientInRecipe{{ID: ingredient.ID, Amount: "1"}},
+			Instructions:      []client.InstructionInRecipe{{StepNo: 1, Instruction: "Cook"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[r.ID] = true
+	}
+
+	tests := []struct {
+		pageSize int
+		pages    int
+	}{
+		{pageSize: 2, pages: 3},
+		{pageSize: 5, pages: 2},
+		{pageSize: 10, pages: 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("page size %d", tt.pageSize), func(t *testing.T) {
+			srv.failNext(0)
+			seen := map[uuid.UUID]bool{}
+			it := c.Recipes(ctx, tt.pageSize)
+			for it.Next() {
+				id := it.Recipe().ID
+				if seen[id] {
+					t.Errorf("recipe %s seen twice", id)
+				}
+				seen[id] = true
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if len(seen) != len(want) {
+				t.Errorf("got %d recipes, want %d", len(seen), len(want))
+			}
+			for id := range want {
+				if !seen[id] {
+					t.Errorf("recipe %s missing", id)
+				}
+			}
+			if n := srv.count(http.MethodGet, "/v1/recipes"); n != tt.pages {
+				t.Errorf("fetched %d pages, want %d", n, tt.pages)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateCollection(ctx context.Context, cr CollectionRequest) (Collection, error) {
+	var col Collection
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/collections", body: cr, auth: authAccess}, &col)
+	return col, err
+}
+
+// ListCollections lists the collections of the user, without their recipes.
+func (c *Client) ListCollections(ctx context.Context) ([]Collection, error) {
+	var cols []Collection
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/collections", auth: authAccess}, &cols)
+	return cols, err
+}
+
+// GetCollection returns a collection with its recipes.
+func (c *Client) GetCollection(ctx context.Context, collectionID uuid.UUID) (Collection, error) {
+	var col Collection
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/collections/" + collectionID.String(), auth: authAccess}, &col)
+	return col, err
+}
+
+func (c *Client) UpdateCollection(ctx context.Context, collectionID uuid.UUID, cr CollectionRequest) (Collection, error) {
+	var col Collection
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/collections/" + collectionID.String(), body: cr, auth: authAccess}, &col)
+	return col, err
+}
+
+func (c *Client) DeleteCollection(ctx context.Context, collectionID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/collections/" + collectionID.String(), auth: authAccess}, nil)
+}
+
+// SetCollectionRecipes replaces the recipes of a manual collection, in the
+// order given.
+func (c *Client) SetCollectionRecipes(ctx context.Context, collectionID uuid.UUID, recipeIDs []uuid.UUID) (Collection, error) {
+	var col Collection
+	body := CollectionRecipesRequest{RecipeIDs: recipeIDs}
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/collections/" + collectionID.String() + "/recipes", body: body, auth: authAccess}, &col)
+	return col, err
+}
+
+func (c *Client) AddRecipeToCollection(ctx context.Context, collectionID, recipeID uuid.UUID) error {
+	body := CollectionRecipeRequest{RecipeID: recipeID}
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/collections/" + collectionID.String() + "/recipes", body: body, auth: authAccess}, nil)
+}
+
+func (c *Client) RemoveRecipeFromCollection(ctx context.Context, collectionID, recipeID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/collections/" + collectionID.String() + "/recipes/" + recipeID.String(), auth: authAccess}, nil)
+}
+
+// CollectionCookbook returns the recipes of a collection as a book in the
+// given format, "pdf" or "epub".
+func (c *Client) CollectionCookbook(ctx context.Context, collectionID uuid.UUID, format string) ([]byte, error) {
+	var data []byte
+	q := url.Values{}
+	if format != "" {
+		q.Set("format", format)
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/collections/" + collectionID.String() + "/cookbook", query: q, auth: authAccess}, &data)
+	return data, err
+}
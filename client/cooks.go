@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// CookEvents returns the cook log of a recipe, most recent first.
+func (c *Client) CookEvents(ctx context.Context, recipeID uuid.UUID) ([]CookEvent, error) {
+	var es []CookEvent
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/" + recipeID.String() + "/cooks", auth: authAccess}, &es)
+	return es, err
+}
+
+// LogCook records a time the recipe was cooked. CookedAt defaults to now.
+func (c *Client) LogCook(ctx context.Context, recipeID uuid.UUID, cr CookEventRequest) (CookEvent, error) {
+	var e CookEvent
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/recipes/" + recipeID.String() + "/cooks", body: cr, auth: authAccess}, &e)
+	return e, err
+}
+
+func (c *Client) UpdateCookEvent(ctx context.Context, recipeID, eventID uuid.UUID, cr CookEventRequest) (CookEvent, error) {
+	var e CookEvent
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/recipes/" + recipeID.String() + "/cooks/" + eventID.String(), body: cr, auth: authAccess}, &e)
+	return e, err
+}
+
+func (c *Client) DeleteCookEvent(ctx context.Context, recipeID, eventID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/recipes/" + recipeID.String() + "/cooks/" + eventID.String(), auth: authAccess}, nil)
+}
+
+// SetFavorite marks a recipe as a favorite, or not.
+func (c *Client) SetFavorite(ctx context.Context, recipeID uuid.UUID, favorite bool) error {
+	body := FavoriteRequest{Favorite: favorite}
+	return c.do(ctx, request{method: http.MethodPut, path: "/v1/recipes/" + recipeID.String() + "/favorite", body: body, auth: authAccess}, nil)
+}
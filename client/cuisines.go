@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateCuisine(ctx context.Context, cr CuisineRequest) (Cuisine, error) {
+	var cu Cuisine
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/cuisines", body: cr, auth: authAccess}, &cu)
+	return cu, err
+}
+
+func (c *Client) ListCuisines(ctx context.Context) ([]Cuisine, error) {
+	var cs []Cuisine
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/cuisines", auth: authAccess}, &cs)
+	return cs, err
+}
+
+func (c *Client) UpdateCuisine(ctx context.Context, cuisineID uuid.UUID, cr CuisineRequest) (Cuisine, error) {
+	var cu Cuisine
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/cuisines/" + cuisineID.String(), body: cr, auth: authAccess}, &cu)
+	return cu, err
+}
+
+func (c *Client) DeleteCuisine(ctx context.Context, cuisineID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/cuisines/" + cuisineID.String(), auth: authAccess}, nil)
+}
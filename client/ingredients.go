@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateIngredient(ctx context.Context, ir IngredientRequest) (Ingredient, error) {
+	var i Ingredient
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/ingredients", body: ir, auth: authAccess}, &i)
+	return i, err
+}
+
+func (c *Client) ListIngredients(ctx context.Context) ([]Ingredient, error) {
+	var is []Ingredient
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/ingredients", auth: authAccess}, &is)
+	return is, err
+}
+
+func (c *Client) UpdateIngredient(ctx context.Context, ingredientID uuid.UUID, ir IngredientRequest) (Ingredient, error) {
+	var i Ingredient
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/ingredients/" + ingredientID.String(), body: ir, auth: authAccess}, &i)
+	return i, err
+}
+
+func (c *Client) DeleteIngredient(ctx context.Context, ingredientID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/ingredients/" + ingredientID.String(), auth: authAccess}, nil)
+}
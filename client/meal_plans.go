@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// SuggestMealPlan drafts a plan within the constraints of mr. The draft is
+// not saved until passed to CreateMealPlan, see MealPlanRequestFromDraft.
+func (c *Client) SuggestMealPlan(ctx context.Context, mr MealPlanSuggestRequest) (MealPlanDraft, error) {
+	var draft MealPlanDraft
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/meal-plans/suggest", body: mr, auth: authAccess}, &draft)
+	return draft, err
+}
+
+func (c *Client) CreateMealPlan(ctx context.Context, mr MealPlanRequest) (MealPlan, error) {
+	var plan MealPlan
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/meal-plans", body: mr, auth: authAccess}, &plan)
+	return plan, err
+}
+
+// ListMealPlans lists the plans of the user, without their meals.
+func (c *Client) ListMealPlans(ctx context.Context) ([]MealPlan, error) {
+	var plans []MealPlan
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/meal-plans", auth: authAccess}, &plans)
+	return plans, err
+}
+
+func (c *Client) GetMealPlan(ctx context.Context, planID uuid.UUID) (MealPlan, error) {
+	var plan MealPlan
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/meal-plans/" + planID.String(), auth: authAccess}, &plan)
+	return plan, err
+}
+
+// UpdateMealPlan replaces a plan and its meals.
+func (c *Client) UpdateMealPlan(ctx context.Context, planID uuid.UUID, mr MealPlanRequest) (MealPlan, error) {
+	var plan MealPlan
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/meal-plans/" + planID.String(), body: mr, auth: authAccess}, &plan)
+	return plan, err
+}
+
+func (c *Client) DeleteMealPlan(ctx context.Context, planID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/meal-plans/" + planID.String(), auth: authAccess}, nil)
+}
+
+// GetCalendarFeed returns the iCalendar subscription of the meal plans of
+// the user.
+func (c *Client) GetCalendarFeed(ctx context.Context) (CalendarFeed, error) {
+	var feed CalendarFeed
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/meal-plans/calendar", auth: authAccess}, &feed)
+	return feed, err
+}
+
+// ResetCalendarFeed makes the calendar subscription, or moves it to a new
+// secret URL.
+func (c *Client) ResetCalendarFeed(ctx context.Context) (CalendarFeed, error) {
+	var feed CalendarFeed
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/meal-plans/calendar", auth: authAccess}, &feed)
+	return feed, err
+}
+
+func (c *Client) DeleteCalendarFeed(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/meal-plans/calendar", auth: authAccess}, nil)
+}
+
+// MealPlanRequestFromDraft turns a suggested plan into the request that
+// saves it as is.
+func MealPlanRequestFromDraft(name string, draft MealPlanDraft) MealPlanRequest {
+	mr := MealPlanRequest{Name: name, StartDate: draft.StartDate}
+	for _, m := range draft.Meals {
+		mr.Meals = append(mr.Meals, PlannedMealRequest{Date: m.Date, RecipeID: m.RecipeID, Time: m.Time})
+	}
+	return mr
+}
@@ -0,0 +1,76 @@
+package client
+
+import "github.com/quangd42/meal-org/internal/models"
+
+// Request and response types are shared with the server. They are aliased
+// here so that code outside this module can name them.
+type (
+	CreateUserRequest = models.CreateUserRequest
+	UpdateUserRequest = models.UpdateUserRequest
+	LoginRequest      = models.LoginRequest
+	User              = models.User
+	UserWithToken     = models.UserWithToken
+
+	RecipeRequest       = models.RecipeRequest
+	Recipe              = models.Recipe
+	RecipeInList        = models.RecipeInList
+	CuisineInRecipe     = models.CuisineInRecipe
+	IngredientInRecipe  = models.IngredientInRecipe
+	InstructionInRecipe = models.InstructionInRecipe
+	Job                 = models.Job
+	RecipeRevision      = models.RecipeRevision
+	RecipeDiff          = models.RecipeDiff
+	FieldChange         = models.FieldChange
+	ImportReport        = models.ImportReport
+	ImportResult        = models.ImportResult
+	RecipeTextRequest   = models.RecipeTextRequest
+	RecipeDraft         = models.RecipeDraft
+	CookbookRequest     = models.CookbookRequest
+	CookEventRequest    = models.CookEventRequest
+	CookEvent           = models.CookEvent
+	FavoriteRequest     = models.FavoriteRequest
+
+	IngredientRequest = models.IngredientRequest
+	Ingredient        = models.Ingredient
+
+	CuisineRequest = models.CuisineRequest
+	Cuisine        = models.Cuisine
+
+	TagRequest        = models.TagRequest
+	RecipeTagsRequest = models.RecipeTagsRequest
+	Tag               = models.Tag
+
+	CollectionRequest        = models.CollectionRequest
+	CollectionFilter         = models.CollectionFilter
+	CollectionRecipesRequest = models.CollectionRecipesRequest
+	CollectionRecipeRequest  = models.CollectionRecipeRequest
+	Collection               = models.Collection
+
+	Date                   = models.Date
+	MealPlanSuggestRequest = models.MealPlanSuggestRequest
+	MealPlanDraft          = models.MealPlanDraft
+	MealPlanRequest        = models.MealPlanRequest
+	PlannedMealRequest     = models.PlannedMealRequest
+	MealPlan               = models.MealPlan
+	PlannedMeal            = models.PlannedMeal
+	CalendarFeed           = models.CalendarFeed
+
+	PantryItemRequest = models.PantryItemRequest
+	PantryItem        = models.PantryItem
+	CookableRecipe    = models.CookableRecipe
+)
+
+// Orders of ListRecipesSorted.
+const (
+	SortByName        = models.RecipeSortName
+	SortByLastCooked  = models.RecipeSortLastCooked
+	SortByTimesCooked = models.RecipeSortTimesCooked
+	SortByRating      = models.RecipeSortRating
+	SortByFavorite    = models.RecipeSortFavorite
+)
+
+// Content types of PatchRecipe.
+const (
+	MergePatch = models.MergePatchContentType
+	JSONPatch  = models.JSONPatchContentType
+)
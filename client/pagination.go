@@ -0,0 +1,61 @@
+package client
+
+import "context"
+
+// RecipeIterator walks through pages of recipes. Use it like bufio.Scanner:
+//
+//	it := c.Recipes(ctx, 50)
+//	for it.Next() {
+//		r := it.Recipe()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type RecipeIterator struct {
+	c        *Client
+	ctx      context.Context
+	pageSize int
+
+	offset int
+	page   []RecipeInList
+	cur    RecipeInList
+	done   bool
+	err    error
+}
+
+// Next advances to the next recipe, fetching a new page when needed. It
+// returns false when there are no more recipes or an error occurred.
+func (it *RecipeIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 {
+		if it.done {
+			return false
+		}
+		page, err := it.c.ListRecipes(it.ctx, it.pageSize, it.offset)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.offset += len(page)
+		if len(page) < it.pageSize {
+			it.done = true
+		}
+		if len(page) == 0 {
+			return false
+		}
+		it.page = page
+	}
+
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *RecipeIterator) Recipe() RecipeInList {
+	return it.cur
+}
+
+func (it *RecipeIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ListPantryItems lists what the user has in stock, what expires first
+// first.
+func (c *Client) ListPantryItems(ctx context.Context) ([]PantryItem, error) {
+	var items []PantryItem
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/pantry", auth: authAccess}, &items)
+	return items, err
+}
+
+func (c *Client) CreatePantryItem(ctx context.Context, pr PantryItemRequest) (PantryItem, error) {
+	var item PantryItem
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/pantry", body: pr, auth: authAccess}, &item)
+	return item, err
+}
+
+func (c *Client) UpdatePantryItem(ctx context.Context, itemID uuid.UUID, pr PantryItemRequest) (PantryItem, error) {
+	var item PantryItem
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/pantry/" + itemID.String(), body: pr, auth: authAccess}, &item)
+	return item, err
+}
+
+func (c *Client) DeletePantryItem(ctx context.Context, itemID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/pantry/" + itemID.String(), auth: authAccess}, nil)
+}
+
+// ListCookableRecipes ranks the recipes of the user by what of them the
+// pantry has, those using items expiring within expiringWithin days first.
+func (c *Client) ListCookableRecipes(ctx context.Context, expiringWithin, limit int) ([]CookableRecipe, error) {
+	var rs []CookableRecipe
+	q := url.Values{}
+	q.Set("expiring_within", strconv.Itoa(expiringWithin))
+	q.Set("limit", strconv.Itoa(limit))
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/cookable", query: q, auth: authAccess}, &rs)
+	return rs, err
+}
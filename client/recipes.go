@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateRecipe(ctx context.Context, rr RecipeRequest) (Recipe, error) {
+	var r Recipe
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/recipes", body: rr, auth: authAccess}, &r)
+	return r, err
+}
+
+func (c *Client) GetRecipe(ctx context.Context, recipeID uuid.UUID) (Recipe, error) {
+	var r Recipe
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/" + recipeID.String(), auth: authAccess}, &r)
+	return r, err
+}
+
+// UpdateRecipe updates the recipe only if it is still at version, the
+// Version of the recipe the changes were made to. Otherwise it fails with a
+// 412 APIError, see IsStatus. An empty version updates the recipe whatever
+// its version.
+func (c *Client) UpdateRecipe(ctx context.Context, recipeID uuid.UUID, rr RecipeRequest, version string) (Recipe, error) {
+	var r Recipe
+	err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   "/v1/recipes/" + recipeID.String(),
+		body:   rr,
+		header: http.Header{"If-Match": {ifMatch(version)}},
+		auth:   authAccess,
+	}, &r)
+	return r, err
+}
+
+// PatchRecipe applies patch, encoded as JSON, to the recipe. contentType is
+// MergePatch or JSONPatch, and version works as in UpdateRecipe. Patches
+// address ingredients by index and instructions by step number, such as
+// {"instructions": {"2": "Bake for 20 minutes"}}.
+func (c *Client) PatchRecipe(ctx context.Context, recipeID uuid.UUID, contentType string, patch any, version string) (Recipe, error) {
+	var r Recipe
+	err := c.do(ctx, request{
+		method: http.MethodPatch,
+		path:   "/v1/recipes/" + recipeID.String(),
+		body:   patch,
+		header: http.Header{"If-Match": {ifMatch(version)}, "Content-Type": {contentType}},
+		auth:   authAccess,
+	}, &r)
+	return r, err
+}
+
+func ifMatch(version string) string {
+	if version == "" {
+		return "*"
+	}
+	return `"` + version + `"`
+}
+
+// DeleteRecipe moves a recipe to the trash, from where it can be restored.
+func (c *Client) DeleteRecipe(ctx context.Context, recipeID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/recipes/" + recipeID.String(), auth: authAccess}, nil)
+}
+
+// DeletedRecipes returns the recipes in the trash, most recently deleted
+// first.
+func (c *Client) DeletedRecipes(ctx context.Context) ([]RecipeInList, error) {
+	var rs []RecipeInList
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/trash", auth: authAccess}, &rs)
+	return rs, err
+}
+
+func (c *Client) RestoreDeletedRecipe(ctx context.Context, recipeID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/v1/recipes/trash/" + recipeID.String() + "/restore", auth: authAccess}, nil)
+}
+
+// DeleteRecipeForever removes a recipe in the trash for good.
+func (c *Client) DeleteRecipeForever(ctx context.Context, recipeID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/recipes/trash/" + recipeID.String(), auth: authAccess}, nil)
+}
+
+// ExportRecipe returns a recipe as a file in the given format, such as
+// "markdown" or "cooklang". An empty format means JSON.
+func (c *Client) ExportRecipe(ctx context.Context, recipeID uuid.UUID, format string) ([]byte, error) {
+	var data []byte
+	q := url.Values{}
+	if format != "" {
+		q.Set("format", format)
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/" + recipeID.String() + "/export", query: q, auth: authAccess}, &data)
+	return data, err
+}
+
+// ExportRecipes returns every recipe as a zip archive, one file per recipe
+// in the given format.
+func (c *Client) ExportRecipes(ctx context.Context, format string) ([]byte, error) {
+	var data []byte
+	q := url.Values{}
+	if format != "" {
+		q.Set("format", format)
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/export", query: q, auth: authAccess}, &data)
+	return data, err
+}
+
+// Cookbook returns the selected recipes as a book in the given format, "pdf"
+// or "epub". Every recipe goes in when arg has no recipe IDs.
+func (c *Client) Cookbook(ctx context.Context, format string, arg CookbookRequest) ([]byte, error) {
+	var data []byte
+	q := url.Values{}
+	if format != "" {
+		q.Set("format", format)
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/recipes/cookbook", query: q, body: arg, auth: authAccess}, &data)
+	return data, err
+}
+
+// ImportRecipes creates recipes from an export of another recipe manager,
+// such as a Paprika archive or a CSV file. The format is detected from the
+// file name and content when empty. Recipes imported before are skipped.
+func (c *Client) ImportRecipes(ctx context.Context, filename, format string, data []byte) (ImportReport, error) {
+	var report ImportReport
+	q := url.Values{}
+	q.Set("filename", filename)
+	if format != "" {
+		q.Set("format", format)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/recipes/import/bulk", query: q, body: data, header: header, auth: authAccess}, &report)
+	return report, err
+}
+
+// ParseRecipe reads a recipe pasted as text into a draft. The draft is not
+// saved: it lacks the cuisines, and the ingredients listed as Unmatched have
+// no ID yet.
+func (c *Client) ParseRecipe(ctx context.Context, text string) (RecipeDraft, error) {
+	var draft RecipeDraft
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/recipes/parse", body: RecipeTextRequest{Text: text}, auth: authAccess}, &draft)
+	return draft, err
+}
+
+// RecipeJobs returns the latest background jobs of a recipe. A job that
+// IsActive means its work, such as fetching the preview image, is underway.
+func (c *Client) RecipeJobs(ctx context.Context, recipeID uuid.UUID) ([]Job, error) {
+	var jobs []Job
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/" + recipeID.String() + "/jobs", auth: authAccess}, &jobs)
+	return jobs, err
+}
+
+// ListRecipes returns a single page of the authenticated user's recipes.
+func (c *Client) ListRecipes(ctx context.Context, limit, offset int) ([]RecipeInList, error) {
+	return c.ListRecipesSorted(ctx, SortByName, limit, offset)
+}
+
+// ListRecipesSorted returns a single page of the authenticated user's
+// recipes in the given order, one of the SortBy constants.
+func (c *Client) ListRecipesSorted(ctx context.Context, sort string, limit, offset int) ([]RecipeInList, error) {
+	var rs []RecipeInList
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("offset", strconv.Itoa(offset))
+	if sort != "" && sort != SortByName {
+		q.Set("sort", sort)
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes", query: q, auth: authAccess}, &rs)
+	return rs, err
+}
+
+// Recipes returns an iterator over all of the authenticated user's recipes,
+// fetching pageSize recipes per request.
+func (c *Client) Recipes(ctx context.Context, pageSize int) *RecipeIterator {
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	return &RecipeIterator{c: c, ctx: ctx, pageSize: pageSize}
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// RecipeRevisions returns the revisions of a recipe, latest first, each with
+// its changes from the one before.
+func (c *Client) RecipeRevisions(ctx context.Context, recipeID uuid.UUID) ([]RecipeRevision, error) {
+	var revs []RecipeRevision
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/" + recipeID.String() + "/revisions", auth: authAccess}, &revs)
+	return revs, err
+}
+
+func (c *Client) RecipeRevision(ctx context.Context, recipeID uuid.UUID, revision int) (RecipeRevision, error) {
+	var rev RecipeRevision
+	path := fmt.Sprintf("/v1/recipes/%s/revisions/%d", recipeID, revision)
+	err := c.do(ctx, request{method: http.MethodGet, path: path, auth: authAccess}, &rev)
+	return rev, err
+}
+
+// DiffRecipeRevisions lists the fields that changed between two revisions.
+func (c *Client) DiffRecipeRevisions(ctx context.Context, recipeID uuid.UUID, from, to int) (RecipeDiff, error) {
+	var diff RecipeDiff
+	q := url.Values{}
+	q.Set("from", strconv.Itoa(from))
+	q.Set("to", strconv.Itoa(to))
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/" + recipeID.String() + "/revisions/diff", query: q, auth: authAccess}, &diff)
+	return diff, err
+}
+
+// RestoreRecipeRevision brings a recipe back to a revision, which saves a new
+// revision.
+func (c *Client) RestoreRecipeRevision(ctx context.Context, recipeID uuid.UUID, revision int) (Recipe, error) {
+	var r Recipe
+	path := fmt.Sprintf("/v1/recipes/%s/revisions/%d/restore", recipeID, revision)
+	err := c.do(ctx, request{method: http.MethodPost, path: path, auth: authAccess}, &r)
+	return r, err
+}
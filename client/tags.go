@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// ListTags lists the tags of the user. With a prefix, it only suggests the
+// few tags starting with it.
+func (c *Client) ListTags(ctx context.Context, prefix string) ([]Tag, error) {
+	var ts []Tag
+	q := url.Values{}
+	if prefix != "" {
+		q.Set("q", prefix)
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/tags", query: q, auth: authAccess}, &ts)
+	return ts, err
+}
+
+func (c *Client) CreateTag(ctx context.Context, tr TagRequest) (Tag, error) {
+	var t Tag
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/tags", body: tr, auth: authAccess}, &t)
+	return t, err
+}
+
+func (c *Client) UpdateTag(ctx context.Context, tagID uuid.UUID, tr TagRequest) (Tag, error) {
+	var t Tag
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/tags/" + tagID.String(), body: tr, auth: authAccess}, &t)
+	return t, err
+}
+
+func (c *Client) DeleteTag(ctx context.Context, tagID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/tags/" + tagID.String(), auth: authAccess}, nil)
+}
+
+func (c *Client) RecipeTags(ctx context.Context, recipeID uuid.UUID) ([]Tag, error) {
+	var ts []Tag
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recipes/" + recipeID.String() + "/tags", auth: authAccess}, &ts)
+	return ts, err
+}
+
+// SetRecipeTags replaces the tags of a recipe, creating those the user
+// doesn't have yet.
+func (c *Client) SetRecipeTags(ctx context.Context, recipeID uuid.UUID, tags []string) ([]Tag, error) {
+	var ts []Tag
+	body := RecipeTagsRequest{Tags: tags}
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/recipes/" + recipeID.String() + "/tags", body: body, auth: authAccess}, &ts)
+	return ts, err
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CreateUser registers a new user and keeps the returned tokens.
+func (c *Client) CreateUser(ctx context.Context, ur CreateUserRequest) (UserWithToken, error) {
+	var u UserWithToken
+	err := c.do(ctx, request{method: http.MethodPost, path: "/v1/users", body: ur}, &u)
+	if err != nil {
+		return u, err
+	}
+	c.setTokens(u.Token, u.RefreshToken)
+	return u, nil
+}
+
+func (c *Client) UpdateUser(ctx context.Context, ur UpdateUserRequest) (User, error) {
+	var u User
+	err := c.do(ctx, request{method: http.MethodPut, path: "/v1/users", body: ur, auth: authAccess}, &u)
+	return u, err
+}
+
+// DeleteUser deletes the authenticated user and all of their data.
+func (c *Client) DeleteUser(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/users", auth: authAccess}, nil)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// backupCmd and restoreCmd wrap pg_dump and pg_restore, which have to be
+// installed on the machine running them.
+
+func backupCmd(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	out := fs.String("out", fmt.Sprintf("mealorg-%s.dump", time.Now().UTC().Format("20060102-150405")), "file to write the dump to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	dbURL, err := databaseURL()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(context.Background(), "pg_dump", "--format=custom", "--no-owner", "--file", *out, dbURL) // #nosec G204
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pg_dump failed: %w", err)
+	}
+
+	fmt.Printf("database dumped to %s\n", *out)
+	return nil
+}
+
+func restoreCmd(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	in := fs.String("in", "", "dump file written by backup")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return fmt.Errorf("%w: -in is required", errUsage)
+	}
+
+	dbURL, err := databaseURL()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(context.Background(), "pg_restore", "--clean", "--if-exists", "--no-owner", "--dbname", dbURL, *in) // #nosec G204
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pg_restore failed: %w", err)
+	}
+
+	fmt.Printf("database restored from %s\n", *in)
+	return nil
+}
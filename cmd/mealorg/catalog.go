@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+//go:embed data/cuisines.csv
+var catalogData embed.FS
+
+func catalogCmd(args []string) error {
+	_, args, err := subcommand(args, "seed")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("catalog seed", flag.ContinueOnError)
+	file := fs.String("file", "", "CSV file of name,parent rows, defaults to the bundled cuisines")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var r io.Reader
+	if *file != "" {
+		f, err := os.Open(*file) // #nosec G304
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	} else {
+		f, err := catalogData.Open("data/cuisines.csv")
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	ctx := context.Background()
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.DB.Close()
+
+	rs, err := newRecipeService(store)
+	if err != nil {
+		return err
+	}
+	return seedCuisines(ctx, rs, r)
+}
+
+// seedCuisines creates the cuisines listed in r. Parents must be listed before
+// their children. Cuisines that already exist are left untouched, so seeding
+// twice is safe.
+func seedCuisines(ctx context.Context, rs services.RecipeService, r io.Reader) error {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	var created, skipped int
+	for i, row := range records {
+		if i == 0 {
+			continue // Skip header row
+		}
+		if len(row) < 2 {
+			return fmt.Errorf("line %d: expected name,parent", i+1)
+		}
+		name, parentName := row[0], row[1]
+
+		_, err := rs.GetCuisineByName(ctx, name)
+		if err == nil {
+			skipped++
+			continue
+		}
+		if !errors.Is(err, services.ErrResourceNotFound) {
+			return err
+		}
+
+		var parentID *uuid.UUID
+		if parentName != "" {
+			parent, err := rs.GetCuisineByName(ctx, parentName)
+			if err != nil {
+				return fmt.Errorf("line %d: parent %q: %w", i+1, parentName, err)
+			}
+			parentID = &parent.ID
+		}
+
+		_, err = rs.CreateCuisine(ctx, models.CuisineRequest{Name: name, ParentID: parentID})
+		if err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+		created++
+	}
+
+	fmt.Printf("cuisines seeded: %d created, %d already present\n", created, skipped)
+	return nil
+}
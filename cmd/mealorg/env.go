@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/quangd42/meal-org/internal/blob"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/fetcher"
+	"github.com/quangd42/meal-org/internal/planner"
+	"github.com/quangd42/meal-org/internal/services"
+	"github.com/quangd42/meal-org/internal/tracing"
+)
+
+func loadEnv() error {
+	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+		return errors.New("error loading env")
+	}
+	return nil
+}
+
+// envInt reads an integer setting, falling back to def when it is unset or
+// invalid.
+func envInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// envDuration reads a duration setting such as "720h", falling back to def
+// when it is unset or invalid.
+func envDuration(key string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func databaseURL() (string, error) {
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		return "", errors.New("missing env settings: DATABASE_URL")
+	}
+	return dbURL, nil
+}
+
+// openStore connects to the database shared by the server and every other
+// command. The caller is responsible for closing store.DB.
+func openStore(ctx context.Context) (*database.Store, error) {
+	dbURL, err := databaseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
+	cfg.ConnConfig.Tracer = tracing.QueryTracer{}
+
+	db, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+
+	return database.NewStore(db), nil
+}
+
+// newRecipeService wires the recipe service with the fetcher for external
+// content, the store of cached images at $IMAGE_DIR or data/images, and the
+// meal planner.
+func newRecipeService(store *database.Store) (services.RecipeService, error) {
+	dir := os.Getenv("IMAGE_DIR")
+	if dir == "" {
+		dir = "data/images"
+	}
+	blobs, err := blob.NewDiskStore(dir)
+	if err != nil {
+		return services.RecipeService{}, fmt.Errorf("unable to open image store: %w", err)
+	}
+	return services.NewRecipeService(store, fetcher.New(), blobs, planner.Suggester{}), nil
+}
+
+// subcommand splits args into the name of a nested command and its arguments.
+func subcommand(args []string, names ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%w: expected one of %v", errUsage, names)
+	}
+	for _, n := range names {
+		if args[0] == n {
+			return n, args[1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w: unknown command %q, expected one of %v", errUsage, args[0], names)
+}
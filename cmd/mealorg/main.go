@@ -0,0 +1,71 @@
+// Command mealorg runs the meal-org web server and its admin tasks.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/quangd42/meal-org/internal/logging"
+)
+
+const usage = `Usage: mealorg <command> [arguments]
+
+Commands:
+  serve                           start the web server
+  migrate up|down|status          manage the database schema
+  user create|promote|delete      manage users
+  recipe import|export            copy a user's recipes in or out as JSON
+  catalog seed                    populate the cuisines catalog
+  backup                          dump the database with pg_dump
+  restore                         restore a dump with pg_restore
+
+Run "mealorg <command> -h" for the arguments of a command.
+`
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+		}
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if err := loadEnv(); err != nil {
+		return err
+	}
+	slog.SetDefault(logging.New(os.Stderr))
+
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "serve":
+		return serveCmd(args)
+	case "migrate":
+		return migrateCmd(args)
+	case "user":
+		return userCmd(args)
+	case "recipe":
+		return recipeCmd(args)
+	case "catalog":
+		return catalogCmd(args)
+	case "backup":
+		return backupCmd(args)
+	case "restore":
+		return restoreCmd(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, cmd)
+	}
+}
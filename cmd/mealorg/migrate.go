@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+func migrateCmd(args []string) error {
+	cmd, _, err := subcommand(args, "up", "down", "status")
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.DB.Close()
+
+	ms, err := services.NewMigrationService(store)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "up":
+		results, err := ms.Up(ctx)
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			fmt.Printf("applied %s (%s)\n", r.Source.Path, r.Duration.Round(time.Millisecond))
+		}
+		if len(results) == 0 {
+			fmt.Println("no migrations to apply")
+		}
+
+	case "down":
+		r, err := ms.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %s\n", r.Source.Path)
+
+	case "status":
+		statuses, err := ms.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if !s.AppliedAt.IsZero() {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-30s %s\n", s.Source.Path, appliedAt)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+func recipeCmd(args []string) error {
+	cmd, args, err := subcommand(args, "import", "export")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("recipe "+cmd, flag.ContinueOnError)
+	email := fs.String("email", "", "email of the user owning the recipes")
+	file := fs.String("file", "-", "JSON file to read from or write to, - for stdin/stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("%w: -email is required", errUsage)
+	}
+
+	ctx := context.Background()
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.DB.Close()
+
+	us := services.NewUserService(store)
+	rs, err := newRecipeService(store)
+	if err != nil {
+		return err
+	}
+
+	user, err := getUserByEmail(ctx, us, *email)
+	if err != nil {
+		return err
+	}
+
+	if cmd == "export" {
+		return exportRecipes(ctx, rs, user, *file)
+	}
+	return importRecipes(ctx, rs, user, *file)
+}
+
+func exportRecipes(ctx context.Context, rs services.RecipeService, user models.User, file string) error {
+	recipes, err := rs.ExportRecipesByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if file != "-" {
+		f, err := os.Create(file) // #nosec G304
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(recipes); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d recipes of %s\n", len(recipes), user.Email)
+	return nil
+}
+
+// importRecipes reads recipes in the format written by export. A recipe that
+// fails is reported and skipped so that one bad entry doesn't stop the rest,
+// and the import fails at the end when any did.
+func importRecipes(ctx context.Context, rs services.RecipeService, user models.User, file string) error {
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file) // #nosec G304
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var recipes []models.Recipe
+	if err := json.NewDecoder(r).Decode(&recipes); err != nil {
+		return fmt.Errorf("cannot decode recipes: %w", err)
+	}
+
+	var failed int
+	for _, recipe := range recipes {
+		rr, err := rs.RecipeRequestFromRecipe(ctx, recipe)
+		if err == nil {
+			err = rr.Validate(ctx)
+		}
+		if err == nil {
+			_, err = rs.CreateRecipe(ctx, user.ID, rr)
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "skipped %q: %s\n", recipe.Name, err)
+		}
+	}
+
+	// The external images are fetched by the job workers of the server
+	fmt.Fprintf(os.Stderr, "imported %d of %d recipes for %s\n", len(recipes)-failed, len(recipes), user.Email)
+	if failed > 0 {
+		return fmt.Errorf("%d recipes were skipped", failed)
+	}
+	return nil
+}
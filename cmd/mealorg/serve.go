@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alexedwards/scs/pgxstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/quangd42/meal-org/internal/handlers"
+	"github.com/quangd42/meal-org/internal/metrics"
+	"github.com/quangd42/meal-org/internal/services"
+	"github.com/quangd42/meal-org/internal/tracing"
+)
+
+const shutdownTimeout = 20 * time.Second
+
+func serveCmd(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	port := fs.String("port", os.Getenv("PORT"), "port to listen on, defaults to $PORT or 8080")
+	workers := fs.Int("workers", envInt("JOB_WORKERS", 2), "number of background job workers, defaults to $JOB_WORKERS or 2")
+	trashRetention := fs.Duration("trash-retention", envDuration("TRASH_RETENTION", services.DefaultTrashRetention), "how long deleted recipes are kept in the trash, defaults to $TRASH_RETENTION or 720h")
+	jobRetention := fs.Duration("job-retention", envDuration("JOB_RETENTION", services.DefaultJobRetention), "how long finished background jobs are kept, defaults to $JOB_RETENTION or 168h")
+	metricsPort := fs.String("metrics-port", os.Getenv("METRICS_PORT"), "serve /metrics on a separate port, defaults to $METRICS_PORT or the main port")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *port == "" {
+		*port = "8080"
+	}
+
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		return errors.New("missing env settings: jwtSecret")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		return fmt.Errorf("error setting up tracing: %w", err)
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.DB.Close()
+
+	us := services.NewUserService(store)
+	as := services.NewAuthService(store, jwtSecret)
+	rs, err := newRecipeService(store)
+	if err != nil {
+		return err
+	}
+	js := services.NewJobService(store)
+	js.Handle(services.JobFetchExternalImage, rs.FetchExternalImage)
+	js.Handle(services.JobCacheRecipeImage, rs.CacheRecipeImage)
+	js.Schedule("refresh_stale_images", time.Hour, rs.RefreshStaleImages)
+	js.Schedule("purge_deleted_recipes", time.Hour, func(ctx context.Context) error {
+		return rs.PurgeDeletedRecipes(ctx, *trashRetention)
+	})
+	js.Schedule("delete_finished_jobs", time.Hour, func(ctx context.Context) error {
+		return js.DeleteFinishedJobs(ctx, *jobRetention)
+	})
+	rds := services.NewRendererService()
+	sm := services.NewSessionManager(store)
+	ms, err := services.NewMigrationService(store)
+	if err != nil {
+		return err
+	}
+	hs := services.NewHealthService(store, ms, sm.Store)
+	metrics.RegisterDB(store)
+
+	if strings.ToLower(os.Getenv("MIGRATE_ON_START")) == "true" {
+		results, err := ms.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("error migrating database: %w", err)
+		}
+		slog.Info("database migrated", "applied", len(results))
+	}
+
+	r := chi.NewRouter()
+	handlers.AddRoutes(r, sm, rds, us, as, rs, hs)
+
+	server := &http.Server{
+		Addr:         ":" + *port,
+		Handler:      r,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	// Keep the metrics off the public port when a separate one is given
+	var metricsServer *http.Server
+	if *metricsPort == "" || *metricsPort == *port {
+		r.Handle("/metrics", metrics.Handler())
+	} else {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:         ":" + *metricsPort,
+			Handler:      mux,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		}
+	}
+
+	js.Start(ctx, *workers)
+
+	serverErr := make(chan error, 2)
+	go func() {
+		slog.Info("listening", "port", *port)
+		serverErr <- server.ListenAndServe()
+	}()
+	if metricsServer != nil {
+		go func() {
+			slog.Info("serving metrics", "port", *metricsPort)
+			serverErr <- metricsServer.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("error listening and serving: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Stop listening for signals so that a second one kills the process
+	stop()
+	slog.Info("shutting down")
+	hs.SetShuttingDown()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("error shutting down server: %w", err)
+	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("error shutting down metrics server: %w", err)
+		}
+	}
+	if err := js.Wait(shutdownCtx); err != nil {
+		return fmt.Errorf("error waiting for background jobs: %w", err)
+	}
+	if s, ok := sm.Store.(*pgxstore.PostgresStore); ok {
+		s.StopCleanup()
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		return fmt.Errorf("error flushing traces: %w", err)
+	}
+
+	slog.Info("server stopped")
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+func userCmd(args []string) error {
+	cmd, args, err := subcommand(args, "create", "promote", "delete")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("user "+cmd, flag.ContinueOnError)
+	email := fs.String("email", "", "email of the user")
+	password := fs.String("password", "", "password of the new user (create only)")
+	admin := fs.Bool("admin", false, "make the new user an admin (create only)")
+	revoke := fs.Bool("revoke", false, "remove admin rights instead of granting them (promote only)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("%w: -email is required", errUsage)
+	}
+
+	ctx := context.Background()
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.DB.Close()
+
+	us := services.NewUserService(store)
+
+	switch cmd {
+	case "create":
+		ur := models.CreateUserRequest{Email: *email, Password: *password}
+		if err := ur.Validate(ctx); err != nil {
+			return err
+		}
+		user, err := us.CreateUser(ctx, ur)
+		if err != nil {
+			return err
+		}
+		if *admin {
+			if err := us.UpdateUserAdminByID(ctx, user.ID, true); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("created user %s (%s)\n", user.Email, user.ID)
+
+	case "promote":
+		user, err := getUserByEmail(ctx, us, *email)
+		if err != nil {
+			return err
+		}
+		if err := us.UpdateUserAdminByID(ctx, user.ID, !*revoke); err != nil {
+			return err
+		}
+		if *revoke {
+			fmt.Printf("%s is no longer an admin\n", user.Email)
+		} else {
+			fmt.Printf("%s is now an admin\n", user.Email)
+		}
+
+	case "delete":
+		user, err := getUserByEmail(ctx, us, *email)
+		if err != nil {
+			return err
+		}
+		if err := us.DeleteUserByID(ctx, user.ID); err != nil {
+			return err
+		}
+		fmt.Printf("deleted user %s\n", user.Email)
+	}
+
+	return nil
+}
+
+func getUserByEmail(ctx context.Context, us services.UserService, email string) (models.User, error) {
+	user, err := us.GetUserByEmail(ctx, email)
+	if errors.Is(err, services.ErrResourceNotFound) {
+		return user, fmt.Errorf("no user with email %s", email)
+	}
+	return user, err
+}
@@ -68,3 +68,13 @@ func GenerateRefreshToken() (string, error) {
 	tokenString := base64.StdEncoding.EncodeToString(b)
 	return tokenString, nil
 }
+
+// GenerateURLToken returns a random secret safe to put in a URL.
+func GenerateURLToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,95 @@
+// Package blob stores files, such as cached images, behind an interface so
+// that the disk can be swapped for an object store.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob: not found")
+	ErrInvalidKey = errors.New("blob: invalid key")
+)
+
+// Store holds blobs by slash separated keys, such as "recipes/<id>/card.jpg".
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+	// Delete removes the blob at key, or every blob under it. Deleting a
+	// missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// DiskStore keeps blobs as files under a root directory.
+type DiskStore struct {
+	root string
+}
+
+var _ Store = DiskStore{}
+
+func NewDiskStore(root string) (DiskStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return DiskStore{}, err
+	}
+	return DiskStore{root: root}, nil
+}
+
+func (s DiskStore) path(key string) (string, error) {
+	if key == "" || !fs.ValidPath(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (s DiskStore) Get(ctx context.Context, key string) ([]byte, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Put writes to a temporary file first, so that readers never see a partial
+// blob.
+func (s DiskStore) Put(ctx context.Context, key string, data []byte) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+func (s DiskStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(strings.TrimSuffix(key, "/"))
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(p)
+}
@@ -0,0 +1,218 @@
+// Package calendar writes planned meals as an iCalendar feed (RFC 5545),
+// for phone and desktop calendars to subscribe to. Times are floating, a
+// dinner at 18:30 shows at 18:30 wherever the calendar is.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const ContentType = "text/calendar; charset=utf-8"
+
+// Meal is a planned dinner.
+type Meal struct {
+	ID   uuid.UUID
+	Date time.Time
+	// Time is the time of day as 15:04, empty for an all-day event.
+	Time     string
+	Name     string
+	URL      string
+	CookTime int
+	// Ingredients is the summary of what the recipe needs.
+	Ingredients string
+	Notes       string
+}
+
+// Reminder is a step to take ahead of a meal, such as thawing the chicken.
+type Reminder struct {
+	Text string
+	At   time.Time
+}
+
+var prepWords = []string{
+	"night before", "day before", "overnight", "ahead", "in advance",
+	"thaw", "defrost", "soak", "marinate",
+}
+
+// PrepReminders finds the steps of notes to take ahead of a meal on date:
+// the sentences that speak of the night before, overnight, thawing,
+// soaking or marinating. They are due at 20:00 the evening before, or at
+// 8:00 the same day for those that say morning.
+func PrepReminders(notes string, date time.Time) []Reminder {
+	var reminders []Reminder
+	for _, s := range sentences(notes) {
+		lower := strings.ToLower(s)
+		if !containsAny(lower, prepWords) {
+			continue
+		}
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		at := day.Add(-4 * time.Hour)
+		if strings.Contains(lower, "morning") && !strings.Contains(lower, "night before") {
+			at = day.Add(8 * time.Hour)
+		}
+		reminders = append(reminders, Reminder{Text: s, At: at})
+	}
+	return reminders
+}
+
+// Write writes the meals as a calendar called name, each with its prep
+// reminders. stamp is when the feed is made.
+func Write(w io.Writer, name string, meals []Meal, stamp time.Time) error {
+	cw := calendarWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", "-//meal-org//Meal Plans//EN")
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	cw.line("X-WR-CALNAME", escape(name))
+	cw.line("REFRESH-INTERVAL;VALUE=DURATION", "PT6H")
+	cw.line("X-PUBLISHED-TTL", "PT6H")
+
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, m := range meals {
+		cw.meal(m, dtstamp)
+		for i, r := range PrepReminders(m.Notes, m.Date) {
+			cw.reminder(m, r, i, dtstamp)
+		}
+	}
+
+	cw.line("END", "VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+type calendarWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *calendarWriter) meal(m Meal, dtstamp string) {
+	cw.line("BEGIN", "VEVENT")
+	cw.line("UID", m.ID.String()+"@meal-org")
+	cw.line("DTSTAMP", dtstamp)
+
+	start, timed := mealStart(m)
+	if timed {
+		cw.line("DTSTART", start.Format("20060102T150405"))
+		cw.line("DURATION", "PT1H")
+	} else {
+		cw.line("DTSTART;VALUE=DATE", start.Format("20060102"))
+		cw.line("DTEND;VALUE=DATE", start.AddDate(0, 0, 1).Format("20060102"))
+		cw.line("TRANSP", "TRANSPARENT")
+	}
+	cw.line("SUMMARY", escape(m.Name))
+
+	var desc []string
+	if m.Ingredients != "" {
+		desc = append(desc, "Ingredients: "+m.Ingredients)
+	}
+	if m.CookTime > 0 {
+		desc = append(desc, fmt.Sprintf("Cook time: %d min", m.CookTime))
+	}
+	if m.URL != "" {
+		desc = append(desc, m.URL)
+		cw.line("URL", m.URL)
+	}
+	if len(desc) > 0 {
+		cw.line("DESCRIPTION", escape(strings.Join(desc, "\n")))
+	}
+
+	// A timed dinner rings when it is time to start cooking.
+	if timed && m.CookTime > 0 {
+		cw.alarm(fmt.Sprintf("-PT%dM", m.CookTime), "Start cooking "+m.Name)
+	}
+	cw.line("END", "VEVENT")
+}
+
+func (cw *calendarWriter) reminder(m Meal, r Reminder, i int, dtstamp string) {
+	cw.line("BEGIN", "VEVENT")
+	cw.line("UID", fmt.Sprintf("%s-prep-%d@meal-org", m.ID, i))
+	cw.line("DTSTAMP", dtstamp)
+	cw.line("DTSTART", r.At.Format("20060102T150405"))
+	cw.line("DURATION", "PT15M")
+	cw.line("SUMMARY", escape(fmt.Sprintf("Prep for %s: %s", m.Name, r.Text)))
+	if m.URL != "" {
+		cw.line("URL", m.URL)
+	}
+	cw.alarm("PT0M", r.Text)
+	cw.line("END", "VEVENT")
+}
+
+func (cw *calendarWriter) alarm(trigger, text string) {
+	cw.line("BEGIN", "VALARM")
+	cw.line("ACTION", "DISPLAY")
+	cw.line("TRIGGER", trigger)
+	cw.line("DESCRIPTION", escape(text))
+	cw.line("END", "VALARM")
+}
+
+// line writes a content line, folded at 75 octets without splitting a
+// character.
+func (cw *calendarWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	s := name + ":" + value
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, cw.err = cw.w.WriteString(s[:cut] + "\r\n "); cw.err != nil {
+			return
+		}
+		s = s[cut:]
+		// The leading space of a continuation line counts.
+		limit = 74
+	}
+	_, cw.err = cw.w.WriteString(s + "\r\n")
+}
+
+// mealStart is when the meal is served, and whether it has a time at all.
+func mealStart(m Meal) (time.Time, bool) {
+	day := time.Date(m.Date.Year(), m.Date.Month(), m.Date.Day(), 0, 0, 0, 0, time.UTC)
+	t, err := time.Parse("15:04", m.Time)
+	if err != nil {
+		return day, false
+	}
+	return day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), true
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// sentences splits notes into lines, and lines into sentences.
+func sentences(notes string) []string {
+	var out []string
+	for _, line := range strings.Split(notes, "\n") {
+		for _, s := range strings.SplitAfter(line, ". ") {
+			s = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), "."))
+			s = strings.TrimLeft(s, "-*• ")
+			if s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func containsAny(s string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
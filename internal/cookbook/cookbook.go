@@ -0,0 +1,125 @@
+// Package cookbook lays out a selection of recipes as a book, a PDF to
+// print or an EPUB for e-readers, with the recipes grouped by cuisine.
+package cookbook
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const (
+	FormatPDF  = "pdf"
+	FormatEPUB = "epub"
+)
+
+var ErrUnknownFormat = errors.New("unknown cookbook format, use pdf or epub")
+
+// OtherCuisine is the section of the recipes without a cuisine.
+const OtherCuisine = "Other"
+
+// Book is what goes into a cookbook. Images are the JPEG pictures of the
+// recipes, those without one are printed without.
+type Book struct {
+	Title   string
+	Recipes []models.Recipe
+	Images  map[uuid.UUID][]byte
+	// Cuisines are every cuisine, to place those of the recipes in the
+	// cuisine tree.
+	Cuisines []models.Cuisine
+}
+
+// IsFormat reports whether a book can be written in format, empty meaning
+// PDF.
+func IsFormat(format string) bool {
+	return format == "" || format == FormatPDF || format == FormatEPUB
+}
+
+// Write writes the book in format, PDF when empty.
+func Write(w io.Writer, format string, b Book) error {
+	switch format {
+	case "", FormatPDF:
+		return WritePDF(w, b)
+	case FormatEPUB:
+		return WriteEPUB(w, b)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// ContentType is the media type of a book in format.
+func ContentType(format string) string {
+	if format == FormatEPUB {
+		return "application/epub+zip"
+	}
+	return "application/pdf"
+}
+
+// firstCuisine is the cuisine a recipe is filed under, the first of its
+// cuisines in alphabetical order.
+func firstCuisine(r models.Recipe) (models.CuisineInRecipe, bool) {
+	var first models.CuisineInRecipe
+	for i, c := range r.Cuisines {
+		if i == 0 || strings.ToLower(c.Name) < strings.ToLower(first.Name) {
+			first = c
+		}
+	}
+	return first, len(r.Cuisines) > 0
+}
+
+// ordered returns r with its ingredients and steps in the order of the
+// recipe.
+func ordered(r models.Recipe) models.Recipe {
+	r.Ingredients = append([]models.IngredientInRecipe(nil), r.Ingredients...)
+	sort.SliceStable(r.Ingredients, func(i, j int) bool { return r.Ingredients[i].Index < r.Ingredients[j].Index })
+	r.Instructions = append([]models.InstructionInRecipe(nil), r.Instructions...)
+	sort.SliceStable(r.Instructions, func(i, j int) bool { return r.Instructions[i].StepNo < r.Instructions[j].StepNo })
+	return r
+}
+
+func summary(r models.Recipe) string {
+	var parts []string
+	if r.Servings > 0 {
+		parts = append(parts, "Serves "+strconv.Itoa(r.Servings))
+	}
+	if r.Yield != nil && *r.Yield != "" {
+		parts = append(parts, "Makes "+*r.Yield)
+	}
+	if r.CookTimeInMinutes > 0 {
+		parts = append(parts, duration(r.CookTimeInMinutes))
+	}
+	var cuisines []string
+	for _, c := range r.Cuisines {
+		cuisines = append(cuisines, c.Name)
+	}
+	if len(cuisines) > 0 {
+		parts = append(parts, strings.Join(cuisines, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func duration(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%d min", m)
+	case m == 0:
+		return fmt.Sprintf("%d h", h)
+	default:
+		return fmt.Sprintf("%d h %d min", h, m)
+	}
+}
+
+func ingredientLine(i models.IngredientInRecipe) string {
+	line := strings.TrimSpace(i.Amount + " " + i.Name)
+	if i.PrepNote != nil && *i.PrepNote != "" {
+		line += ", " + *i.PrepNote
+	}
+	return line
+}
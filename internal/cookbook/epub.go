@@ -0,0 +1,369 @@
+package cookbook
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+// chapter is the page of a recipe in the EPUB.
+type chapter struct {
+	ID     string
+	Href   string
+	Image  string
+	Recipe models.Recipe
+	Facts  string
+}
+
+// epubFile is an XML file of the EPUB, made from a template of either the
+// text or html package.
+type epubFile struct {
+	name string
+	tmpl interface {
+		Execute(w io.Writer, data any) error
+	}
+	data any
+}
+
+// navNode is a cuisine in the navigation, with its sub-cuisines and the
+// recipes filed under it.
+type navNode struct {
+	Name     string
+	Children []*navNode
+	Chapters []*chapter
+}
+
+// WriteEPUB writes the book as an EPUB 3, with a chapter per recipe and a
+// navigation that follows the cuisine tree.
+func WriteEPUB(w io.Writer, b Book) error {
+	root := cuisineTree(b)
+
+	// Chapters are numbered in reading order, which is that of the
+	// navigation.
+	var chapters []*chapter
+	var walk func(n *navNode)
+	walk = func(n *navNode) {
+		for _, c := range n.Chapters {
+			num := len(chapters) + 1
+			c.ID = fmt.Sprintf("recipe-%03d", num)
+			c.Href = fmt.Sprintf("recipes/%03d.xhtml", num)
+			if len(b.Images[c.Recipe.ID]) > 0 {
+				c.Image = fmt.Sprintf("images/%03d.jpg", num)
+			}
+			chapters = append(chapters, c)
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	zw := zip.NewWriter(w)
+
+	// The mimetype comes first and uncompressed, so that it can be read
+	// at a fixed offset.
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mw, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	files := []epubFile{
+		{"META-INF/container.xml", containerTemplate, nil},
+		{"OEBPS/content.opf", packageTemplate, packageData(b, chapters)},
+		{"OEBPS/nav.xhtml", navTemplate, root},
+		{"OEBPS/title.xhtml", titleTemplate, b},
+	}
+	for _, c := range chapters {
+		files = append(files, epubFile{"OEBPS/" + c.Href, chapterTemplate, c})
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, xmlHeader); err != nil {
+			return err
+		}
+		if err := f.tmpl.Execute(fw, f.data); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+
+	fw, err := zw.Create("OEBPS/style.css")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(fw, epubStyle); err != nil {
+		return err
+	}
+
+	for _, c := range chapters {
+		if c.Image == "" {
+			continue
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: "OEBPS/" + c.Image, Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(b.Images[c.Recipe.ID]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// cuisineTree files each recipe under its first cuisine, placed in the tree
+// of cuisines by their parents. Cuisines without recipes below them are
+// left out, and recipes without a cuisine come last.
+func cuisineTree(b Book) *navNode {
+	byID := make(map[uuid.UUID]models.Cuisine, len(b.Cuisines))
+	for _, c := range b.Cuisines {
+		byID[c.ID] = c
+	}
+
+	root := &navNode{}
+	nodes := map[uuid.UUID]*navNode{}
+	var nodeOf func(id uuid.UUID, name string, depth int) *navNode
+	nodeOf = func(id uuid.UUID, name string, depth int) *navNode {
+		if n, ok := nodes[id]; ok {
+			return n
+		}
+		n := &navNode{Name: name}
+		nodes[id] = n
+		parent := root
+		// depth guards against a loop of parents
+		if c, ok := byID[id]; ok && c.ParentID != nil && depth < len(b.Cuisines) {
+			if p, ok := byID[*c.ParentID]; ok {
+				parent = nodeOf(p.ID, p.Name, depth+1)
+			}
+		}
+		parent.Children = append(parent.Children, n)
+		return n
+	}
+
+	var other *navNode
+	for _, r := range b.Recipes {
+		c := &chapter{Recipe: ordered(r), Facts: summary(r)}
+		cuisine, ok := firstCuisine(r)
+		if !ok {
+			if other == nil {
+				other = &navNode{Name: OtherCuisine}
+			}
+			other.Chapters = append(other.Chapters, c)
+			continue
+		}
+		n := nodeOf(cuisine.ID, cuisine.Name, 0)
+		n.Chapters = append(n.Chapters, c)
+	}
+
+	var sortTree func(n *navNode)
+	sortTree = func(n *navNode) {
+		sort.SliceStable(n.Chapters, func(i, j int) bool {
+			return strings.ToLower(n.Chapters[i].Recipe.Name) < strings.ToLower(n.Chapters[j].Recipe.Name)
+		})
+		sort.SliceStable(n.Children, func(i, j int) bool {
+			return strings.ToLower(n.Children[i].Name) < strings.ToLower(n.Children[j].Name)
+		})
+		for _, c := range n.Children {
+			sortTree(c)
+		}
+	}
+	sortTree(root)
+	if other != nil {
+		sortTree(other)
+		root.Children = append(root.Children, other)
+	}
+	return root
+}
+
+type epubPackage struct {
+	ID       string
+	Title    string
+	Modified string
+	Chapters []*chapter
+}
+
+// packageData identifies the book by its recipes, so that e-readers see the
+// same selection exported again as a new edition of the same book.
+func packageData(b Book, chapters []*chapter) epubPackage {
+	ids := make([]string, 0, len(b.Recipes))
+	modified := time.Time{}
+	for _, r := range b.Recipes {
+		ids = append(ids, r.ID.String())
+		if r.UpdatedAt.After(modified) {
+			modified = r.UpdatedAt
+		}
+	}
+	sort.Strings(ids)
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+	return epubPackage{
+		ID:       uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.Join(ids, ","))).String(),
+		Title:    b.Title,
+		Modified: modified.UTC().Format("2006-01-02T15:04:05Z"),
+		Chapters: chapters,
+	}
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s)) // #nosec G104
+	return b.String()
+}
+
+var containerTemplate = texttemplate.Must(texttemplate.New("container").Parse(
+	`<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`))
+
+var packageTemplate = texttemplate.Must(texttemplate.New("package").Funcs(texttemplate.FuncMap{"x": xmlEscape}).Parse(
+	`<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="en">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">urn:uuid:{{.ID}}</dc:identifier>
+    <dc:title>{{x .Title}}</dc:title>
+    <dc:language>en</dc:language>
+    <meta property="dcterms:modified">{{.Modified}}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="style" href="style.css" media-type="text/css"/>
+    <item id="title" href="title.xhtml" media-type="application/xhtml+xml"/>
+{{- range .Chapters}}
+    <item id="{{.ID}}" href="{{.Href}}" media-type="application/xhtml+xml"/>
+{{- if .Image}}
+    <item id="{{.ID}}-image" href="{{.Image}}" media-type="image/jpeg"/>
+{{- end}}
+{{- end}}
+  </manifest>
+  <spine>
+    <itemref idref="title"/>
+    <itemref idref="nav"/>
+{{- range .Chapters}}
+    <itemref idref="{{.ID}}"/>
+{{- end}}
+  </spine>
+</package>
+`))
+
+var navTemplate = template.Must(template.New("nav").Parse(
+	`<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+  <meta charset="UTF-8"/>
+  <title>Contents</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>Contents</h1>
+    <ol>
+{{- range .Children}}{{template "node" .}}{{end}}
+    </ol>
+  </nav>
+</body>
+</html>
+{{define "node"}}
+      <li><span>{{.Name}}</span>
+        <ol>
+{{- range .Chapters}}
+          <li><a href="{{.Href}}">{{.Recipe.Name}}</a></li>
+{{- end}}
+{{- range .Children}}{{template "node" .}}{{end}}
+        </ol>
+      </li>
+{{- end}}`))
+
+var titleTemplate = template.Must(template.New("title").Parse(
+	`<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+  <meta charset="UTF-8"/>
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <section epub:type="titlepage" class="title-page">
+    <h1>{{.Title}}</h1>
+    <p>{{len .Recipes}} {{if eq (len .Recipes) 1}}recipe{{else}}recipes{{end}}</p>
+  </section>
+</body>
+</html>
+`))
+
+var chapterTemplate = template.Must(template.New("chapter").Parse(
+	`<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+  <meta charset="UTF-8"/>
+  <title>{{.Recipe.Name}}</title>
+  <link rel="stylesheet" type="text/css" href="../style.css"/>
+</head>
+<body>
+  <section epub:type="chapter">
+    <h1>{{.Recipe.Name}}</h1>
+{{- if .Facts}}
+    <p class="facts">{{.Facts}}</p>
+{{- end}}
+{{- if .Image}}
+    <img src="../{{.Image}}" alt="{{.Recipe.Name}}"/>
+{{- end}}
+{{- with .Recipe.Description}}{{if .}}
+    <p>{{.}}</p>
+{{- end}}{{end}}
+{{- if .Recipe.Ingredients}}
+    <h2>Ingredients</h2>
+    <ul>
+{{- range .Recipe.Ingredients}}
+      <li>{{with .Amount}}{{.}} {{end}}{{.Name}}{{with .PrepNote}}{{if .}}, {{.}}{{end}}{{end}}</li>
+{{- end}}
+    </ul>
+{{- end}}
+{{- if .Recipe.Instructions}}
+    <h2>Directions</h2>
+    <ol>
+{{- range .Recipe.Instructions}}
+      <li>{{.Instruction}}</li>
+{{- end}}
+    </ol>
+{{- end}}
+{{- with .Recipe.Notes}}{{if .}}
+    <h2>Notes</h2>
+    <p>{{.}}</p>
+{{- end}}{{end}}
+{{- with .Recipe.ExternalURL}}{{if .}}
+    <p class="source"><a href="{{.}}">{{.}}</a></p>
+{{- end}}{{end}}
+  </section>
+</body>
+</html>
+`))
+
+const epubStyle = `body { font-family: serif; line-height: 1.5; }
+h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+h2 { font-size: 1.2em; margin-top: 1.2em; border-bottom: 1px solid #ccc; page-break-after: avoid; }
+img { display: block; max-width: 100%; margin: 1em auto; }
+li { margin-bottom: 0.3em; }
+.facts, .source { color: #555; font-size: 0.9em; }
+.title-page { text-align: center; margin-top: 30%; }
+nav ol { list-style: none; padding-left: 1em; }
+`
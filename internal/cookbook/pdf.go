@@ -0,0 +1,285 @@
+package cookbook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const (
+	margin     = 20.0
+	lineHeight = 5.5
+	tocLine    = 7.0
+	// imageMaxHeight leaves room for the ingredients on the first page of a
+	// recipe.
+	imageMaxHeight = 80.0
+)
+
+type section struct {
+	name    string
+	link    int
+	recipes []models.Recipe
+	links   []int
+}
+
+// WritePDF writes the book as an A4 PDF. Text outside of Windows-1252, the
+// encoding of the standard PDF fonts, is replaced.
+func WritePDF(w io.Writer, b Book) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(b.Title, true)
+	pdf.SetCreator("Meal Org", true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-margin + 5)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 5, strconv.Itoa(pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	sections := sectionsOf(b.Recipes)
+	for i := range sections {
+		sections[i].link = pdf.AddLink()
+		for range sections[i].recipes {
+			sections[i].links = append(sections[i].links, pdf.AddLink())
+		}
+	}
+
+	writeTitlePage(pdf, tr, b)
+
+	// The table of contents is written once the pages of the recipes are
+	// known, on pages kept for it here.
+	entries := len(sections)
+	for _, s := range sections {
+		entries += len(s.recipes)
+	}
+	_, pageHeight := pdf.GetPageSize()
+	perPage := int((pageHeight - 2*margin - 20) / tocLine)
+	tocStart := pdf.PageCount() + 1
+	for i := 0; i < max(1, (entries+perPage-1)/perPage); i++ {
+		pdf.AddPage()
+	}
+
+	pages := map[int]int{}
+	for _, s := range sections {
+		pdf.AddPage()
+		pdf.SetLink(s.link, 0, -1)
+		pages[s.link] = pdf.PageNo()
+		pdf.Bookmark(s.name, 0, -1)
+		writeSectionPage(pdf, tr, s.name)
+
+		for i, r := range s.recipes {
+			pdf.AddPage()
+			pdf.SetLink(s.links[i], 0, -1)
+			pages[s.links[i]] = pdf.PageNo()
+			pdf.Bookmark(r.Name, 1, -1)
+			writeRecipe(pdf, tr, ordered(r), b.Images[r.ID])
+		}
+	}
+
+	last := pdf.PageNo()
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.SetPage(tocStart)
+	pdf.SetY(margin)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 12, "Contents", "", 1, "L", false, 0, "")
+	pdf.Ln(8)
+	n := 0
+	tocEntry := func(name string, link int, indent float64, style string) {
+		if n > 0 && n%perPage == 0 {
+			pdf.SetPage(pdf.PageNo() + 1)
+			pdf.SetY(margin + 20)
+		}
+		n++
+		pageWidth, _ := pdf.GetPageSize()
+		number := strconv.Itoa(pages[link])
+		pdf.SetFont("Helvetica", style, 11)
+		pdf.SetX(margin + indent)
+		name = fitText(pdf, tr(name), pageWidth-2*margin-indent-15)
+		pdf.CellFormat(pageWidth-2*margin-indent-15, tocLine, name, "", 0, "L", false, link, "")
+		pdf.CellFormat(15, tocLine, number, "", 1, "R", false, link, "")
+	}
+	for _, s := range sections {
+		tocEntry(s.name, s.link, 0, "B")
+		for i, r := range s.recipes {
+			tocEntry(r.Name, s.links[i], 6, "")
+		}
+	}
+	pdf.SetPage(last)
+
+	return pdf.Output(w)
+}
+
+// sectionsOf files each recipe under its first cuisine in alphabetical
+// order, and sorts sections and recipes by name.
+func sectionsOf(recipes []models.Recipe) []section {
+	byName := map[string]*section{}
+	for _, r := range recipes {
+		name := OtherCuisine
+		if c, ok := firstCuisine(r); ok {
+			name = c.Name
+		}
+		s, ok := byName[name]
+		if !ok {
+			s = &section{name: name}
+			byName[name] = s
+		}
+		s.recipes = append(s.recipes, r)
+	}
+
+	sections := make([]section, 0, len(byName))
+	for _, s := range byName {
+		sort.SliceStable(s.recipes, func(i, j int) bool {
+			return strings.ToLower(s.recipes[i].Name) < strings.ToLower(s.recipes[j].Name)
+		})
+		sections = append(sections, *s)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		// Other comes last
+		if (sections[i].name == OtherCuisine) != (sections[j].name == OtherCuisine) {
+			return sections[j].name == OtherCuisine
+		}
+		return strings.ToLower(sections[i].name) < strings.ToLower(sections[j].name)
+	})
+	return sections
+}
+
+func writeTitlePage(pdf *fpdf.Fpdf, tr func(string) string, b Book) {
+	pdf.AddPage()
+	_, pageHeight := pdf.GetPageSize()
+	pdf.SetY(pageHeight / 3)
+	pdf.SetFont("Helvetica", "B", 32)
+	pdf.MultiCell(0, 14, tr(b.Title), "", "C", false)
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "", 13)
+	pdf.SetTextColor(100, 100, 100)
+	count := fmt.Sprintf("%d recipes", len(b.Recipes))
+	if len(b.Recipes) == 1 {
+		count = "1 recipe"
+	}
+	pdf.CellFormat(0, 8, count, "", 1, "C", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+func writeSectionPage(pdf *fpdf.Fpdf, tr func(string) string, name string) {
+	_, pageHeight := pdf.GetPageSize()
+	pdf.SetY(pageHeight/2 - 10)
+	pdf.SetFont("Helvetica", "B", 28)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.MultiCell(0, 12, tr(name), "", "C", false)
+}
+
+func writeRecipe(pdf *fpdf.Fpdf, tr func(string) string, r models.Recipe, image []byte) {
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - 2*margin
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.MultiCell(0, 9, tr(r.Name), "", "L", false)
+
+	pdf.SetFont("Helvetica", "I", 10)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.MultiCell(0, lineHeight, tr(summary(r)), "", "L", false)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(3)
+
+	if len(image) > 0 {
+		name := "recipe-" + r.ID.String()
+		opts := fpdf.ImageOptions{ImageType: "JPG"}
+		info := pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(image))
+		if pdf.Ok() && info != nil && info.Width() > 0 {
+			w, h := width, width*info.Height()/info.Width()
+			if h > imageMaxHeight {
+				w, h = imageMaxHeight*info.Width()/info.Height(), imageMaxHeight
+			}
+			pdf.ImageOptions(name, margin+(width-w)/2, pdf.GetY(), w, h, true, opts, 0, "")
+			pdf.Ln(4)
+		}
+	}
+
+	if r.Description != nil && *r.Description != "" {
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(0, lineHeight, tr(*r.Description), "", "L", false)
+		pdf.Ln(3)
+	}
+
+	if len(r.Ingredients) > 0 {
+		heading(pdf, "Ingredients")
+		pdf.SetFont("Helvetica", "", 11)
+		for _, i := range r.Ingredients {
+			pdf.SetX(margin + 2)
+			pdf.CellFormat(5, lineHeight, tr("•"), "", 0, "L", false, 0, "")
+			pdf.MultiCell(width-7, lineHeight, tr(ingredientLine(i)), "", "L", false)
+		}
+		pdf.Ln(3)
+	}
+
+	if len(r.Instructions) > 0 {
+		heading(pdf, "Directions")
+		pdf.SetFont("Helvetica", "", 11)
+		for n, s := range r.Instructions {
+			keepLines(pdf, 2)
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.CellFormat(8, lineHeight, strconv.Itoa(n+1)+".", "", 0, "L", false, 0, "")
+			pdf.SetFont("Helvetica", "", 11)
+			pdf.MultiCell(width-8, lineHeight, tr(s.Instruction), "", "L", false)
+			pdf.Ln(1.5)
+		}
+		pdf.Ln(3)
+	}
+
+	if r.Notes != nil && *r.Notes != "" {
+		heading(pdf, "Notes")
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(0, lineHeight, tr(*r.Notes), "", "L", false)
+		pdf.Ln(3)
+	}
+
+	if r.ExternalURL != nil && *r.ExternalURL != "" {
+		keepLines(pdf, 1)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.MultiCell(0, 4, tr(*r.ExternalURL), "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+}
+
+// heading writes a heading of a recipe, on the next page unless there is
+// room for a few lines below it.
+func heading(pdf *fpdf.Fpdf, title string) {
+	keepLines(pdf, 4)
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+	pdf.Ln(1)
+}
+
+// keepLines breaks the page when fewer than n lines fit below.
+func keepLines(pdf *fpdf.Fpdf, n int) {
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+float64(n)*lineHeight > pageHeight-margin {
+		pdf.AddPage()
+	}
+}
+
+// fitText shortens s with an ellipsis to fit in width.
+func fitText(pdf *fpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	for len(s) > 0 && pdf.GetStringWidth(s+"...") > width {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
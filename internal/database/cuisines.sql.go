@@ -74,6 +74,26 @@ func (q *Queries) GetCuisineByID(ctx context.Context, id uuid.UUID) (Cuisine, er
 	return i, err
 }
 
+const getCuisineByName = `-- name: GetCuisineByName :one
+SELECT id, created_at, updated_at, name, parent_id
+FROM cuisines
+WHERE name = $1
+LIMIT 1
+`
+
+func (q *Queries) GetCuisineByName(ctx context.Context, name string) (Cuisine, error) {
+	row := q.db.QueryRow(ctx, getCuisineByName, name)
+	var i Cuisine
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.ParentID,
+	)
+	return i, err
+}
+
 const listCuisines = `-- name: ListCuisines :many
 SELECT id, created_at, updated_at, name, parent_id
 FROM cuisines
@@ -70,6 +70,24 @@ func (q *Queries) GetIngredientByID(ctx context.Context, id uuid.UUID) (Ingredie
 	return i, err
 }
 
+const getIngredientByName = `-- name: GetIngredientByName :one
+SELECT id, created_at, updated_at, name
+FROM ingredients
+WHERE name = $1
+`
+
+func (q *Queries) GetIngredientByName(ctx context.Context, name string) (Ingredient, error) {
+	row := q.db.QueryRow(ctx, getIngredientByName, name)
+	var i Ingredient
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
 const listIngredients = `-- name: ListIngredients :many
 SELECT id, created_at, updated_at, name
 FROM ingredients
@@ -10,6 +10,42 @@ import (
 	"github.com/google/uuid"
 )
 
+type CalendarToken struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Value     string    `json:"value"`
+}
+
+type Collection struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UserID      uuid.UUID `json:"user_id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	Filter      []byte    `json:"filter"`
+}
+
+type CollectionRecipe struct {
+	CreatedAt    time.Time `json:"created_at"`
+	CollectionID uuid.UUID `json:"collection_id"`
+	RecipeID     uuid.UUID `json:"recipe_id"`
+	Position     int32     `json:"position"`
+}
+
+type CookEvent struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	RecipeID  uuid.UUID `json:"recipe_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CookedAt  time.Time `json:"cooked_at"`
+	CookedBy  *string   `json:"cooked_by"`
+	Rating    *int16    `json:"rating"`
+	Notes     *string   `json:"notes"`
+	PhotoUrl  *string   `json:"photo_url"`
+}
+
 type Cuisine struct {
 	ID        uuid.UUID  `json:"id"`
 	CreatedAt time.Time  `json:"created_at"`
@@ -33,19 +69,75 @@ type Instruction struct {
 	RecipeID    uuid.UUID `json:"recipe_id"`
 }
 
+type Job struct {
+	ID           uuid.UUID  `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Kind         string     `json:"kind"`
+	UniqueKey    string     `json:"unique_key"`
+	Payload      []byte     `json:"payload"`
+	TraceContext []byte     `json:"trace_context"`
+	Status       string     `json:"status"`
+	Attempts     int32      `json:"attempts"`
+	MaxAttempts  int32      `json:"max_attempts"`
+	LastError    *string    `json:"last_error"`
+	RunAt        time.Time  `json:"run_at"`
+	LockedAt     *time.Time `json:"locked_at"`
+	RecipeID     *uuid.UUID `json:"recipe_id"`
+}
+
+type MealPlan struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+}
+
+type PantryItem struct {
+	ID           uuid.UUID  `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	UserID       uuid.UUID  `json:"user_id"`
+	IngredientID uuid.UUID  `json:"ingredient_id"`
+	Quantity     *float64   `json:"quantity"`
+	Unit         *string    `json:"unit"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
+
+type PlannedMeal struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	MealPlanID uuid.UUID `json:"meal_plan_id"`
+	RecipeID   uuid.UUID `json:"recipe_id"`
+	Day        time.Time `json:"day"`
+	ServeTime  *string   `json:"serve_time"`
+}
+
 type Recipe struct {
-	ID                uuid.UUID `json:"id"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
-	ExternalUrl       *string   `json:"external_url"`
-	Name              string    `json:"name"`
-	Description       *string   `json:"description"`
-	Servings          int32     `json:"servings"`
-	Yield             *string   `json:"yield"`
-	CookTimeInMinutes int32     `json:"cook_time_in_minutes"`
-	Notes             *string   `json:"notes"`
-	UserID            uuid.UUID `json:"user_id"`
-	ExternalImageUrl  *string   `json:"external_image_url"`
+	ID                uuid.UUID  `json:"id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	ExternalUrl       *string    `json:"external_url"`
+	Name              string     `json:"name"`
+	Description       *string    `json:"description"`
+	Servings          int32      `json:"servings"`
+	Yield             *string    `json:"yield"`
+	CookTimeInMinutes int32      `json:"cook_time_in_minutes"`
+	Notes             *string    `json:"notes"`
+	UserID            uuid.UUID  `json:"user_id"`
+	ExternalImageUrl  *string    `json:"external_image_url"`
+	SiteName          *string    `json:"site_name"`
+	VideoUrl          *string    `json:"video_url"`
+	DeletedAt         *time.Time `json:"deleted_at"`
+}
+
+type RecipeCookStat struct {
+	RecipeID      uuid.UUID   `json:"recipe_id"`
+	LastCookedAt  interface{} `json:"last_cooked_at"`
+	TimesCooked   int64       `json:"times_cooked"`
+	AverageRating float64     `json:"average_rating"`
 }
 
 type RecipeCuisine struct {
@@ -55,6 +147,30 @@ type RecipeCuisine struct {
 	RecipeID  uuid.UUID `json:"recipe_id"`
 }
 
+type RecipeFavorite struct {
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	RecipeID  uuid.UUID `json:"recipe_id"`
+}
+
+type RecipeImage struct {
+	RecipeID  uuid.UUID `json:"recipe_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	SourceUrl string    `json:"source_url"`
+	Status    string    `json:"status"`
+	LastError *string   `json:"last_error"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+type RecipeImport struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Source    string    `json:"source"`
+	SourceID  string    `json:"source_id"`
+	RecipeID  uuid.UUID `json:"recipe_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type RecipeIngredient struct {
 	Index        int32     `json:"index"`
 	CreatedAt    time.Time `json:"created_at"`
@@ -65,12 +181,36 @@ type RecipeIngredient struct {
 	RecipeID     uuid.UUID `json:"recipe_id"`
 }
 
+type RecipeRevision struct {
+	ID           uuid.UUID `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	RecipeID     uuid.UUID `json:"recipe_id"`
+	Revision     int32     `json:"revision"`
+	UserID       uuid.UUID `json:"user_id"`
+	Recipe       []byte    `json:"recipe"`
+	RestoredFrom *int32    `json:"restored_from"`
+}
+
+type RecipeTag struct {
+	CreatedAt time.Time `json:"created_at"`
+	TagID     uuid.UUID `json:"tag_id"`
+	RecipeID  uuid.UUID `json:"recipe_id"`
+}
+
 type Session struct {
 	Token  string    `json:"token"`
 	Data   []byte    `json:"data"`
 	Expiry time.Time `json:"expiry"`
 }
 
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+}
+
 type Token struct {
 	Value     string    `json:"value"`
 	CreatedAt time.Time `json:"created_at"`
@@ -86,4 +226,5 @@ type User struct {
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
 	Hash      string    `json:"hash"`
+	IsAdmin   bool      `json:"is_admin"`
 }
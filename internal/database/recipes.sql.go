@@ -27,7 +27,7 @@ INSERT INTO recipes (
   notes
 )
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-RETURNING id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url
+RETURNING id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url, site_name, video_url, deleted_at
 `
 
 type CreateRecipeParams struct {
@@ -72,10 +72,31 @@ func (q *Queries) CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Rec
 		&i.Notes,
 		&i.UserID,
 		&i.ExternalImageUrl,
+		&i.SiteName,
+		&i.VideoUrl,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const deleteDeletedRecipe = `-- name: DeleteDeletedRecipe :execrows
+DELETE FROM recipes
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+`
+
+type DeleteDeletedRecipeParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteDeletedRecipe(ctx context.Context, arg DeleteDeletedRecipeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDeletedRecipe, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteRecipe = `-- name: DeleteRecipe :exec
 DELETE FROM recipes
 WHERE id = $1
@@ -87,8 +108,8 @@ func (q *Queries) DeleteRecipe(ctx context.Context, id uuid.UUID) error {
 }
 
 const getRecipeByID = `-- name: GetRecipeByID :one
-SELECT id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url FROM recipes
-WHERE id = $1
+SELECT id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url, site_name, video_url, deleted_at FROM recipes
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetRecipeByID(ctx context.Context, id uuid.UUID) (Recipe, error) {
@@ -107,35 +128,154 @@ func (q *Queries) GetRecipeByID(ctx context.Context, id uuid.UUID) (Recipe, erro
 		&i.Notes,
 		&i.UserID,
 		&i.ExternalImageUrl,
+		&i.SiteName,
+		&i.VideoUrl,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const listRecipesByUserID = `-- name: ListRecipesByUserID :many
-SELECT id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url
+const getRecipeByIDForUpdate = `-- name: GetRecipeByIDForUpdate :one
+SELECT id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url, site_name, video_url, deleted_at FROM recipes
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetRecipeByIDForUpdate(ctx context.Context, id uuid.UUID) (Recipe, error) {
+	row := q.db.QueryRow(ctx, getRecipeByIDForUpdate, id)
+	var i Recipe
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalUrl,
+		&i.Name,
+		&i.Description,
+		&i.Servings,
+		&i.Yield,
+		&i.CookTimeInMinutes,
+		&i.Notes,
+		&i.UserID,
+		&i.ExternalImageUrl,
+		&i.SiteName,
+		&i.VideoUrl,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const listDeletedRecipesByUserID = `-- name: ListDeletedRecipesByUserID :many
+SELECT id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url, site_name, video_url, deleted_at
 FROM recipes
-WHERE user_id = $1
-ORDER BY name
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListDeletedRecipesByUserID(ctx context.Context, userID uuid.UUID) ([]Recipe, error) {
+	rows, err := q.db.Query(ctx, listDeletedRecipesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Recipe
+	for rows.Next() {
+		var i Recipe
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExternalUrl,
+			&i.Name,
+			&i.Description,
+			&i.Servings,
+			&i.Yield,
+			&i.CookTimeInMinutes,
+			&i.Notes,
+			&i.UserID,
+			&i.ExternalImageUrl,
+			&i.SiteName,
+			&i.VideoUrl,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecipesByUserID = `-- name: ListRecipesByUserID :many
+SELECT
+  r.id, r.created_at, r.updated_at, r.external_url, r.name, r.description, r.servings, r.yield, r.cook_time_in_minutes, r.notes, r.user_id, r.external_image_url, r.site_name, r.video_url, r.deleted_at,
+  s.last_cooked_at,
+  coalesce(s.times_cooked, 0)::BIGINT AS times_cooked,
+  s.average_rating,
+  (f.recipe_id IS NOT NULL)::BOOLEAN AS favorite
+FROM
+  recipes r
+LEFT JOIN
+  recipe_cook_stats s ON r.id = s.recipe_id
+LEFT JOIN
+  recipe_favorites f ON r.id = f.recipe_id AND r.user_id = f.user_id
+WHERE
+  r.user_id = $1 AND r.deleted_at IS NULL
+ORDER BY
+  CASE WHEN $2::TEXT = 'last_cooked' THEN s.last_cooked_at END DESC NULLS LAST,
+  CASE WHEN $2::TEXT = 'times_cooked' THEN s.times_cooked END DESC NULLS LAST,
+  CASE WHEN $2::TEXT = 'rating' THEN s.average_rating END DESC NULLS LAST,
+  CASE WHEN $2::TEXT = 'favorite' THEN f.recipe_id IS NOT NULL END DESC,
+  r.name
 LIMIT
-  $2
-  OFFSET $3
+  $3
+  OFFSET $4
 `
 
 type ListRecipesByUserIDParams struct {
 	UserID uuid.UUID `json:"user_id"`
+	Sort   string    `json:"sort"`
 	Limit  int32     `json:"limit"`
 	Offset int32     `json:"offset"`
 }
 
-func (q *Queries) ListRecipesByUserID(ctx context.Context, arg ListRecipesByUserIDParams) ([]Recipe, error) {
-	rows, err := q.db.Query(ctx, listRecipesByUserID, arg.UserID, arg.Limit, arg.Offset)
+type ListRecipesByUserIDRow struct {
+	ID                uuid.UUID  `json:"id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	ExternalUrl       *string    `json:"external_url"`
+	Name              string     `json:"name"`
+	Description       *string    `json:"description"`
+	Servings          int32      `json:"servings"`
+	Yield             *string    `json:"yield"`
+	CookTimeInMinutes int32      `json:"cook_time_in_minutes"`
+	Notes             *string    `json:"notes"`
+	UserID            uuid.UUID  `json:"user_id"`
+	ExternalImageUrl  *string    `json:"external_image_url"`
+	SiteName          *string    `json:"site_name"`
+	VideoUrl          *string    `json:"video_url"`
+	DeletedAt         *time.Time `json:"deleted_at"`
+	LastCookedAt      *time.Time `json:"last_cooked_at"`
+	TimesCooked       int64      `json:"times_cooked"`
+	AverageRating     *float64   `json:"average_rating"`
+	Favorite          bool       `json:"favorite"`
+}
+
+func (q *Queries) ListRecipesByUserID(ctx context.Context, arg ListRecipesByUserIDParams) ([]ListRecipesByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, listRecipesByUserID,
+		arg.UserID,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Recipe
+	var items []ListRecipesByUserIDRow
 	for rows.Next() {
-		var i Recipe
+		var i ListRecipesByUserIDRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -149,6 +289,13 @@ func (q *Queries) ListRecipesByUserID(ctx context.Context, arg ListRecipesByUser
 			&i.Notes,
 			&i.UserID,
 			&i.ExternalImageUrl,
+			&i.SiteName,
+			&i.VideoUrl,
+			&i.DeletedAt,
+			&i.LastCookedAt,
+			&i.TimesCooked,
+			&i.AverageRating,
+			&i.Favorite,
 		); err != nil {
 			return nil, err
 		}
@@ -162,47 +309,74 @@ func (q *Queries) ListRecipesByUserID(ctx context.Context, arg ListRecipesByUser
 
 const listRecipesWithCuisinesByUserID = `-- name: ListRecipesWithCuisinesByUserID :many
 SELECT
-  r.id, r.created_at, r.updated_at, r.external_url, r.name, r.description, r.servings, r.yield, r.cook_time_in_minutes, r.notes, r.user_id, r.external_image_url,
-  string_agg(c.name, ', ') AS cuisines
+  r.id, r.created_at, r.updated_at, r.external_url, r.name, r.description, r.servings, r.yield, r.cook_time_in_minutes, r.notes, r.user_id, r.external_image_url, r.site_name, r.video_url, r.deleted_at,
+  string_agg(c.name, ', ') AS cuisines,
+  s.last_cooked_at,
+  coalesce(s.times_cooked, 0)::BIGINT AS times_cooked,
+  s.average_rating,
+  (f.recipe_id IS NOT NULL)::BOOLEAN AS favorite
 FROM
   recipes r
 LEFT JOIN
   recipe_cuisine rc ON r.id = rc.recipe_id
 LEFT JOIN
   cuisines c ON rc.cuisine_id = c.id
+LEFT JOIN
+  recipe_cook_stats s ON r.id = s.recipe_id
+LEFT JOIN
+  recipe_favorites f ON r.id = f.recipe_id AND r.user_id = f.user_id
 WHERE
-  r.user_id = $1
+  r.user_id = $1 AND r.deleted_at IS NULL
 GROUP BY
-  r.id
+  r.id, s.last_cooked_at, s.times_cooked, s.average_rating, f.recipe_id
+ORDER BY
+  CASE WHEN $2::TEXT = 'last_cooked' THEN s.last_cooked_at END DESC NULLS LAST,
+  CASE WHEN $2::TEXT = 'times_cooked' THEN s.times_cooked END DESC NULLS LAST,
+  CASE WHEN $2::TEXT = 'rating' THEN s.average_rating END DESC NULLS LAST,
+  CASE WHEN $2::TEXT = 'favorite' THEN f.recipe_id IS NOT NULL END DESC,
+  r.name
 LIMIT
-  $2
-  OFFSET $3
+  $3
+  OFFSET $4
 `
 
 type ListRecipesWithCuisinesByUserIDParams struct {
 	UserID uuid.UUID `json:"user_id"`
+	Sort   string    `json:"sort"`
 	Limit  int32     `json:"limit"`
 	Offset int32     `json:"offset"`
 }
 
 type ListRecipesWithCuisinesByUserIDRow struct {
-	ID                uuid.UUID `json:"id"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
-	ExternalUrl       *string   `json:"external_url"`
-	Name              string    `json:"name"`
-	Description       *string   `json:"description"`
-	Servings          int32     `json:"servings"`
-	Yield             *string   `json:"yield"`
-	CookTimeInMinutes int32     `json:"cook_time_in_minutes"`
-	Notes             *string   `json:"notes"`
-	UserID            uuid.UUID `json:"user_id"`
-	ExternalImageUrl  *string   `json:"external_image_url"`
-	Cuisines          []byte    `json:"cuisines"`
+	ID                uuid.UUID  `json:"id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	ExternalUrl       *string    `json:"external_url"`
+	Name              string     `json:"name"`
+	Description       *string    `json:"description"`
+	Servings          int32      `json:"servings"`
+	Yield             *string    `json:"yield"`
+	CookTimeInMinutes int32      `json:"cook_time_in_minutes"`
+	Notes             *string    `json:"notes"`
+	UserID            uuid.UUID  `json:"user_id"`
+	ExternalImageUrl  *string    `json:"external_image_url"`
+	SiteName          *string    `json:"site_name"`
+	VideoUrl          *string    `json:"video_url"`
+	DeletedAt         *time.Time `json:"deleted_at"`
+	Cuisines          []byte     `json:"cuisines"`
+	LastCookedAt      *time.Time `json:"last_cooked_at"`
+	TimesCooked       int64      `json:"times_cooked"`
+	AverageRating     *float64   `json:"average_rating"`
+	Favorite          bool       `json:"favorite"`
 }
 
 func (q *Queries) ListRecipesWithCuisinesByUserID(ctx context.Context, arg ListRecipesWithCuisinesByUserIDParams) ([]ListRecipesWithCuisinesByUserIDRow, error) {
-	rows, err := q.db.Query(ctx, listRecipesWithCuisinesByUserID, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listRecipesWithCuisinesByUserID,
+		arg.UserID,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -223,7 +397,14 @@ func (q *Queries) ListRecipesWithCuisinesByUserID(ctx context.Context, arg ListR
 			&i.Notes,
 			&i.UserID,
 			&i.ExternalImageUrl,
+			&i.SiteName,
+			&i.VideoUrl,
+			&i.DeletedAt,
 			&i.Cuisines,
+			&i.LastCookedAt,
+			&i.TimesCooked,
+			&i.AverageRating,
+			&i.Favorite,
 		); err != nil {
 			return nil, err
 		}
@@ -235,22 +416,97 @@ func (q *Queries) ListRecipesWithCuisinesByUserID(ctx context.Context, arg ListR
 	return items, nil
 }
 
-const saveExternalImageURL = `-- name: SaveExternalImageURL :exec
+const purgeDeletedRecipes = `-- name: PurgeDeletedRecipes :many
+DELETE FROM recipes
+WHERE deleted_at < $1
+RETURNING id
+`
+
+func (q *Queries) PurgeDeletedRecipes(ctx context.Context, deletedAt *time.Time) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedRecipes, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreDeletedRecipe = `-- name: RestoreDeletedRecipe :execrows
 UPDATE recipes
-SET external_image_url = $2
+SET deleted_at = NULL
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+`
+
+type RestoreDeletedRecipeParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RestoreDeletedRecipe(ctx context.Context, arg RestoreDeletedRecipeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreDeletedRecipe, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const saveExternalMetadata = `-- name: SaveExternalMetadata :exec
+UPDATE recipes
+SET
+  external_image_url = $2,
+  site_name = $3,
+  video_url = $4
 WHERE id = $1
 `
 
-type SaveExternalImageURLParams struct {
+type SaveExternalMetadataParams struct {
 	ID               uuid.UUID `json:"id"`
 	ExternalImageUrl *string   `json:"external_image_url"`
+	SiteName         *string   `json:"site_name"`
+	VideoUrl         *string   `json:"video_url"`
 }
 
-func (q *Queries) SaveExternalImageURL(ctx context.Context, arg SaveExternalImageURLParams) error {
-	_, err := q.db.Exec(ctx, saveExternalImageURL, arg.ID, arg.ExternalImageUrl)
+func (q *Queries) SaveExternalMetadata(ctx context.Context, arg SaveExternalMetadataParams) error {
+	_, err := q.db.Exec(ctx, saveExternalMetadata,
+		arg.ID,
+		arg.ExternalImageUrl,
+		arg.SiteName,
+		arg.VideoUrl,
+	)
 	return err
 }
 
+const softDeleteRecipe = `-- name: SoftDeleteRecipe :execrows
+UPDATE recipes
+SET deleted_at = $2
+WHERE id = $1 AND user_id = $3 AND deleted_at IS NULL
+`
+
+type SoftDeleteRecipeParams struct {
+	ID        uuid.UUID  `json:"id"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+}
+
+func (q *Queries) SoftDeleteRecipe(ctx context.Context, arg SoftDeleteRecipeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteRecipe, arg.ID, arg.DeletedAt, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateRecipeByID = `-- name: UpdateRecipeByID :one
 UPDATE recipes
 SET
@@ -262,8 +518,8 @@ SET
   yield = $6,
   cook_time_in_minutes = $7,
   notes = $8
-WHERE id = $1
-RETURNING id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, created_at, updated_at, external_url, name, description, servings, yield, cook_time_in_minutes, notes, user_id, external_image_url, site_name, video_url, deleted_at
 `
 
 type UpdateRecipeByIDParams struct {
@@ -304,6 +560,9 @@ func (q *Queries) UpdateRecipeByID(ctx context.Context, arg UpdateRecipeByIDPara
 		&i.Notes,
 		&i.UserID,
 		&i.ExternalImageUrl,
+		&i.SiteName,
+		&i.VideoUrl,
+		&i.DeletedAt,
 	)
 	return i, err
 }
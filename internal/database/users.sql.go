@@ -15,7 +15,7 @@ import (
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, created_at, updated_at, name, email, hash)
 VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, created_at, updated_at, name, email, hash
+RETURNING id, created_at, updated_at, name, email, hash, is_admin
 `
 
 type CreateUserParams struct {
@@ -44,6 +44,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Name,
 		&i.Email,
 		&i.Hash,
+		&i.IsAdmin,
 	)
 	return i, err
 }
@@ -59,7 +60,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, created_at, updated_at, name, email, hash FROM users
+SELECT id, created_at, updated_at, name, email, hash, is_admin FROM users
 WHERE email = $1
 `
 
@@ -73,12 +74,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Name,
 		&i.Email,
 		&i.Hash,
+		&i.IsAdmin,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, created_at, updated_at, name, email, hash FROM users
+SELECT id, created_at, updated_at, name, email, hash, is_admin FROM users
 WHERE id = $1
 `
 
@@ -92,15 +94,33 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Name,
 		&i.Email,
 		&i.Hash,
+		&i.IsAdmin,
 	)
 	return i, err
 }
 
+const updateUserAdminByID = `-- name: UpdateUserAdminByID :exec
+UPDATE users
+SET is_admin = $2, updated_at = $3
+WHERE id = $1
+`
+
+type UpdateUserAdminByIDParams struct {
+	ID        uuid.UUID `json:"id"`
+	IsAdmin   bool      `json:"is_admin"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserAdminByID(ctx context.Context, arg UpdateUserAdminByIDParams) error {
+	_, err := q.db.Exec(ctx, updateUserAdminByID, arg.ID, arg.IsAdmin, arg.UpdatedAt)
+	return err
+}
+
 const updateUserByID = `-- name: UpdateUserByID :one
 UPDATE users
 SET name = $2, hash = $3, updated_at = $4
 WHERE id = $1
-RETURNING id, created_at, updated_at, name, email, hash
+RETURNING id, created_at, updated_at, name, email, hash, is_admin
 `
 
 type UpdateUserByIDParams struct {
@@ -125,6 +145,7 @@ func (q *Queries) UpdateUserByID(ctx context.Context, arg UpdateUserByIDParams)
 		&i.Name,
 		&i.Email,
 		&i.Hash,
+		&i.IsAdmin,
 	)
 	return i, err
 }
@@ -0,0 +1,180 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// encodeCooklang writes the recipe in https://cooklang.org. Ingredients are
+// marked where a step first mentions them, those never mentioned are
+// gathered in a step of their own before the others.
+func encodeCooklang(w io.Writer, r models.Recipe) error {
+	bw := bufio.NewWriter(w)
+
+	meta := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(bw, ">> %s: %s\n", key, oneLine(value))
+		}
+	}
+	meta("title", r.Name)
+	meta("description", stringValue(r.Description))
+	meta("source", stringValue(r.ExternalURL))
+	if r.Servings > 0 {
+		meta("servings", fmt.Sprint(r.Servings))
+	}
+	meta("yield", stringValue(r.Yield))
+	if r.CookTimeInMinutes > 0 {
+		meta("cook time", fmt.Sprintf("%d minutes", r.CookTimeInMinutes))
+	}
+	meta("cuisine", strings.Join(cuisineNames(r), ", "))
+
+	ingredients := sortedIngredients(r)
+	used := make([]bool, len(ingredients))
+	var steps []string
+	for _, i := range sortedInstructions(r) {
+		steps = append(steps, markIngredients(i.Instruction, ingredients, used))
+	}
+
+	var unused []string
+	for n, i := range ingredients {
+		if !used[n] {
+			unused = append(unused, cooklangIngredient(i))
+		}
+	}
+	if len(unused) > 0 {
+		steps = append([]string{"Gather " + strings.Join(unused, ", ") + "."}, steps...)
+	}
+
+	for _, s := range steps {
+		fmt.Fprintf(bw, "\n%s\n", s)
+	}
+
+	if notes := stringValue(r.Notes); notes != "" {
+		fmt.Fprint(bw, "\n")
+		for _, line := range strings.Split(strings.TrimSpace(notes), "\n") {
+			fmt.Fprintf(bw, "> %s\n", strings.TrimSpace(line))
+		}
+	}
+
+	return bw.Flush()
+}
+
+type mention struct {
+	start, end int
+	ingredient int
+}
+
+// markIngredients turns the first mention of each ingredient not yet used
+// into a Cooklang ingredient. Longer names are matched first, so that
+// "black pepper" is not taken for "pepper".
+func markIngredients(step string, ingredients []models.IngredientInRecipe, used []bool) string {
+	order := make([]int, len(ingredients))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(ingredients[order[a]].Name) > len(ingredients[order[b]].Name)
+	})
+
+	lower := strings.ToLower(step)
+	var mentions []mention
+	for _, n := range order {
+		name := strings.ToLower(strings.TrimSpace(ingredients[n].Name))
+		if used[n] || name == "" {
+			continue
+		}
+		for from := 0; from < len(lower); {
+			idx := strings.Index(lower[from:], name)
+			if idx < 0 {
+				break
+			}
+			start := from + idx
+			end := start + len(name)
+			from = start + 1
+			if !isWordBoundary(lower, start, end) || overlaps(mentions, start, end) {
+				continue
+			}
+			mentions = append(mentions, mention{start: start, end: end, ingredient: n})
+			used[n] = true
+			break
+		}
+	}
+	sort.Slice(mentions, func(a, b int) bool { return mentions[a].start < mentions[b].start })
+
+	var b strings.Builder
+	last := 0
+	for _, m := range mentions {
+		b.WriteString(escapeCooklang(step[last:m.start]))
+		b.WriteString(cooklangIngredient(ingredients[m.ingredient]))
+		last = m.end
+	}
+	b.WriteString(escapeCooklang(step[last:]))
+	return oneLine(b.String())
+}
+
+func isWordBoundary(s string, start, end int) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(s[:start]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if end < len(s) {
+		if r, _ := utf8.DecodeRuneInString(s[end:]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func overlaps(mentions []mention, start, end int) bool {
+	for _, m := range mentions {
+		if start < m.end && m.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// cooklangIngredient writes @name{quantity%unit}(prep note). The braces are
+// always written, so that names of several words are read whole.
+func cooklangIngredient(i models.IngredientInRecipe) string {
+	s := "@" + escapeCooklang(strings.TrimSpace(i.Name)) + "{" + cooklangAmount(i.Amount) + "}"
+	if note := stringValue(i.PrepNote); note != "" {
+		s += "(" + strings.NewReplacer("(", "", ")", "").Replace(note) + ")"
+	}
+	return s
+}
+
+// cooklangAmount splits an amount such as "2 cups" into quantity and unit.
+// Amounts that don't start with a number are kept as the quantity.
+func cooklangAmount(amount string) string {
+	amount = strings.NewReplacer("{", "", "}", "", "%", "").Replace(strings.TrimSpace(amount))
+	qty, unit, found := strings.Cut(amount, " ")
+	if !found || !startsWithDigit(qty) {
+		return amount
+	}
+	return qty + "%" + strings.TrimSpace(unit)
+}
+
+func startsWithDigit(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsDigit(r)
+}
+
+var cooklangEscaper = strings.NewReplacer("@", `\@`, "#", `\#`, "~", `\~`)
+
+func escapeCooklang(s string) string {
+	return cooklangEscaper.Replace(s)
+}
+
+// oneLine keeps text on a single line, a line break ends a Cooklang step.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,180 @@
+// Package export writes recipes in the formats they can be taken out of the
+// app in.
+package export
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// DefaultFormat is used when no format is asked for.
+const DefaultFormat = "json"
+
+var ErrUnknownFormat = errors.New("export: unknown format")
+
+// Encoder writes a single recipe in some format.
+type Encoder interface {
+	Encode(w io.Writer, r models.Recipe) error
+}
+
+// EncoderFunc lets a plain function be used as an Encoder.
+type EncoderFunc func(w io.Writer, r models.Recipe) error
+
+func (f EncoderFunc) Encode(w io.Writer, r models.Recipe) error {
+	return f(w, r)
+}
+
+// Format is an Encoder along with what is needed to serve or store its
+// output.
+type Format struct {
+	Name        string
+	ContentType string
+	// Extension of the files written in this format, without the dot.
+	Extension string
+	Encoder
+}
+
+var formats = map[string]Format{}
+
+// Register makes a format available under its name. Registering a name
+// twice replaces the earlier format.
+func Register(f Format) {
+	formats[f.Name] = f
+}
+
+// Lookup returns the format registered under name, or DefaultFormat when
+// name is empty.
+func Lookup(name string) (Format, error) {
+	if name == "" {
+		name = DefaultFormat
+	}
+	f, ok := formats[strings.ToLower(name)]
+	if !ok {
+		return Format{}, fmt.Errorf("%w %q, use one of: %s", ErrUnknownFormat, name, strings.Join(Names(), ", "))
+	}
+	return f, nil
+}
+
+// Names lists the registered formats.
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(Format{Name: "json", ContentType: "application/json", Extension: "json", Encoder: EncoderFunc(encodeJSON)})
+	Register(Format{Name: "markdown", ContentType: "text/markdown; charset=utf-8", Extension: "md", Encoder: EncoderFunc(encodeMarkdown)})
+	Register(Format{Name: "cooklang", ContentType: "text/plain; charset=utf-8", Extension: "cook", Encoder: EncoderFunc(encodeCooklang)})
+	Register(Format{Name: "jsonld", ContentType: "application/ld+json", Extension: "jsonld", Encoder: EncoderFunc(encodeJSONLD)})
+	Register(Format{Name: "text", ContentType: "text/plain; charset=utf-8", Extension: "txt", Encoder: EncoderFunc(encodeText)})
+}
+
+// Filename is the name of the file holding r in format f.
+func (f Format) Filename(r models.Recipe) string {
+	return Slug(r.Name) + "." + f.Extension
+}
+
+// WriteZip writes a zip archive with one file per recipe. Recipes that share
+// a name get the first numbered suffix no other file has.
+func WriteZip(w io.Writer, f Format, recipes []models.Recipe) error {
+	zw := zip.NewWriter(w)
+	used := map[string]bool{}
+	// next is the suffix to try first for a name, so that many recipes of
+	// the same name don't each count up from 2.
+	next := map[string]int{}
+	for _, r := range recipes {
+		slug := Slug(r.Name)
+		name := slug
+		for n := max(next[slug], 2); used[name]; n++ {
+			name = fmt.Sprintf("%s-%d", slug, n)
+			next[slug] = n + 1
+		}
+		used[name] = true
+
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     name + "." + f.Extension,
+			Method:   zip.Deflate,
+			Modified: r.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := f.Encode(fw, r); err != nil {
+			return fmt.Errorf("recipe %s: %w", r.ID, err)
+		}
+	}
+	return zw.Close()
+}
+
+// Slug turns a recipe name into something safe to use as a file name.
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(name) {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(c)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		return "recipe"
+	}
+	return b.String()
+}
+
+// sortedIngredients returns the ingredients of r in their order in the
+// recipe.
+func sortedIngredients(r models.Recipe) []models.IngredientInRecipe {
+	ingredients := append([]models.IngredientInRecipe(nil), r.Ingredients...)
+	sort.SliceStable(ingredients, func(i, j int) bool {
+		return ingredients[i].Index < ingredients[j].Index
+	})
+	return ingredients
+}
+
+func sortedInstructions(r models.Recipe) []models.InstructionInRecipe {
+	instructions := append([]models.InstructionInRecipe(nil), r.Instructions...)
+	sort.SliceStable(instructions, func(i, j int) bool {
+		return instructions[i].StepNo < instructions[j].StepNo
+	})
+	return instructions
+}
+
+func ingredientLine(i models.IngredientInRecipe) string {
+	s := strings.TrimSpace(i.Amount + " " + i.Name)
+	if i.PrepNote != nil && *i.PrepNote != "" {
+		s += ", " + *i.PrepNote
+	}
+	return s
+}
+
+func cuisineNames(r models.Recipe) []string {
+	names := make([]string, len(r.Cuisines))
+	for i, c := range r.Cuisines {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
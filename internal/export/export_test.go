@@ -0,0 +1,142 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func ptr(s string) *string {
+	return &s
+}
+
+// goldenRecipe has every field set, with ingredients and instructions out
+// of order and names that need escaping in some formats.
+var goldenRecipe = models.Recipe{
+	ID:                uuid.MustParse("6f1c0b5e-2d1a-4c3b-9e8f-7a6b5c4d3e2f"),
+	CreatedAt:         time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC),
+	UpdatedAt:         time.Date(2024, 3, 2, 9, 15, 0, 0, time.UTC),
+	Name:              "Chicken & Leek Pie",
+	ExternalURL:       ptr("https://example.com/recipes/chicken-leek-pie"),
+	ExternalImageURL:  ptr("https://example.com/images/pie.jpg"),
+	SiteName:          ptr("Example Kitchen"),
+	VideoURL:          ptr("https://example.com/videos/pie"),
+	Description:       ptr("A weeknight pie with a flaky lid."),
+	UserID:            uuid.MustParse("0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+	Servings:          4,
+	Yield:             ptr("1 pie"),
+	CookTimeInMinutes: 55,
+	Notes:             ptr("Thaw the pastry the night before.\nKeeps 2 days in the fridge."),
+	Version:           models.RecipeVersion(time.Date(2024, 3, 2, 9, 15, 0, 0, time.UTC)),
+	Cuisines: []models.CuisineInRecipe{
+		{ID: uuid.MustParse("11111111-1111-4111-8111-111111111111"), Name: "British"},
+	},
+	Ingredients: []models.IngredientInRecipe{
+		{ID: uuid.MustParse("22222222-2222-4222-8222-222222222222"), Name: "leek", Amount: "2", PrepNote: ptr("sliced"), Index: 1},
+		{ID: uuid.MustParse("33333333-3333-4333-8333-333333333333"), Name: "chicken thighs", Amount: "500 g", Index: 0},
+		{ID: uuid.MustParse("44444444-4444-4444-8444-444444444444"), Name: "puff pastry", Amount: "1 sheet", Index: 2},
+	},
+	Instructions: []models.InstructionInRecipe{
+		{StepNo: 2, Instruction: "Add the leek; cook until soft."},
+		{StepNo: 1, Instruction: "Brown the chicken thighs in a pan."},
+		{StepNo: 3, Instruction: "Cover with the puff pastry and bake for 30 minutes."},
+	},
+}
+
+// TestGolden encodes goldenRecipe in every registered format and compares
+// it to testdata/recipe.<extension>. Run with -update after a change to a
+// format.
+func TestGolden(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			f, err := Lookup(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := f.Encode(&buf, goldenRecipe); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "recipe."+f.Extension)
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("%s differs from the output:\n%s", golden, buf.String())
+			}
+		})
+	}
+}
+
+func TestWriteZipNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		recipes []string
+		want    []string
+	}{
+		{
+			name:    "unique",
+			recipes: []string{"Soup", "Stew"},
+			want:    []string{"soup.txt", "stew.txt"},
+		},
+		{
+			name:    "same name",
+			recipes: []string{"Soup", "Soup", "soup!"},
+			want:    []string{"soup.txt", "soup-2.txt", "soup-3.txt"},
+		},
+		{
+			name:    "suffix taken by a name",
+			recipes: []string{"Soup", "Soup", "Soup 2"},
+			want:    []string{"soup.txt", "soup-2.txt", "soup-2-2.txt"},
+		},
+		{
+			name:    "name taken by a suffix",
+			recipes: []string{"Soup 2", "Soup", "Soup"},
+			want:    []string{"soup-2.txt", "soup.txt", "soup-3.txt"},
+		},
+	}
+	f, err := Lookup("text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recipes []models.Recipe
+			for _, name := range tt.recipes {
+				recipes = append(recipes, models.Recipe{ID: uuid.New(), Name: name})
+			}
+			var buf bytes.Buffer
+			if err := WriteZip(&buf, f, recipes); err != nil {
+				t.Fatal(err)
+			}
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(zr.File) != len(tt.want) {
+				t.Fatalf("got %d files, want %d", len(zr.File), len(tt.want))
+			}
+			for i, zf := range zr.File {
+				if zf.Name != tt.want[i] {
+					t.Errorf("file %d: got %q, want %q", i, zf.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// encodeJSON writes the recipe as the API returns it, which is also what
+// the recipe import command reads. Ingredients and instructions are put in
+// order, so that the same recipe is always written the same way.
+func encodeJSON(w io.Writer, r models.Recipe) error {
+	r.Ingredients = sortedIngredients(r)
+	r.Instructions = sortedInstructions(r)
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// schemaRecipe is a https://schema.org/Recipe, as recipe sites embed in
+// their pages.
+type schemaRecipe struct {
+	Context            string       `json:"@context"`
+	Type               string       `json:"@type"`
+	Name               string       `json:"name"`
+	Description        string       `json:"description,omitempty"`
+	URL                string       `json:"url,omitempty"`
+	Image              string       `json:"image,omitempty"`
+	DateCreated        string       `json:"dateCreated"`
+	DateModified       string       `json:"dateModified"`
+	RecipeYield        []string     `json:"recipeYield,omitempty"`
+	CookTime           string       `json:"cookTime,omitempty"`
+	RecipeCuisine      []string     `json:"recipeCuisine,omitempty"`
+	RecipeIngredient   []string     `json:"recipeIngredient"`
+	RecipeInstructions []schemaStep `json:"recipeInstructions"`
+	Comment            string       `json:"comment,omitempty"`
+}
+
+type schemaStep struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Text     string `json:"text"`
+}
+
+func encodeJSONLD(w io.Writer, r models.Recipe) error {
+	sr := schemaRecipe{
+		Context:            "https://schema.org",
+		Type:               "Recipe",
+		Name:               r.Name,
+		Description:        stringValue(r.Description),
+		URL:                stringValue(r.ExternalURL),
+		Image:              stringValue(r.ExternalImageURL),
+		DateCreated:        r.CreatedAt.UTC().Format(time.RFC3339),
+		DateModified:       r.UpdatedAt.UTC().Format(time.RFC3339),
+		RecipeCuisine:      cuisineNames(r),
+		RecipeIngredient:   []string{},
+		RecipeInstructions: []schemaStep{},
+		Comment:            stringValue(r.Notes),
+	}
+
+	if r.Servings > 0 {
+		sr.RecipeYield = append(sr.RecipeYield, strconv.Itoa(r.Servings))
+	}
+	if y := stringValue(r.Yield); y != "" {
+		sr.RecipeYield = append(sr.RecipeYield, y)
+	}
+	if r.CookTimeInMinutes > 0 {
+		sr.CookTime = fmt.Sprintf("PT%dM", r.CookTimeInMinutes)
+	}
+	for _, i := range sortedIngredients(r) {
+		sr.RecipeIngredient = append(sr.RecipeIngredient, ingredientLine(i))
+	}
+	for n, i := range sortedInstructions(r) {
+		sr.RecipeInstructions = append(sr.RecipeInstructions, schemaStep{Type: "HowToStep", Position: n + 1, Text: i.Instruction})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sr)
+}
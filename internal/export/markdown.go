@@ -0,0 +1,59 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+func encodeMarkdown(w io.Writer, r models.Recipe) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# %s\n\n", r.Name)
+	if d := stringValue(r.Description); d != "" {
+		fmt.Fprintf(bw, "%s\n\n", d)
+	}
+
+	if r.Servings > 0 {
+		fmt.Fprintf(bw, "- **Servings:** %d\n", r.Servings)
+	}
+	if y := stringValue(r.Yield); y != "" {
+		fmt.Fprintf(bw, "- **Yield:** %s\n", y)
+	}
+	if r.CookTimeInMinutes > 0 {
+		fmt.Fprintf(bw, "- **Cook time:** %d minutes\n", r.CookTimeInMinutes)
+	}
+	if len(r.Cuisines) > 0 {
+		fmt.Fprintf(bw, "- **Cuisines:** %s\n", strings.Join(cuisineNames(r), ", "))
+	}
+	if u := stringValue(r.ExternalURL); u != "" {
+		source := u
+		if site := stringValue(r.SiteName); site != "" {
+			source = site
+		}
+		fmt.Fprintf(bw, "- **Source:** [%s](%s)\n", source, u)
+	}
+
+	if len(r.Ingredients) > 0 {
+		fmt.Fprint(bw, "\n## Ingredients\n\n")
+		for _, i := range sortedIngredients(r) {
+			fmt.Fprintf(bw, "- %s\n", ingredientLine(i))
+		}
+	}
+
+	if len(r.Instructions) > 0 {
+		fmt.Fprint(bw, "\n## Instructions\n\n")
+		for n, i := range sortedInstructions(r) {
+			fmt.Fprintf(bw, "%d. %s\n", n+1, i.Instruction)
+		}
+	}
+
+	if notes := stringValue(r.Notes); notes != "" {
+		fmt.Fprintf(bw, "\n## Notes\n\n%s\n", notes)
+	}
+
+	return bw.Flush()
+}
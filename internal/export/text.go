@@ -0,0 +1,55 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+func encodeText(w io.Writer, r models.Recipe) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "%s\n%s\n\n", r.Name, strings.Repeat("=", len([]rune(r.Name))))
+	if d := stringValue(r.Description); d != "" {
+		fmt.Fprintf(bw, "%s\n\n", d)
+	}
+
+	if r.Servings > 0 {
+		fmt.Fprintf(bw, "Servings: %d\n", r.Servings)
+	}
+	if y := stringValue(r.Yield); y != "" {
+		fmt.Fprintf(bw, "Yield: %s\n", y)
+	}
+	if r.CookTimeInMinutes > 0 {
+		fmt.Fprintf(bw, "Cook time: %d minutes\n", r.CookTimeInMinutes)
+	}
+	if len(r.Cuisines) > 0 {
+		fmt.Fprintf(bw, "Cuisines: %s\n", strings.Join(cuisineNames(r), ", "))
+	}
+	if u := stringValue(r.ExternalURL); u != "" {
+		fmt.Fprintf(bw, "Source: %s\n", u)
+	}
+
+	if len(r.Ingredients) > 0 {
+		fmt.Fprint(bw, "\nIngredients\n\n")
+		for _, i := range sortedIngredients(r) {
+			fmt.Fprintf(bw, "  * %s\n", ingredientLine(i))
+		}
+	}
+
+	if len(r.Instructions) > 0 {
+		fmt.Fprint(bw, "\nInstructions\n\n")
+		for n, i := range sortedInstructions(r) {
+			fmt.Fprintf(bw, "  %d. %s\n", n+1, i.Instruction)
+		}
+	}
+
+	if notes := stringValue(r.Notes); notes != "" {
+		fmt.Fprintf(bw, "\nNotes\n\n%s\n", notes)
+	}
+
+	return bw.Flush()
+}
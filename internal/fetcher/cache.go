@@ -0,0 +1,94 @@
+package fetcher
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+)
+
+// cache keeps the latest responses that carry a validator, so that they can
+// be revalidated with a conditional request instead of downloaded again.
+type cache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	url          string
+	etag         string
+	lastModified string
+	resp         Response
+}
+
+func newCache(size int) *cache {
+	return &cache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+func (c *cache) get(url string) (cacheEntry, bool) {
+	if c == nil {
+		return cacheEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[url]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(cacheEntry), true
+}
+
+// put stores resp if it can be revalidated later.
+func (c *cache) put(url string, resp Response) {
+	if c == nil {
+		return
+	}
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" || resp.Header.Get("Cache-Control") == "no-store" {
+		c.remove(url)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := cacheEntry{url: url, etag: etag, lastModified: lastModified, resp: resp}
+	if el, ok := c.entries[url]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[url] = c.lru.PushFront(e)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(cacheEntry).url)
+	}
+}
+
+func (c *cache) remove(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[url]; ok {
+		c.lru.Remove(el)
+		delete(c.entries, url)
+	}
+}
+
+func (e cacheEntry) setValidators(req *http.Request) {
+	if e.etag != "" {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		req.Header.Set("If-Modified-Since", e.lastModified)
+	}
+}
@@ -0,0 +1,88 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetETag(t *testing.T) {
+	const etag = `"v1"`
+	var conditional, full int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Write([]byte("soup"))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		cacheSize     int
+		wantFromCache bool
+		wantFull      int
+	}{
+		{name: "cached", cacheSize: 1, wantFromCache: true, wantFull: 1},
+		{name: "no cache", cacheSize: 0, wantFull: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditional, full = 0, 0
+			f := newTestFetcher(t, srv, WithCacheSize(tt.cacheSize))
+
+			first, err := f.Get(context.Background(), srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first.FromCache {
+				t.Error("first response is from the cache")
+			}
+			second, err := f.Get(context.Background(), srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if second.FromCache != tt.wantFromCache {
+				t.Errorf("second response from cache: got %v, want %v", second.FromCache, tt.wantFromCache)
+			}
+			if string(second.Body) != "soup" {
+				t.Errorf("got body %q, want soup", second.Body)
+			}
+			if full != tt.wantFull || conditional != 2-tt.wantFull {
+				t.Errorf("got %d full and %d conditional requests, want %d full", full, conditional, tt.wantFull)
+			}
+		})
+	}
+}
+
+func TestCacheNoValidator(t *testing.T) {
+	c := newCache(2)
+	c.put("a", Response{Header: http.Header{"Etag": {`"a"`}}})
+	c.put("a", Response{Header: http.Header{}})
+	if _, ok := c.get("a"); ok {
+		t.Error("a response without validators replaced a cached one and stayed cached")
+	}
+	c.put("b", Response{Header: http.Header{"Etag": {`"b"`}, "Cache-Control": {"no-store"}}})
+	if _, ok := c.get("b"); ok {
+		t.Error("a no-store response was cached")
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache(2)
+	for _, url := range []string{"a", "b"} {
+		c.put(url, Response{Header: http.Header{"Etag": {`"` + url + `"`}}})
+	}
+	c.get("a")
+	c.put("c", Response{Header: http.Header{"Etag": {`"c"`}}})
+	for url, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := c.get(url); ok != want {
+			t.Errorf("%s cached: got %v, want %v", url, ok, want)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Package fetcher fetches external content, such as the recipe pages users
+// link to, without letting those URLs reach into our own network.
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	defaultUserAgent    = "Mozilla/5.0 (compatible; MealOrgBot/1.0; +https://github.com/quangd42/meal-org)"
+	defaultTimeout      = 10 * time.Second
+	defaultMaxBytes     = 5 << 20
+	defaultMaxRedirects = 5
+	defaultPerHost      = 2
+	defaultHostDelay    = 500 * time.Millisecond
+	defaultCacheSize    = 64
+	// Larger responses are not cached, to bound the memory of the cache
+	maxCachedBytes = 1 << 20
+)
+
+var (
+	ErrUnsupportedURL      = errors.New("fetcher: only absolute http and https URLs are supported")
+	ErrTooLarge            = errors.New("fetcher: response is too large")
+	ErrTooManyRedirects    = errors.New("fetcher: too many redirects")
+	ErrUnsupportedEncoding = errors.New("fetcher: unsupported content encoding")
+)
+
+// StatusError is returned for responses that are not 2xx.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("fetcher: %s responded %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+type Response struct {
+	// URL is the final URL, after redirects.
+	URL        *url.URL
+	StatusCode int
+	Header     http.Header
+	// Body is decoded from its Content-Encoding, but not its charset.
+	Body []byte
+	// FromCache is set when the server confirmed the cached copy is current.
+	FromCache bool
+}
+
+// MediaType returns the media type of the response, without parameters.
+func (r Response) MediaType() string {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mt
+}
+
+// Text returns the body converted to UTF-8, using the charset from the
+// Content-Type header or, for HTML, the document itself.
+func (r Response) Text() (io.Reader, error) {
+	return charset.NewReader(bytes.NewReader(r.Body), r.Header.Get("Content-Type"))
+}
+
+type Fetcher struct {
+	client    *http.Client
+	limiter   *hostLimiter
+	cache     *cache
+	maxBytes  int64
+	userAgent string
+}
+
+type Option func(*Fetcher)
+
+// WithTimeout limits the time of a whole request, redirects included.
+func WithTimeout(d time.Duration) Option {
+	return func(f *Fetcher) { f.client.Timeout = d }
+}
+
+// WithMaxBytes limits the size of response bodies, before and after
+// decompression.
+func WithMaxBytes(n int64) Option {
+	return func(f *Fetcher) { f.maxBytes = n }
+}
+
+func WithUserAgent(ua string) Option {
+	return func(f *Fetcher) { f.userAgent = ua }
+}
+
+// WithHostLimit allows perHost concurrent requests to a host, and starts them
+// at least delay apart.
+func WithHostLimit(perHost int, delay time.Duration) Option {
+	return func(f *Fetcher) { f.limiter = newHostLimiter(perHost, delay) }
+}
+
+// WithCacheSize sets the number of responses kept for revalidation, 0
+// disables the cache.
+func WithCacheSize(n int) Option {
+	return func(f *Fetcher) {
+		f.cache = nil
+		if n > 0 {
+			f.cache = newCache(n)
+		}
+	}
+}
+
+func New(opts ...Option) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialControl,
+	}
+	transport := &http.Transport{
+		// Never go through a proxy, it would dial for us and skip the checks
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: defaultTimeout,
+		// Decoding is done by the fetcher, so that the size limit applies
+		DisableCompression: true,
+	}
+
+	f := &Fetcher{
+		client: &http.Client{
+			Transport:     transport,
+			Timeout:       defaultTimeout,
+			CheckRedirect: checkRedirect,
+		},
+		limiter:   newHostLimiter(defaultPerHost, defaultHostDelay),
+		cache:     newCache(defaultCacheSize),
+		maxBytes:  defaultMaxBytes,
+		userAgent: defaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= defaultMaxRedirects {
+		return ErrTooManyRedirects
+	}
+	return checkURL(req.URL)
+}
+
+func checkURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrUnsupportedURL
+	}
+	return nil
+}
+
+// Get fetches rawURL. Responses that are not 2xx are returned as a
+// *StatusError.
+func (f *Fetcher) Get(ctx context.Context, rawURL string) (Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Response{}, fmt.Errorf("%w: %s", ErrUnsupportedURL, err)
+	}
+	if err := checkURL(u); err != nil {
+		return Response{}, err
+	}
+
+	release, err := f.limiter.acquire(ctx, u.Hostname())
+	if err != nil {
+		return Response{}, err
+	}
+	defer release()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Response{}, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "*/*")
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	cached, hasCached := f.cache.get(u.String())
+	if hasCached {
+		cached.setValidators(req)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		r := cached.resp
+		r.FromCache = true
+		return r, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Response{}, &StatusError{URL: u.String(), StatusCode: resp.StatusCode}
+	}
+
+	body, err := f.readBody(resp)
+	if err != nil {
+		return Response{}, err
+	}
+
+	r := Response{
+		URL:        resp.Request.URL,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+	if len(body) <= maxCachedBytes {
+		f.cache.put(u.String(), r)
+	}
+	return r, nil
+}
+
+// readBody decodes the body according to its Content-Encoding. The limit
+// applies to both the encoded and the decoded size.
+func (f *Fetcher) readBody(resp *http.Response) ([]byte, error) {
+	encoded := &io.LimitedReader{R: resp.Body, N: f.maxBytes + 1}
+	var r io.Reader = encoded
+
+	switch enc := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); enc {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	case "deflate":
+		// Servers send either zlib, as the spec says, or raw deflate
+		br := bufio.NewReader(r)
+		header, _ := br.Peek(2)
+		if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, err
+			}
+			defer zr.Close()
+			r = zr
+		} else {
+			fr := flate.NewReader(br)
+			defer fr.Close()
+			r = fr
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, enc)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, f.maxBytes+1))
+	if encoded.N <= 0 || int64(len(body)) > f.maxBytes {
+		return nil, ErrTooLarge
+	}
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
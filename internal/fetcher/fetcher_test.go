@@ -0,0 +1,217 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newTestFetcher returns a fetcher that may dial srv, on loopback, but still
+// refuses every other address dialControl refuses.
+func newTestFetcher(t *testing.T, srv *httptest.Server, opts ...Option) *Fetcher {
+	t.Helper()
+	allowed := srv.Listener.Addr().String()
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			if address == allowed {
+				return nil
+			}
+			return dialControl(network, address, c)
+		},
+	}
+	opts = append([]Option{WithHostLimit(defaultPerHost, 0)}, opts...)
+	f := New(opts...)
+	f.client.Transport.(*http.Transport).DialContext = dialer.DialContext
+	return f
+}
+
+func TestGetBlocksLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the request reached the server")
+	}))
+	defer srv.Close()
+
+	_, err := New(WithHostLimit(defaultPerHost, 0)).Get(context.Background(), srv.URL)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("got %v, want ErrBlockedAddress", err)
+	}
+}
+
+func TestGetBlocksRedirectToLoopback(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the redirect reached the internal server")
+	}))
+	defer internal.Close()
+	public := httptest.NewServer(http.RedirectHandler(internal.URL+"/admin", http.StatusFound))
+	defer public.Close()
+
+	_, err := newTestFetcher(t, public).Get(context.Background(), public.URL)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("got %v, want ErrBlockedAddress", err)
+	}
+}
+
+func TestGetUnsupportedURL(t *testing.T) {
+	for _, u := range []string{"ftp://example.com/", "file:///etc/passwd", "/relative", "http://"} {
+		t.Run(u, func(t *testing.T) {
+			_, err := New().Get(context.Background(), u)
+			if !errors.Is(err, ErrUnsupportedURL) {
+				t.Errorf("got %v, want ErrUnsupportedURL", err)
+			}
+		})
+	}
+}
+
+func TestGetRedirectLimit(t *testing.T) {
+	// /n redirects to /n-1, down to /0 which answers.
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n, err := strconv.Atoi(r.URL.Path[1:])
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if n > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/%d", n-1), http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "done")
+	}))
+	defer srv.Close()
+	f := newTestFetcher(t, srv)
+
+	tests := []struct {
+		redirects int
+		wantErr   error
+		requests  int32
+	}{
+		{redirects: 0, requests: 1},
+		{redirects: defaultMaxRedirects - 1, requests: defaultMaxRedirects},
+		{redirects: defaultMaxRedirects, wantErr: ErrTooManyRedirects, requests: defaultMaxRedirects},
+		{redirects: 100, wantErr: ErrTooManyRedirects, requests: defaultMaxRedirects},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.redirects), func(t *testing.T) {
+			requests.Store(0)
+			resp, err := f.Get(context.Background(), fmt.Sprintf("%s/%d", srv.URL, tt.redirects))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if n := requests.Load(); n != tt.requests {
+				t.Errorf("sent %d requests, want %d", n, tt.requests)
+			}
+			if err == nil && resp.URL.Path != "/0" {
+				t.Errorf("got final URL %s, want /0", resp.URL)
+			}
+		})
+	}
+}
+
+func TestGetDecoding(t *testing.T) {
+	const maxBytes = 1 << 10
+
+	encoders := map[string]func([]byte) []byte{
+		"identity": func(b []byte) []byte { return b },
+		"gzip": func(b []byte) []byte {
+			var buf bytes.Buffer
+			w := gzip.NewWriter(&buf)
+			w.Write(b)
+			w.Close()
+			return buf.Bytes()
+		},
+		// zlib, as the spec says
+		"deflate": func(b []byte) []byte {
+			var buf bytes.Buffer
+			w := zlib.NewWriter(&buf)
+			w.Write(b)
+			w.Close()
+			return buf.Bytes()
+		},
+		// raw deflate, as some servers send
+		"raw deflate": func(b []byte) []byte {
+			var buf bytes.Buffer
+			w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+			w.Write(b)
+			w.Close()
+			return buf.Bytes()
+		},
+	}
+	noise := make([]byte, 2*maxBytes)
+	rand.Read(noise)
+
+	tests := []struct {
+		name    string
+		body    []byte
+		wantErr error
+	}{
+		{name: "small", body: []byte("<html>soup</html>")},
+		{name: "at the limit", body: bytes.Repeat([]byte("a"), maxBytes)},
+		// compresses well under the limit but decodes over it
+		{name: "bomb", body: make([]byte, 100*maxBytes), wantErr: ErrTooLarge},
+		// over the limit before decoding too
+		{name: "noise", body: noise, wantErr: ErrTooLarge},
+	}
+	for enc, encode := range encoders {
+		header := enc
+		if enc == "raw deflate" {
+			header = "deflate"
+		}
+		for _, tt := range tests {
+			t.Run(enc+" "+tt.name, func(t *testing.T) {
+				body := encode(tt.body)
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Encoding", header)
+					w.Write(body)
+				}))
+				defer srv.Close()
+
+				resp, err := newTestFetcher(t, srv, WithMaxBytes(maxBytes)).Get(context.Background(), srv.URL)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				if err == nil && !bytes.Equal(resp.Body, tt.body) {
+					t.Errorf("got body of %d bytes, want %d", len(resp.Body), len(tt.body))
+				}
+			})
+		}
+	}
+}
+
+func TestGetUnsupportedEncoding(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		w.Write([]byte("soup"))
+	}))
+	defer srv.Close()
+
+	_, err := newTestFetcher(t, srv).Get(context.Background(), srv.URL)
+	if !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("got %v, want ErrUnsupportedEncoding", err)
+	}
+}
+
+func TestGetStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := newTestFetcher(t, srv).Get(context.Background(), srv.URL)
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		t.Errorf("got %v, want a 404 StatusError", err)
+	}
+}
@@ -0,0 +1,70 @@
+package fetcher
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIsBlocked(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "127.0.0.1", want: true},
+		{addr: "127.255.0.1", want: true},
+		{addr: "::1", want: true},
+		{addr: "169.254.169.254", want: true},
+		{addr: "fe80::1", want: true},
+		{addr: "10.1.2.3", want: true},
+		{addr: "172.16.0.1", want: true},
+		{addr: "172.31.255.255", want: true},
+		{addr: "192.168.0.1", want: true},
+		{addr: "fd00::1", want: true},
+		{addr: "::ffff:127.0.0.1", want: true},
+		{addr: "::ffff:10.0.0.1", want: true},
+		{addr: "::ffff:169.254.169.254", want: true},
+		{addr: "100.64.0.1", want: true},
+		{addr: "100.127.255.254", want: true},
+		{addr: "0.0.0.0", want: true},
+		{addr: "::", want: true},
+		{addr: "224.0.0.1", want: true},
+		{addr: "64:ff9b::a00:1", want: true},
+		{addr: "8.8.8.8", want: false},
+		{addr: "172.32.0.1", want: false},
+		{addr: "100.128.0.1", want: false},
+		{addr: "::ffff:8.8.8.8", want: false},
+		{addr: "2606:4700:4700::1111", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := isBlocked(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	tests := []struct {
+		network string
+		address string
+		blocked bool
+	}{
+		{network: "tcp4", address: "127.0.0.1:80", blocked: true},
+		{network: "tcp6", address: "[::1]:443", blocked: true},
+		{network: "tcp6", address: "[::ffff:127.0.0.1]:80", blocked: true},
+		{network: "tcp4", address: "169.254.169.254:80", blocked: true},
+		{network: "udp4", address: "8.8.8.8:53", blocked: true},
+		{network: "tcp4", address: "8.8.8.8:443", blocked: false},
+		{network: "tcp6", address: "[2606:4700:4700::1111]:443", blocked: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.network+" "+tt.address, func(t *testing.T) {
+			err := dialControl(tt.network, tt.address, nil)
+			if got := errors.Is(err, ErrBlockedAddress); got != tt.blocked {
+				t.Errorf("got %v, want blocked %v", err, tt.blocked)
+			}
+		})
+	}
+}
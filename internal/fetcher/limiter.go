@@ -0,0 +1,93 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// hostLimiter caps the number of concurrent requests to a host and spaces
+// out the requests that start, to stay polite with the sites we fetch from.
+type hostLimiter struct {
+	perHost int
+	delay   time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostSlot
+}
+
+type hostSlot struct {
+	sem  chan struct{}
+	next time.Time
+	// users counts the requests holding or waiting for the slot, so that
+	// idle slots can be dropped.
+	users int
+}
+
+func newHostLimiter(perHost int, delay time.Duration) *hostLimiter {
+	return &hostLimiter{
+		perHost: perHost,
+		delay:   delay,
+		hosts:   map[string]*hostSlot{},
+	}
+}
+
+// acquire blocks until a request to host may start. The returned func must be
+// called once the request is done.
+func (l *hostLimiter) acquire(ctx context.Context, host string) (release func(), err error) {
+	l.mu.Lock()
+	slot, ok := l.hosts[host]
+	if !ok {
+		slot = &hostSlot{sem: make(chan struct{}, l.perHost)}
+		l.hosts[host] = slot
+	}
+	slot.users++
+	l.mu.Unlock()
+
+	done := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		slot.users--
+		if slot.users > 0 {
+			return
+		}
+		// Keep the slot until its delay is over, so that the next request
+		// still waits
+		time.AfterFunc(time.Until(slot.next), func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if slot.users == 0 && l.hosts[host] == slot {
+				delete(l.hosts, host)
+			}
+		})
+	}
+
+	select {
+	case slot.sem <- struct{}{}:
+	case <-ctx.Done():
+		done()
+		return nil, ctx.Err()
+	}
+
+	l.mu.Lock()
+	wait := time.Until(slot.next)
+	slot.next = time.Now().Add(max(wait, 0) + l.delay)
+	l.mu.Unlock()
+
+	if wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			<-slot.sem
+			done()
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		<-slot.sem
+		done()
+	}, nil
+}
@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHostLimiterDelay(t *testing.T) {
+	const delay = 50 * time.Millisecond
+	l := newHostLimiter(2, delay)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := l.acquire(ctx, "example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 2*delay {
+		t.Errorf("3 requests started within %s, want at least %s", elapsed, 2*delay)
+	}
+
+	// Other hosts don't wait.
+	start = time.Now()
+	release, err := l.acquire(ctx, "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("the first request to another host waited %s", elapsed)
+	}
+}
+
+func TestHostLimiterConcurrency(t *testing.T) {
+	l := newHostLimiter(1, 0)
+	release, err := l.acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second request: got %v, want it to wait for the first", err)
+	}
+
+	release()
+	release, err = l.acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("after release: %v", err)
+	}
+	release()
+}
@@ -4,7 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"mime"
 	"net/http"
 
 	"github.com/quangd42/meal-org/internal/services"
@@ -13,7 +14,7 @@ import (
 func respondJSON[T any](w http.ResponseWriter, code int, v T) {
 	data, err := json.Marshal(v)
 	if err != nil {
-		log.Printf("error decoding JSON: %s\n", err)
+		slog.Error("error encoding JSON", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -23,10 +24,29 @@ func respondJSON[T any](w http.ResponseWriter, code int, v T) {
 	w.Write(data) // #nosec G104
 }
 
-func respondError(w http.ResponseWriter, code int, value any) {
+// respondFile sends data as a file to download.
+func respondFile(w http.ResponseWriter, contentType, filename string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data) // #nosec G104
+}
+
+func respondError(w http.ResponseWriter, r *http.Request, code int, value any) {
 	if code > 499 {
-		log.Printf("Responding with 5xx error: %s\n", value)
+		slog.ErrorContext(r.Context(), "responding with 5xx error", "error", value)
 	}
+	writeError(w, code, value)
+}
+
+// respondInternalServerError logs err with the request ID and trace of r,
+// and keeps it from the client.
+func respondInternalServerError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.ErrorContext(r.Context(), "responding with 5xx error", "error", err)
+	writeError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+}
+
+func writeError(w http.ResponseWriter, code int, value any) {
 	type response struct {
 		Error any `json:"error"`
 	}
@@ -35,18 +55,14 @@ func respondError(w http.ResponseWriter, code int, value any) {
 	})
 }
 
-func respondInternalServerError(w http.ResponseWriter) {
-	respondError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
-}
-
-func respondDBConstraintsError(w http.ResponseWriter, err error, msg string) {
+func respondDBConstraintsError(w http.ResponseWriter, r *http.Request, err error, msg string) {
 	if errors.Is(err, services.ErrDBConstraint) {
-		respondError(w, http.StatusForbidden, fmt.Sprintf("invalid operation, check: %s", msg))
+		respondError(w, r, http.StatusForbidden, fmt.Sprintf("invalid operation, check: %s", msg))
 		return
 	}
-	respondInternalServerError(w)
+	respondInternalServerError(w, r, err)
 }
 
-func respondMalformedRequestError(w http.ResponseWriter) {
-	respondError(w, http.StatusBadRequest, "malformed request body")
+func respondMalformedRequestError(w http.ResponseWriter, r *http.Request) {
+	respondError(w, r, http.StatusBadRequest, "malformed request body")
 }
@@ -21,36 +21,37 @@ type AuthService interface {
 	RevokeRefreshToken(ctx context.Context, refreshToken string) error
 	Login(ctx context.Context, lr models.LoginRequest) (models.User, error)
 	AuthVerifier() func(http.Handler) http.Handler
+	AdminVerifier() func(http.Handler) http.Handler
 }
 
 func loginAPIHandler(as AuthService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		lr, err := decodeJSONValidate[models.LoginRequest](r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+			respondError(w, r, http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
 			return
 		}
 
 		user, err := as.Login(r.Context(), lr)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-				respondError(w, http.StatusUnauthorized, ErrAuthenticationFailed.Error())
+				respondError(w, r, http.StatusUnauthorized, ErrAuthenticationFailed.Error())
 				return
 			}
 
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
 		jwt, err := as.GenerateAccessToken(r.Context(), user.ID)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
 		refreshToken, err := as.GenerateAndSaveRefreshToken(r.Context(), user.ID)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
@@ -62,19 +63,19 @@ func refreshAccessHandler(as AuthService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		refreshToken, err := auth.GetHeaderToken(r)
 		if err != nil {
-			respondError(w, http.StatusUnauthorized, auth.ErrTokenNotFound.Error())
+			respondError(w, r, http.StatusUnauthorized, auth.ErrTokenNotFound.Error())
 			return
 		}
 
 		userID, err := as.ValidateRefreshToken(r.Context(), refreshToken)
 		if err != nil {
-			respondError(w, http.StatusUnauthorized, err.Error())
+			respondError(w, r, http.StatusUnauthorized, err.Error())
 			return
 		}
 
 		jwt, err := as.GenerateAccessToken(r.Context(), userID)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
@@ -98,7 +99,7 @@ func revokeRefreshTokenHandler(as AuthService) http.HandlerFunc {
 
 		err = as.RevokeRefreshToken(r.Context(), refreshToken)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/calendar"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+// getCalendarFeedHandler sends the URL of the calendar feed of the user,
+// 404 until one is made.
+func getCalendarFeedHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		feed, err := ms.GetCalendarFeed(r.Context(), userID, baseURL(r))
+		if err != nil {
+			respondMealPlanError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, feed)
+	}
+}
+
+// resetCalendarFeedHandler makes the calendar feed of the user, or gives
+// it a new URL when the old one leaked.
+func resetCalendarFeedHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		feed, err := ms.ResetCalendarFeed(r.Context(), userID, baseURL(r))
+		if err != nil {
+			respondMealPlanError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusCreated, feed)
+	}
+}
+
+func deleteCalendarFeedHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		err = ms.DeleteCalendarFeed(r.Context(), userID)
+		if err != nil {
+			respondMealPlanError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+// mealPlanCalendarHandler serves the calendar feed to calendar apps, which
+// can't log in: the secret token in the URL stands for the user.
+func mealPlanCalendarHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := ms.MealPlanCalendar(r.Context(), chi.URLParam(r, "token"), baseURL(r))
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", calendar.ContentType)
+		w.Header().Set("Cache-Control", "private, max-age=900")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data) // #nosec G104
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/cookbook"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+type CollectionService interface {
+	CreateCollection(ctx context.Context, userID uuid.UUID, arg models.CollectionRequest) (models.Collection, error)
+	ListCollections(ctx context.Context, userID uuid.UUID) ([]models.Collection, error)
+	GetCollection(ctx context.Context, userID, collectionID uuid.UUID) (models.Collection, error)
+	UpdateCollection(ctx context.Context, userID, collectionID uuid.UUID, arg models.CollectionRequest) (models.Collection, error)
+	DeleteCollection(ctx context.Context, userID, collectionID uuid.UUID) error
+	SetCollectionRecipes(ctx context.Context, userID, collectionID uuid.UUID, recipeIDs []uuid.UUID) (models.Collection, error)
+	AddRecipeToCollection(ctx context.Context, userID, collectionID, recipeID uuid.UUID) error
+	RemoveRecipeFromCollection(ctx context.Context, userID, collectionID, recipeID uuid.UUID) error
+	CollectionCookbook(ctx context.Context, userID, collectionID uuid.UUID, format string) (string, []byte, error)
+}
+
+func respondCollectionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+	case errors.Is(err, services.ErrSmartCollection):
+		respondError(w, r, http.StatusBadRequest, services.ErrSmartCollection.Error())
+	default:
+		respondDBConstraintsError(w, r, err, "collection")
+	}
+}
+
+func createCollectionHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.CollectionRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		collection, err := cs.CreateCollection(r.Context(), userID, arg)
+		if err != nil {
+			respondCollectionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusCreated, collection)
+	}
+}
+
+func listCollectionsHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		collections, err := cs.ListCollections(r.Context(), userID)
+		if err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, collections)
+	}
+}
+
+// getCollectionHandler sends a collection with its recipes, those of a
+// smart collection found anew.
+func getCollectionHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		collectionID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		collection, err := cs.GetCollection(r.Context(), userID, collectionID)
+		if err != nil {
+			respondCollectionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, collection)
+	}
+}
+
+func updateCollectionHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		collectionID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.CollectionRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		collection, err := cs.UpdateCollection(r.Context(), userID, collectionID, arg)
+		if err != nil {
+			respondCollectionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, collection)
+	}
+}
+
+func deleteCollectionHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		collectionID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = cs.DeleteCollection(r.Context(), userID, collectionID)
+		if err != nil {
+			respondCollectionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+// setCollectionRecipesHandler replaces the recipes of a manual collection,
+// which is how they are reordered.
+func setCollectionRecipesHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		collectionID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.CollectionRecipesRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		collection, err := cs.SetCollectionRecipes(r.Context(), userID, collectionID, arg.RecipeIDs)
+		if err != nil {
+			respondCollectionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, collection)
+	}
+}
+
+func addCollectionRecipeHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		collectionID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.CollectionRecipeRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = cs.AddRecipeToCollection(r.Context(), userID, collectionID, arg.RecipeID)
+		if err != nil {
+			respondCollectionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+func removeCollectionRecipeHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		collectionID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		recipeID, err := getIDParam(r, "recipeID")
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = cs.RemoveRecipeFromCollection(r.Context(), userID, collectionID, recipeID)
+		if err != nil {
+			respondCollectionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+// collectionCookbookHandler sends the recipes of a collection as a
+// cookbook, a PDF unless ?format=epub.
+func collectionCookbookHandler(cs CollectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		collectionID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if !cookbook.IsFormat(format) {
+			respondError(w, r, http.StatusBadRequest, cookbook.ErrUnknownFormat.Error())
+			return
+		}
+
+		title, data, err := cs.CollectionCookbook(r.Context(), userID, collectionID, format)
+		if err != nil {
+			respondCollectionError(w, r, err)
+			return
+		}
+
+		respondFile(w, cookbook.ContentType(format), cookbookFilename(title, format), data)
+	}
+}
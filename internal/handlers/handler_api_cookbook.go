@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/cookbook"
+	"github.com/quangd42/meal-org/internal/export"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+type CookbookService interface {
+	RecipeCookbook(ctx context.Context, userID uuid.UUID, format string, arg models.CookbookRequest) ([]byte, error)
+}
+
+// createCookbookHandler sends the recipes selected in the request as a
+// cookbook, a PDF unless ?format=epub.
+func createCookbookHandler(rs CookbookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if !cookbook.IsFormat(format) {
+			respondError(w, r, http.StatusBadRequest, cookbook.ErrUnknownFormat.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.CookbookRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		data, err := rs.RecipeCookbook(r.Context(), userID, format, arg)
+		if err != nil {
+			respondCookbookError(w, r, err)
+			return
+		}
+
+		respondFile(w, cookbook.ContentType(format), cookbookFilename(arg.Title, format), data)
+	}
+}
+
+func respondCookbookError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrResourceNotFound) {
+		respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+		return
+	}
+	respondInternalServerError(w, r, err)
+}
+
+func cookbookFilename(title, format string) string {
+	if title == "" {
+		title = services.DefaultCookbookTitle
+	}
+	if format == "" {
+		format = cookbook.FormatPDF
+	}
+	return export.Slug(title) + "." + format
+}
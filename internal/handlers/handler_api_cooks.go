@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+type CookService interface {
+	ListCookEvents(ctx context.Context, userID, recipeID uuid.UUID) ([]models.CookEvent, error)
+	CreateCookEvent(ctx context.Context, userID, recipeID uuid.UUID, arg models.CookEventRequest) (models.CookEvent, error)
+	UpdateCookEvent(ctx context.Context, userID, recipeID, eventID uuid.UUID, arg models.CookEventRequest) (models.CookEvent, error)
+	DeleteCookEvent(ctx context.Context, userID, recipeID, eventID uuid.UUID) error
+	SetRecipeFavorite(ctx context.Context, userID, recipeID uuid.UUID, favorite bool) error
+}
+
+func respondCookError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrResourceNotFound) {
+		respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+		return
+	}
+	respondDBConstraintsError(w, r, err, "rating")
+}
+
+// listCookEventsHandler sends the cook log of a recipe, most recent first.
+func listCookEventsHandler(cs CookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		events, err := cs.ListCookEvents(r.Context(), userID, recipeID)
+		if err != nil {
+			respondCookError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, events)
+	}
+}
+
+func createCookEventHandler(cs CookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.CookEventRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		event, err := cs.CreateCookEvent(r.Context(), userID, recipeID, arg)
+		if err != nil {
+			respondCookError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusCreated, event)
+	}
+}
+
+func updateCookEventHandler(cs CookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		eventID, err := getIDParam(r, "cookID")
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.CookEventRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		event, err := cs.UpdateCookEvent(r.Context(), userID, recipeID, eventID, arg)
+		if err != nil {
+			respondCookError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, event)
+	}
+}
+
+func deleteCookEventHandler(cs CookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		eventID, err := getIDParam(r, "cookID")
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = cs.DeleteCookEvent(r.Context(), userID, recipeID, eventID)
+		if err != nil {
+			respondCookError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+// setFavoriteHandler marks a recipe as a favorite, or not, as the request
+// says.
+func setFavoriteHandler(cs CookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.FavoriteRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = cs.SetRecipeFavorite(r.Context(), userID, recipeID, arg.Favorite)
+		if err != nil {
+			respondCookError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
@@ -21,17 +21,17 @@ func createCuisineHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		cr, err := decodeJSONValidate[models.CuisineRequest](r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err)
+			respondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
 		cuisine, err := rs.CreateCuisine(r.Context(), cr)
 		if err != nil {
 			if errors.Is(err, services.ErrResourceNotFound) {
-				respondError(w, http.StatusBadRequest, map[string]string{"parent_id": "parent does not exist"})
+				respondError(w, r, http.StatusBadRequest, map[string]string{"parent_id": "parent does not exist"})
 				return
 			}
-			respondDBConstraintsError(w, err, "cuisine name")
+			respondDBConstraintsError(w, r, err, "cuisine name")
 			return
 		}
 
@@ -43,23 +43,23 @@ func updateCuisineHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		cuisineID, err := getResourceIDFromURL(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		cr, err := decodeJSONValidate[models.CuisineRequest](r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err)
+			respondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
 		cuisine, err := rs.UpdateCuisineByID(r.Context(), cuisineID, cr)
 		if err != nil {
 			if errors.Is(err, services.ErrResourceNotFound) {
-				respondError(w, http.StatusBadRequest, map[string]string{"parent_id": "parent does not exist"})
+				respondError(w, r, http.StatusBadRequest, map[string]string{"parent_id": "parent does not exist"})
 				return
 			}
-			respondDBConstraintsError(w, err, "cuisine name")
+			respondDBConstraintsError(w, r, err, "cuisine name")
 			return
 		}
 
@@ -71,7 +71,7 @@ func listCuisinesHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		cuisines, err := rs.ListCuisines(r.Context())
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 		respondJSON(w, http.StatusOK, cuisines)
@@ -82,13 +82,13 @@ func deleteCuisineHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		cuisineID, err := getResourceIDFromURL(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		err = rs.DeleteCuisine(r.Context(), cuisineID)
 		if err != nil {
-			respondDBConstraintsError(w, err, "cuisine children")
+			respondDBConstraintsError(w, r, err, "cuisine children")
 			return
 		}
 
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/export"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+// exportRecipeHandler sends one recipe as a file in the format asked for
+// with ?format=, JSON by default.
+func exportRecipeHandler(rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		format, err := export.Lookup(r.URL.Query().Get("format"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		recipe, err := rs.GetRecipeByID(r.Context(), recipeID)
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+				return
+			}
+			respondInternalServerError(w, r, err)
+			return
+		}
+		if recipe.UserID != userID {
+			respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := format.Encode(&buf, recipe); err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondFile(w, format.ContentType, format.Filename(recipe), buf.Bytes())
+	}
+}
+
+// exportRecipesHandler sends every recipe of the user as a zip archive, one
+// file per recipe in the format asked for with ?format=.
+func exportRecipesHandler(rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		format, err := export.Lookup(r.URL.Query().Get("format"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		recipes, err := rs.ExportRecipesByUserID(r.Context(), userID)
+		if err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := export.WriteZip(&buf, format, recipes); err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondFile(w, "application/zip", fmt.Sprintf("recipes-%s.zip", format.Name), buf.Bytes())
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+const maxImportBytes = 32 << 20
+
+type ImportService interface {
+	ImportRecipes(ctx context.Context, userID uuid.UUID, filename, format string, data []byte) (models.ImportReport, error)
+}
+
+// importRecipesHandler creates recipes from an export of another recipe
+// manager. The archive is sent as the "file" field of a multipart form or as
+// the request body, its format is detected unless given with ?format=.
+func importRecipesHandler(rs ImportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		filename, data, err := readUpload(w, r)
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				respondError(w, r, http.StatusRequestEntityTooLarge, "upload is larger than 32MB")
+				return
+			}
+			respondError(w, r, http.StatusBadRequest, "missing or unreadable upload")
+			return
+		}
+
+		report, err := rs.ImportRecipes(r.Context(), userID, filename, r.URL.Query().Get("format"), data)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidImport) {
+				respondError(w, r, http.StatusBadRequest, err.Error())
+				return
+			}
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, report)
+	}
+}
+
+// readUpload returns the uploaded file and its name, which helps telling its
+// format.
+func readUpload(w http.ResponseWriter, r *http.Request) (string, []byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return "", nil, err
+		}
+		filename := r.URL.Query().Get("filename")
+		if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+			filename = params["filename"]
+		}
+		return filename, data, nil
+	}
+
+	if err := r.ParseMultipartForm(maxImportBytes); err != nil {
+		return "", nil, err
+	}
+	f, header, err := r.FormFile("file")
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	return header.Filename, data, err
+}
@@ -22,13 +22,13 @@ func createIngredientHandler(is IngredientService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		arg, err := decodeJSONValidate[models.IngredientRequest](r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err)
+			respondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
 		ingredient, err := is.CreateIngredient(r.Context(), arg)
 		if err != nil {
-			respondDBConstraintsError(w, err, "ingredient name")
+			respondDBConstraintsError(w, r, err, "ingredient name")
 			return
 		}
 
@@ -40,23 +40,23 @@ func updateIngredientHandler(is IngredientService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ingredientID, err := getResourceIDFromURL(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		arg, err := decodeJSONValidate[models.IngredientRequest](r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err)
+			respondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
 		ingredient, err := is.UpdateIngredientByID(r.Context(), ingredientID, arg)
 		if err != nil {
 			if errors.Is(err, services.ErrResourceNotFound) {
-				respondError(w, http.StatusBadRequest, map[string]string{"id": err.Error()})
+				respondError(w, r, http.StatusBadRequest, map[string]string{"id": err.Error()})
 				return
 			}
-			respondDBConstraintsError(w, err, "ingredient name")
+			respondDBConstraintsError(w, r, err, "ingredient name")
 			return
 		}
 
@@ -68,7 +68,7 @@ func listIngredientsHandler(is IngredientService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ingredients, err := is.ListIngredients(r.Context())
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
@@ -80,13 +80,13 @@ func deleteIngredientHandler(is IngredientService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ingredientID, err := getResourceIDFromURL(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		err = is.DeleteIngredient(r.Context(), ingredientID)
 		if err != nil {
-			respondDBConstraintsError(w, err, "ingredient children")
+			respondDBConstraintsError(w, r, err, "ingredient children")
 			return
 		}
 
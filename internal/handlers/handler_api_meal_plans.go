@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/planner"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+type MealPlanService interface {
+	SuggestMealPlan(ctx context.Context, userID uuid.UUID, arg models.MealPlanSuggestRequest) (models.MealPlanDraft, error)
+	CreateMealPlan(ctx context.Context, userID uuid.UUID, arg models.MealPlanRequest) (models.MealPlan, error)
+	ListMealPlans(ctx context.Context, userID uuid.UUID) ([]models.MealPlan, error)
+	GetMealPlan(ctx context.Context, userID, planID uuid.UUID) (models.MealPlan, error)
+	UpdateMealPlan(ctx context.Context, userID, planID uuid.UUID, arg models.MealPlanRequest) (models.MealPlan, error)
+	DeleteMealPlan(ctx context.Context, userID, planID uuid.UUID) error
+	GetCalendarFeed(ctx context.Context, userID uuid.UUID, baseURL string) (models.CalendarFeed, error)
+	ResetCalendarFeed(ctx context.Context, userID uuid.UUID, baseURL string) (models.CalendarFeed, error)
+	DeleteCalendarFeed(ctx context.Context, userID uuid.UUID) error
+	MealPlanCalendar(ctx context.Context, token, baseURL string) ([]byte, error)
+}
+
+func respondMealPlanError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+	case errors.Is(err, planner.ErrNoRecipes):
+		respondError(w, r, http.StatusBadRequest, planner.ErrNoRecipes.Error())
+	default:
+		respondDBConstraintsError(w, r, err, "meal plan")
+	}
+}
+
+// suggestMealPlanHandler sends a draft plan within the constraints of the
+// request. Nothing is saved: the draft, edited or not, is posted back to
+// create the plan.
+func suggestMealPlanHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.MealPlanSuggestRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		draft, err := ms.SuggestMealPlan(r.Context(), userID, arg)
+		if err != nil {
+			respondMealPlanError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, draft)
+	}
+}
+
+func createMealPlanHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.MealPlanRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		plan, err := ms.CreateMealPlan(r.Context(), userID, arg)
+		if err != nil {
+			respondMealPlanError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusCreated, plan)
+	}
+}
+
+func listMealPlansHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		plans, err := ms.ListMealPlans(r.Context(), userID)
+		if err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, plans)
+	}
+}
+
+func getMealPlanHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		planID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		plan, err := ms.GetMealPlan(r.Context(), userID, planID)
+		if err != nil {
+			respondMealPlanError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, plan)
+	}
+}
+
+// updateMealPlanHandler replaces a plan and its meals with the request.
+func updateMealPlanHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		planID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.MealPlanRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		plan, err := ms.UpdateMealPlan(r.Context(), userID, planID, arg)
+		if err != nil {
+			respondMealPlanError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, plan)
+	}
+}
+
+func deleteMealPlanHandler(ms MealPlanService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		planID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = ms.DeleteMealPlan(r.Context(), userID, planID)
+		if err != nil {
+			respondMealPlanError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
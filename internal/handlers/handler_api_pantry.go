@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+type PantryService interface {
+	ListPantryItems(ctx context.Context, userID uuid.UUID) ([]models.PantryItem, error)
+	CreatePantryItem(ctx context.Context, userID uuid.UUID, arg models.PantryItemRequest) (models.PantryItem, error)
+	UpdatePantryItem(ctx context.Context, userID, itemID uuid.UUID, arg models.PantryItemRequest) (models.PantryItem, error)
+	DeletePantryItem(ctx context.Context, userID, itemID uuid.UUID) error
+	ListCookableRecipes(ctx context.Context, userID uuid.UUID, expiringWithin int, limit int32) ([]models.CookableRecipe, error)
+}
+
+func respondPantryError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrResourceNotFound) {
+		respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+		return
+	}
+	respondDBConstraintsError(w, r, err, "ingredient_id")
+}
+
+func listPantryItemsHandler(ps PantryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		items, err := ps.ListPantryItems(r.Context(), userID)
+		if err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, items)
+	}
+}
+
+func createPantryItemHandler(ps PantryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.PantryItemRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		item, err := ps.CreatePantryItem(r.Context(), userID, arg)
+		if err != nil {
+			respondPantryError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusCreated, item)
+	}
+}
+
+func updatePantryItemHandler(ps PantryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		itemID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.PantryItemRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		item, err := ps.UpdatePantryItem(r.Context(), userID, itemID, arg)
+		if err != nil {
+			respondPantryError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, item)
+	}
+}
+
+func deletePantryItemHandler(ps PantryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		itemID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = ps.DeletePantryItem(r.Context(), userID, itemID)
+		if err != nil {
+			respondPantryError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+// listCookableRecipesHandler ranks the recipes by what of them the pantry
+// has, those using items expiring within ?expiring_within days first.
+func listCookableRecipesHandler(ps PantryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		limit := getPaginationParamValue(r, "limit", 20)
+		expiringWithin := getPaginationParamValue(r, "expiring_within", models.DefaultExpiringWithinDays)
+
+		recipes, err := ps.ListCookableRecipes(r.Context(), userID, int(expiringWithin), limit)
+		if err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, recipes)
+	}
+}
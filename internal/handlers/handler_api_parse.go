@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const maxRecipeTextBytes = 1 << 20
+
+// parseRecipeHandler reads a recipe pasted as text into a draft, which is
+// not saved. The text is sent as JSON {"text": ...} or as a text/plain body.
+func parseRecipeHandler(rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRecipeTextBytes)
+
+		var req models.RecipeTextRequest
+		var err error
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType == "text/plain" {
+			var data []byte
+			data, err = io.ReadAll(r.Body)
+			req.Text = string(data)
+		} else {
+			req, err = decodeJSONValidate[models.RecipeTextRequest](r)
+		}
+		if err != nil || strings.TrimSpace(req.Text) == "" {
+			respondError(w, r, http.StatusBadRequest, "missing recipe text")
+			return
+		}
+
+		draft, err := rs.ParseRecipeText(r.Context(), req.Text)
+		if err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, draft)
+	}
+}
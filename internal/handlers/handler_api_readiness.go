@@ -1,18 +1,39 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+
+	"github.com/quangd42/meal-org/internal/models"
 )
 
-func readinessHandler(w http.ResponseWriter, r *http.Request) {
+type HealthService interface {
+	Ready(ctx context.Context) models.Readiness
+}
+
+// livenessHandler only tells that the process is able to serve requests.
+// Dependencies are checked by readinessHandler, so that an unavailable
+// database doesn't get the server restarted.
+func livenessHandler(w http.ResponseWriter, r *http.Request) {
 	type response struct {
 		Status string `json:"status"`
 	}
 	respondJSON(w, http.StatusOK, response{
-		Status: http.StatusText(http.StatusOK),
+		Status: models.HealthStatusOK,
 	})
 }
 
+func readinessHandler(hs HealthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := hs.Ready(r.Context())
+		if !res.IsReady() {
+			respondJSON(w, http.StatusServiceUnavailable, res)
+			return
+		}
+		respondJSON(w, http.StatusOK, res)
+	}
+}
+
 func errorHandler(w http.ResponseWriter, r *http.Request) {
-	respondError(w, http.StatusInternalServerError, "Internal Server Error")
+	respondError(w, r, http.StatusInternalServerError, "Internal Server Error")
 }
@@ -3,24 +3,44 @@ package handlers
 import (
 	"context"
 	"errors"
+	"io"
+	"mime"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/quangd42/meal-org/internal/auth"
 	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/models/validator"
 	"github.com/quangd42/meal-org/internal/services"
 )
 
+const maxPatchBytes = 1 << 20
+
 type RecipeService interface {
 	IngredientService
 	CuisineService
+	ImageService
+	RevisionService
+	TrashService
+	ImportService
+	CookbookService
+	TagService
+	CollectionService
+	CookService
+	MealPlanService
+	PantryService
 
 	CreateRecipe(ctx context.Context, userID uuid.UUID, rr models.RecipeRequest) (models.Recipe, error)
-	UpdateRecipeByID(ctx context.Context, userID, recipeID uuid.UUID, rr models.RecipeRequest) (models.Recipe, error)
+	UpdateRecipeByID(ctx context.Context, userID, recipeID uuid.UUID, rr models.RecipeRequest, version string) (models.Recipe, error)
+	PatchRecipeByID(ctx context.Context, userID, recipeID uuid.UUID, contentType string, patch []byte, version string) (models.Recipe, error)
 	GetRecipeByID(ctx context.Context, recipeID uuid.UUID) (models.Recipe, error)
 	ListRecipesByUserID(ctx context.Context, userID uuid.UUID, pgn models.RecipesPagination) ([]models.RecipeInList, error)
-	DeleteRecipeByID(ctx context.Context, recipeID uuid.UUID) error
+	DeleteRecipeByID(ctx context.Context, userID, recipeID uuid.UUID) error
 	ListRecipesWithCuisinesByUserID(ctx context.Context, userID uuid.UUID, pgn models.RecipesPagination) ([]models.RecipeInList, error)
+	ExportRecipesByUserID(ctx context.Context, userID uuid.UUID) ([]models.Recipe, error)
+	ListRecipeJobs(ctx context.Context, userID, recipeID uuid.UUID) ([]models.Job, error)
+	FetchLinkMetadata(ctx context.Context, pageURL string) (models.LinkMetadata, error)
+	ParseRecipeText(ctx context.Context, text string) (models.RecipeDraft, error)
 }
 
 // TODO: allow for uploading images
@@ -28,22 +48,23 @@ func createRecipeHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, err := services.UserIDFromContext(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
 			return
 		}
 
 		rr, err := decodeJSONValidate[models.RecipeRequest](r)
 		if err != nil {
-			respondMalformedRequestError(w)
+			respondMalformedRequestError(w, r)
 			return
 		}
 
 		recipe, err := rs.CreateRecipe(r.Context(), userID, rr)
 		if err != nil {
-			respondDBConstraintsError(w, err, "cuisine_id, ingredient_id, step_no")
+			respondDBConstraintsError(w, r, err, "cuisine_id, ingredient_id, step_no")
 			return
 		}
 
+		setRecipeETag(w, recipe)
 		respondJSON(w, http.StatusCreated, recipe)
 	}
 }
@@ -52,36 +73,107 @@ func updateRecipeHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, err := services.UserIDFromContext(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
 			return
 		}
 
 		recipeID, err := getResourceIDFromURL(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		version, ok := ifMatchVersion(r)
+		if !ok {
+			respondError(w, r, http.StatusPreconditionRequired, "If-Match header with the ETag of the recipe is required")
 			return
 		}
 
 		rr, err := decodeJSONValidate[models.RecipeRequest](r)
 		if err != nil {
-			respondMalformedRequestError(w)
+			respondMalformedRequestError(w, r)
 			return
 		}
 
-		recipe, err := rs.UpdateRecipeByID(r.Context(), userID, recipeID, rr)
+		recipe, err := rs.UpdateRecipeByID(r.Context(), userID, recipeID, rr, version)
 		if err != nil {
+			if errors.Is(err, services.ErrVersionConflict) {
+				respondVersionConflict(w, r, rs, recipeID)
+				return
+			}
 			if errors.Is(err, services.ErrResourceNotFound) {
-				respondError(w, http.StatusBadRequest, map[string]string{"id": err.Error()})
+				respondError(w, r, http.StatusBadRequest, map[string]string{"id": err.Error()})
 				return
 			}
 			if errors.Is(err, services.ErrUnauthorized) {
-				respondError(w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+				respondError(w, r, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 				return
 			}
-			respondDBConstraintsError(w, err, "cuisine_id, ingredient_id, step_no")
+			respondDBConstraintsError(w, r, err, "cuisine_id, ingredient_id, step_no")
 			return
 		}
 
+		setRecipeETag(w, recipe)
+		respondJSON(w, http.StatusOK, recipe)
+	}
+}
+
+// patchRecipeHandler accepts a JSON Merge Patch or a JSON Patch of the
+// recipe, see models.RecipePatchDocument for the document it applies to.
+func patchRecipeHandler(rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if contentType != models.MergePatchContentType && contentType != models.JSONPatchContentType {
+			w.Header().Set("Accept-Patch", models.MergePatchContentType+", "+models.JSONPatchContentType)
+			respondError(w, r, http.StatusUnsupportedMediaType, services.ErrUnsupportedPatch.Error())
+			return
+		}
+
+		version, ok := ifMatchVersion(r)
+		if !ok {
+			respondError(w, r, http.StatusPreconditionRequired, "If-Match header with the ETag of the recipe is required")
+			return
+		}
+
+		patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchBytes))
+		if err != nil {
+			respondMalformedRequestError(w, r)
+			return
+		}
+
+		recipe, err := rs.PatchRecipeByID(r.Context(), userID, recipeID, contentType, patch, version)
+		if err != nil {
+			var valErrs validator.ValidationErrors
+			switch {
+			case errors.As(err, &valErrs):
+				respondError(w, r, http.StatusBadRequest, valErrs)
+			case errors.Is(err, services.ErrInvalidPatch):
+				respondError(w, r, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, services.ErrVersionConflict):
+				respondVersionConflict(w, r, rs, recipeID)
+			case errors.Is(err, services.ErrResourceNotFound):
+				respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+			case errors.Is(err, services.ErrUnauthorized):
+				respondError(w, r, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+			default:
+				respondDBConstraintsError(w, r, err, "cuisine_id, ingredient_id, step_no")
+			}
+			return
+		}
+
+		setRecipeETag(w, recipe)
 		respondJSON(w, http.StatusOK, recipe)
 	}
 }
@@ -90,13 +182,13 @@ func listRecipesHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, err := services.UserIDFromContext(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
 			return
 		}
 		pgn := getPaginationParams(r)
 		recipes, err := rs.ListRecipesByUserID(r.Context(), userID, pgn)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
@@ -108,52 +200,111 @@ func getRecipeHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		_, err := services.UserIDFromContext(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
 			return
 		}
 
 		recipeID, err := getResourceIDFromURL(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		recipe, err := rs.GetRecipeByID(r.Context(), recipeID)
 		if err != nil {
 			if errors.Is(err, services.ErrResourceNotFound) {
-				respondError(w, http.StatusNotFound, services.ErrResourceNotFound.Error())
+				respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
 				return
 			}
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
+		setRecipeETag(w, recipe)
+		if etagMatches(r.Header.Get("If-None-Match"), recipe.Version) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
 		respondJSON(w, http.StatusOK, recipe)
 	}
 }
 
+// respondVersionConflict responds with the current recipe, for the client to
+// merge its changes into.
+func respondVersionConflict(w http.ResponseWriter, r *http.Request, rs RecipeService, recipeID uuid.UUID) {
+	current, err := rs.GetRecipeByID(r.Context(), recipeID)
+	if err != nil {
+		respondInternalServerError(w, r, err)
+		return
+	}
+
+	type response struct {
+		Error   string        `json:"error"`
+		Current models.Recipe `json:"current"`
+	}
+	setRecipeETag(w, current)
+	respondJSON(w, http.StatusPreconditionFailed, response{
+		Error:   services.ErrVersionConflict.Error(),
+		Current: current,
+	})
+}
+
 // TODO: unit testing delete Recipe:
 // make sure that instructions and ingredient links are deleted
 func deleteRecipeHandler(rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		_, err := services.UserIDFromContext(r)
+		userID, err := services.UserIDFromContext(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
 			return
 		}
 
 		recipeID, err := getResourceIDFromURL(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err.Error())
+			respondError(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
 
-		err = rs.DeleteRecipeByID(r.Context(), recipeID)
+		err = rs.DeleteRecipeByID(r.Context(), userID, recipeID)
 		if err != nil {
-			respondInternalServerError(w)
+			respondTrashError(w, r, err)
 			return
 		}
 
 		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
 	}
 }
+
+// listRecipeJobsHandler reports the background work on a recipe, such as
+// fetching its preview image.
+func listRecipeJobsHandler(rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		jobs, err := rs.ListRecipeJobs(r.Context(), userID, recipeID)
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+				return
+			}
+			if errors.Is(err, services.ErrUnauthorized) {
+				respondError(w, r, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+				return
+			}
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, jobs)
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+type RevisionService interface {
+	ListRecipeRevisions(ctx context.Context, userID, recipeID uuid.UUID) ([]models.RecipeRevision, error)
+	GetRecipeRevision(ctx context.Context, userID, recipeID uuid.UUID, revision int) (models.RecipeRevision, error)
+	DiffRecipeRevisions(ctx context.Context, userID, recipeID uuid.UUID, from, to int) (models.RecipeDiff, error)
+	RestoreRecipeRevision(ctx context.Context, userID, recipeID uuid.UUID, revision int) (models.Recipe, error)
+}
+
+func respondRevisionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+	case errors.Is(err, services.ErrUnauthorized):
+		respondError(w, r, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+	default:
+		respondDBConstraintsError(w, r, err, "cuisines and ingredients of the revision")
+	}
+}
+
+func listRecipeRevisionsHandler(rs RevisionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		revisions, err := rs.ListRecipeRevisions(r.Context(), userID, recipeID)
+		if err != nil {
+			respondRevisionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, revisions)
+	}
+}
+
+func getRecipeRevisionHandler(rs RevisionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		rev, err := getRevisionParam(r, "rev")
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		revision, err := rs.GetRecipeRevision(r.Context(), userID, recipeID, rev)
+		if err != nil {
+			respondRevisionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, revision)
+	}
+}
+
+// diffRecipeRevisionsHandler compares the revisions in the from and to query
+// params.
+func diffRecipeRevisionsHandler(rs RevisionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		from, err := getRevisionParam(r, "from")
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		to, err := getRevisionParam(r, "to")
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		diff, err := rs.DiffRecipeRevisions(r.Context(), userID, recipeID, from, to)
+		if err != nil {
+			respondRevisionError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, diff)
+	}
+}
+
+func restoreRecipeRevisionHandler(rs RevisionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		rev, err := getRevisionParam(r, "rev")
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		recipe, err := rs.RestoreRecipeRevision(r.Context(), userID, recipeID, rev)
+		if err != nil {
+			respondRevisionError(w, r, err)
+			return
+		}
+
+		setRecipeETag(w, recipe)
+		respondJSON(w, http.StatusOK, recipe)
+	}
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+type TagService interface {
+	ListTags(ctx context.Context, userID uuid.UUID, prefix string) ([]models.Tag, error)
+	CreateTag(ctx context.Context, userID uuid.UUID, arg models.TagRequest) (models.Tag, error)
+	UpdateTagByID(ctx context.Context, userID, tagID uuid.UUID, arg models.TagRequest) (models.Tag, error)
+	DeleteTagByID(ctx context.Context, userID, tagID uuid.UUID) error
+	ListRecipeTags(ctx context.Context, userID, recipeID uuid.UUID) ([]models.Tag, error)
+	SetRecipeTags(ctx context.Context, userID, recipeID uuid.UUID, names []string) ([]models.Tag, error)
+}
+
+func respondTagError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrResourceNotFound) {
+		respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+		return
+	}
+	respondDBConstraintsError(w, r, err, "tag name")
+}
+
+// listTagsHandler lists the tags of the user, or with ?q= suggests those
+// starting with it.
+func listTagsHandler(ts TagService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		tags, err := ts.ListTags(r.Context(), userID, r.URL.Query().Get("q"))
+		if err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, tags)
+	}
+}
+
+func createTagHandler(ts TagService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.TagRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		tag, err := ts.CreateTag(r.Context(), userID, arg)
+		if err != nil {
+			respondTagError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusCreated, tag)
+	}
+}
+
+func updateTagHandler(ts TagService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		tagID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.TagRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		tag, err := ts.UpdateTagByID(r.Context(), userID, tagID, arg)
+		if err != nil {
+			respondTagError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, tag)
+	}
+}
+
+func deleteTagHandler(ts TagService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		tagID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = ts.DeleteTagByID(r.Context(), userID, tagID)
+		if err != nil {
+			respondTagError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+func listRecipeTagsHandler(ts TagService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		tags, err := ts.ListRecipeTags(r.Context(), userID, recipeID)
+		if err != nil {
+			respondTagError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, tags)
+	}
+}
+
+// setRecipeTagsHandler replaces the tags of a recipe with those in the
+// request, creating the new ones.
+func setRecipeTagsHandler(ts TagService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arg, err := decodeJSONValidate[models.RecipeTagsRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		tags, err := ts.SetRecipeTags(r.Context(), userID, recipeID, arg.Tags)
+		if err != nil {
+			respondTagError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, tags)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+type TrashService interface {
+	ListDeletedRecipes(ctx context.Context, userID uuid.UUID) ([]models.RecipeInList, error)
+	RestoreDeletedRecipe(ctx context.Context, userID, recipeID uuid.UUID) error
+	DeleteRecipeForever(ctx context.Context, userID, recipeID uuid.UUID) error
+}
+
+func respondTrashError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrResourceNotFound) {
+		respondError(w, r, http.StatusNotFound, services.ErrResourceNotFound.Error())
+		return
+	}
+	respondInternalServerError(w, r, err)
+}
+
+func listDeletedRecipesHandler(rs TrashService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipes, err := rs.ListDeletedRecipes(r.Context(), userID)
+		if err != nil {
+			respondInternalServerError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, recipes)
+	}
+}
+
+func restoreDeletedRecipeHandler(rs TrashService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = rs.RestoreDeletedRecipe(r.Context(), userID, recipeID)
+		if err != nil {
+			respondTrashError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+func deleteRecipeForeverHandler(rs TrashService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := services.UserIDFromContext(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			return
+		}
+
+		recipeID, err := getResourceIDFromURL(r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = rs.DeleteRecipeForever(r.Context(), userID, recipeID)
+		if err != nil {
+			respondTrashError(w, r, err)
+			return
+		}
+
+		respondJSON(w, http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
@@ -22,33 +22,33 @@ func createUserHandler(us UserService, as AuthService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ur, err := decodeJSONValidate[models.CreateUserRequest](r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err)
+			respondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
 		user, err := us.CreateUser(r.Context(), ur)
 		if err != nil {
 			if errors.Is(err, services.ErrHashPassword) {
-				respondInternalServerError(w)
+				respondInternalServerError(w, r, err)
 				return
 			}
 			if errors.Is(err, services.ErrDBConstraint) {
-				respondError(w, http.StatusForbidden, "email already taken")
+				respondError(w, r, http.StatusForbidden, "email already taken")
 				return
 			}
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
 		jwt, err := as.GenerateAccessToken(r.Context(), user.ID)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
 		refreshToken, err := as.GenerateAndSaveRefreshToken(r.Context(), user.ID)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
@@ -60,19 +60,19 @@ func updateUserHandler(us UserService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, err := services.UserIDFromContext(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
 			return
 		}
 
 		ur, err := decodeJSONValidate[models.UpdateUserRequest](r)
 		if err != nil {
-			respondMalformedRequestError(w)
+			respondMalformedRequestError(w, r)
 			return
 		}
 
 		user, err := us.UpdateUserByID(r.Context(), userID, ur)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
@@ -84,13 +84,13 @@ func forgetMeHandler(us UserService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, err := services.UserIDFromContext(r)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
+			respondError(w, r, http.StatusBadRequest, auth.ErrTokenNotFound.Error())
 			return
 		}
 
 		err = us.DeleteUserByID(r.Context(), userID)
 		if err != nil {
-			respondInternalServerError(w)
+			respondInternalServerError(w, r, err)
 			return
 		}
 
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/cookbook"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/models/validator"
+	"github.com/quangd42/meal-org/internal/services"
+	views "github.com/quangd42/meal-org/internal/views/recipes"
+)
+
+// collectionsPageHandler lists the collections of the user and creates new
+// ones from the form below them.
+func collectionsPageHandler(sm *scs.SessionManager, rds RendererService, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		var errs map[string][]string
+		if r.Method == http.MethodPost {
+			arg, err := createCollectionRequest(r)
+			if err == nil {
+				err = arg.Validate(r.Context())
+			}
+			if err == nil {
+				var collection models.Collection
+				collection, err = rs.CreateCollection(r.Context(), userID, arg)
+				if err == nil {
+					http.Redirect(w, r, fmt.Sprintf("/collections/%s", collection.ID), http.StatusSeeOther)
+					return
+				}
+			}
+			var verrs validator.ValidationErrors
+			if !errors.As(err, &verrs) {
+				http.Error(w, "failed to create collection", http.StatusInternalServerError)
+				return
+			}
+			errs = verrs
+		}
+
+		collections, err := rs.ListCollections(r.Context(), userID)
+		if err != nil {
+			http.Error(w, "failed to list collections", http.StatusInternalServerError)
+			return
+		}
+		cuisines, err := rs.ListCuisines(r.Context())
+		if err != nil {
+			http.Error(w, "failed to list cuisines", http.StatusInternalServerError)
+			return
+		}
+		ingredients, err := rs.ListIngredients(r.Context())
+		if err != nil {
+			http.Error(w, "failed to list ingredients", http.StatusInternalServerError)
+			return
+		}
+
+		vm := views.NewCollectionsVM(userID, rds.GetNavItems(true, r.URL.Path), collections, cuisines, ingredients, errs)
+		render(w, r, views.CollectionsPage(vm))
+	}
+}
+
+func collectionPageHandler(sm *scs.SessionManager, rds RendererService, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		collectionID, err := uuid.Parse(chi.URLParam(r, "collectionID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		collection, err := rs.GetCollection(r.Context(), userID, collectionID)
+		if err != nil {
+			respondCollectionPageError(w, err)
+			return
+		}
+		cuisines, err := rs.ListCuisines(r.Context())
+		if err != nil {
+			http.Error(w, "failed to list cuisines", http.StatusInternalServerError)
+			return
+		}
+		ingredients, err := rs.ListIngredients(r.Context())
+		if err != nil {
+			http.Error(w, "failed to list ingredients", http.StatusInternalServerError)
+			return
+		}
+
+		vm := views.NewCollectionVM(userID, rds.GetNavItems(true, r.URL.Path), collection, cuisines, ingredients)
+		render(w, r, views.CollectionPage(vm))
+	}
+}
+
+func deleteCollectionPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		collectionID, err := uuid.Parse(chi.URLParam(r, "collectionID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = rs.DeleteCollection(r.Context(), userID, collectionID)
+		if err != nil {
+			respondCollectionPageError(w, err)
+			return
+		}
+
+		w.Header().Set("HX-Redirect", "/collections")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// addRecipeToCollectionPageHandler puts a recipe in the collection chosen
+// on its page.
+func addRecipeToCollectionPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		collectionID, err := uuid.Parse(r.FormValue("collection_id"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		recipeID, err := uuid.Parse(r.FormValue("recipe_id"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = rs.AddRecipeToCollection(r.Context(), userID, collectionID, recipeID)
+		if err != nil {
+			respondCollectionPageError(w, err)
+			return
+		}
+
+		w.Write([]byte("Added to the collection.")) // #nosec G104
+	}
+}
+
+// moveCollectionRecipePageHandler moves a recipe of a manual collection one
+// place up or down, as ?to= says.
+func moveCollectionRecipePageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, collectionID, recipeID, ok := collectionRecipeParams(w, r, sm)
+		if !ok {
+			return
+		}
+
+		collection, err := rs.GetCollection(r.Context(), userID, collectionID)
+		if err != nil {
+			respondCollectionPageError(w, err)
+			return
+		}
+
+		ids := make([]uuid.UUID, 0, len(collection.Recipes))
+		for _, recipe := range collection.Recipes {
+			ids = append(ids, recipe.ID)
+		}
+		for i, id := range ids {
+			if id != recipeID {
+				continue
+			}
+			j := i + 1
+			if r.URL.Query().Get("to") == "up" {
+				j = i - 1
+			}
+			if j >= 0 && j < len(ids) {
+				ids[i], ids[j] = ids[j], ids[i]
+			}
+			break
+		}
+
+		collection, err = rs.SetCollectionRecipes(r.Context(), userID, collectionID, ids)
+		if err != nil {
+			respondCollectionPageError(w, err)
+			return
+		}
+
+		render(w, r, views.CollectionRecipes(collection))
+	}
+}
+
+func removeCollectionRecipePageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, collectionID, recipeID, ok := collectionRecipeParams(w, r, sm)
+		if !ok {
+			return
+		}
+
+		err := rs.RemoveRecipeFromCollection(r.Context(), userID, collectionID, recipeID)
+		if err != nil {
+			respondCollectionPageError(w, err)
+			return
+		}
+
+		collection, err := rs.GetCollection(r.Context(), userID, collectionID)
+		if err != nil {
+			respondCollectionPageError(w, err)
+			return
+		}
+
+		render(w, r, views.CollectionRecipes(collection))
+	}
+}
+
+func collectionCookbookPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		collectionID, err := uuid.Parse(chi.URLParam(r, "collectionID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if !cookbook.IsFormat(format) {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		title, data, err := rs.CollectionCookbook(r.Context(), userID, collectionID, format)
+		if err != nil {
+			respondCollectionPageError(w, err)
+			return
+		}
+
+		respondFile(w, cookbook.ContentType(format), cookbookFilename(title, format), data)
+	}
+}
+
+func collectionRecipeParams(w http.ResponseWriter, r *http.Request, sm *scs.SessionManager) (userID, collectionID, recipeID uuid.UUID, ok bool) {
+	userID, err := getUserIDFromCtx(r.Context(), sm)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	collectionID, err = uuid.Parse(chi.URLParam(r, "collectionID"))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	recipeID, err = uuid.Parse(chi.URLParam(r, "recipeID"))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	return userID, collectionID, recipeID, true
+}
+
+func respondCollectionPageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		http.Error(w, "collection or recipe not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrSmartCollection):
+		http.Error(w, services.ErrSmartCollection.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// createCollectionRequest reads the collection form. The filter is only
+// kept when the collection is smart.
+func createCollectionRequest(r *http.Request) (models.CollectionRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return models.CollectionRequest{}, err
+	}
+
+	arg := models.CollectionRequest{Name: strings.TrimSpace(r.PostForm.Get("name"))}
+	if desc := strings.TrimSpace(r.PostForm.Get("description")); desc != "" {
+		arg.Description = &desc
+	}
+	if r.PostForm.Get("smart") != "true" {
+		return arg, nil
+	}
+
+	filter := &models.CollectionFilter{}
+	for _, t := range strings.Split(r.PostForm.Get("tags"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			filter.Tags = append(filter.Tags, t)
+		}
+	}
+	for _, s := range r.PostForm["cuisine_id"] {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return arg, validator.ValidationErrors{"cuisine_id": {"invalid"}}
+		}
+		filter.CuisineIDs = append(filter.CuisineIDs, id)
+	}
+	for _, s := range r.PostForm["ingredient_id"] {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return arg, validator.ValidationErrors{"ingredient_id": {"invalid"}}
+		}
+		filter.IngredientIDs = append(filter.IngredientIDs, id)
+	}
+	if s := r.PostForm.Get("max_cook_time"); s != "" {
+		minutes, err := strconv.Atoi(s)
+		if err != nil {
+			return arg, validator.ValidationErrors{"max_cook_time": {"invalid"}}
+		}
+		filter.MaxCookTime = minutes
+	}
+	arg.Filter = filter
+	return arg, nil
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/images"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+)
+
+const placeholderImagePath = "/assets/img/mise-en-plase.jpg"
+
+type ImageService interface {
+	RecipeImage(ctx context.Context, userID, recipeID uuid.UUID, variant string) (models.Image, error)
+}
+
+// recipeImageProxyHandler serves the cached preview image of a recipe, so
+// that browsers never hotlink third party sites. Until the image is cached,
+// it redirects to a placeholder. The route is public, only the signed in
+// owner of the recipe gets a missing image fetched.
+func recipeImageProxyHandler(sm *scs.SessionManager, is ImageService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		variant := r.URL.Query().Get("size")
+		if variant == "" {
+			variant = images.VariantCard
+		}
+
+		// Anonymous requests have no user ID, and only get what is cached
+		userID, _ := getUserIDFromCtx(r.Context(), sm)
+
+		img, err := is.RecipeImage(r.Context(), userID, recipeID, variant)
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				http.NotFound(w, r)
+				return
+			}
+			if !errors.Is(err, services.ErrImageUnavailable) {
+				slog.ErrorContext(r.Context(), "failed to serve recipe image", "recipe_id", recipeID, "error", err)
+			}
+			w.Header().Set("Cache-Control", "no-store")
+			http.Redirect(w, r, placeholderImagePath, http.StatusFound)
+			return
+		}
+
+		// Pages link to the image with a version that changes with it
+		if r.URL.Query().Has("v") {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			w.Header().Set("Cache-Control", "public, max-age=86400")
+		}
+		w.Header().Set("Content-Type", img.ContentType)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("ETag", img.ETag)
+		http.ServeContent(w, r, "", img.ModTime, bytes.NewReader(img.Data))
+	}
+}
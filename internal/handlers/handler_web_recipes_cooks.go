@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+	views "github.com/quangd42/meal-org/internal/views/recipes"
+)
+
+func favoriteRecipePageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		favorite := r.FormValue("favorite") == "true"
+		err = rs.SetRecipeFavorite(r.Context(), userID, recipeID, favorite)
+		if err != nil {
+			respondCookPageError(w, err)
+			return
+		}
+
+		render(w, r, views.FavoriteButton(recipeID.String(), favorite))
+	}
+}
+
+// quickCookPageHandler logs that the recipe was cooked now, from the "I
+// made this" button of its card.
+func quickCookPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		_, err = rs.CreateCookEvent(r.Context(), userID, recipeID, models.CookEventRequest{})
+		if err != nil {
+			respondCookPageError(w, err)
+			return
+		}
+		events, err := rs.ListCookEvents(r.Context(), userID, recipeID)
+		if err != nil {
+			respondCookPageError(w, err)
+			return
+		}
+
+		var lastCooked *time.Time
+		if len(events) > 0 {
+			lastCooked = &events[0].CookedAt
+		}
+		render(w, r, views.CookedButton(recipeID.String(), len(events), lastCooked))
+	}
+}
+
+func createCookEventPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		arg, err := createCookEventRequest(r)
+		if err == nil {
+			err = arg.Validate(r.Context())
+		}
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		_, err = rs.CreateCookEvent(r.Context(), userID, recipeID, arg)
+		if err != nil {
+			respondCookPageError(w, err)
+			return
+		}
+
+		renderCookLog(w, r, rs, userID, recipeID)
+	}
+}
+
+func deleteCookEventPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		eventID, err := uuid.Parse(chi.URLParam(r, "cookID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = rs.DeleteCookEvent(r.Context(), userID, recipeID, eventID)
+		if err != nil {
+			respondCookPageError(w, err)
+			return
+		}
+
+		renderCookLog(w, r, rs, userID, recipeID)
+	}
+}
+
+func renderCookLog(w http.ResponseWriter, r *http.Request, rs RecipeService, userID, recipeID uuid.UUID) {
+	events, err := rs.ListCookEvents(r.Context(), userID, recipeID)
+	if err != nil {
+		respondCookPageError(w, err)
+		return
+	}
+	render(w, r, views.CookLog(recipeID.String(), events))
+}
+
+func respondCookPageError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrResourceNotFound) {
+		http.Error(w, "recipe not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
+
+// createCookEventRequest reads the cook log form, whose date is a day
+// without time.
+func createCookEventRequest(r *http.Request) (models.CookEventRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return models.CookEventRequest{}, err
+	}
+
+	var arg models.CookEventRequest
+	if s := r.PostForm.Get("cooked_on"); s != "" {
+		day, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return arg, err
+		}
+		arg.CookedAt = &day
+	}
+	if s := r.PostForm.Get("rating"); s != "" {
+		rating, err := strconv.Atoi(s)
+		if err != nil {
+			return arg, err
+		}
+		arg.Rating = &rating
+	}
+	arg.CookedBy = optionalFormValue(r, "cooked_by")
+	arg.Notes = optionalFormValue(r, "notes")
+	arg.PhotoURL = optionalFormValue(r, "photo_url")
+	return arg, nil
+}
+
+func optionalFormValue(r *http.Request, name string) *string {
+	v := strings.TrimSpace(r.PostForm.Get(name))
+	if v == "" {
+		return nil
+	}
+	return &v
+}
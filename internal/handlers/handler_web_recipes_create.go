@@ -21,7 +21,7 @@ func addRecipePageHandler(sm *scs.SessionManager, rds RendererService, rs Recipe
 		}
 
 		if r.Method == http.MethodPost {
-			rr, err := createMockRecipeRequest(r)
+			rr, _, err := createMockRecipeRequest(r)
 			if err != nil {
 				http.Error(w, fmt.Sprintf("failed to mock recipe: %s", err.Error()), http.StatusInternalServerError)
 				return
@@ -42,20 +42,23 @@ func addRecipePageHandler(sm *scs.SessionManager, rds RendererService, rs Recipe
 	}
 }
 
-func createMockRecipeRequest(r *http.Request) (models.RecipeRequest, error) {
+// createMockRecipeRequest also returns the version of the recipe the form was
+// filled from, if any.
+func createMockRecipeRequest(r *http.Request) (models.RecipeRequest, string, error) {
 	var rr models.RecipeRequest
 	type request struct {
 		Name        string `form:"name"`
 		Description string `form:"description"`
 		ExternalURL string `form:"external_url"`
+		Version     string `form:"version"`
 	}
 	arg := &request{}
 	err := form.NewDecoder(r.Body).Decode(arg)
 	if err != nil {
-		return rr, err
+		return rr, "", err
 	}
 	if arg.Name == "" {
-		return rr, errors.New("name is required")
+		return rr, "", errors.New("name is required")
 	}
 	rr = models.RecipeRequest{
 		Name:              arg.Name,
@@ -66,5 +69,5 @@ func createMockRecipeRequest(r *http.Request) (models.RecipeRequest, error) {
 		Ingredients:       []models.IngredientInRecipe{},
 		Instructions:      []models.InstructionInRecipe{},
 	}
-	return rr, err
+	return rr, arg.Version, err
 }
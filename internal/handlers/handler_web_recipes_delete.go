@@ -1,16 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/services"
 )
 
 func deleteRecipePageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		_, err := getUserIDFromCtx(r.Context(), sm)
+		userID, err := getUserIDFromCtx(r.Context(), sm)
 		if err != nil {
 			http.Redirect(w, r, "/login", http.StatusSeeOther)
 			return
@@ -23,8 +25,12 @@ func deleteRecipePageHandler(sm *scs.SessionManager, rs RecipeService) http.Hand
 			return
 		}
 
-		err = rs.DeleteRecipeByID(r.Context(), recipeID)
+		err = rs.DeleteRecipeByID(r.Context(), userID, recipeID)
 		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				http.Error(w, "recipe not found", http.StatusNotFound)
+				return
+			}
 			http.Error(w, "internal server error", http.StatusInternalServerError)
 			return
 		}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/quangd42/meal-org/internal/models"
+	views "github.com/quangd42/meal-org/internal/views/recipes"
+)
+
+// fetchRecipeDetailsHandler re-renders the basic info of the recipe form,
+// filling the fields left empty with the details of the external URL.
+func fetchRecipeDetailsHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		q := r.URL.Query()
+		name, description, externalURL := q.Get("name"), q.Get("description"), strings.TrimSpace(q.Get("external_url"))
+		recipe := &models.Recipe{
+			Name:        name,
+			Description: &description,
+			ExternalURL: &externalURL,
+		}
+
+		if externalURL == "" {
+			render(w, r, views.LinkDetailsResponse(recipe, nil, "Enter the external URL first"))
+			return
+		}
+
+		link, err := rs.FetchLinkMetadata(r.Context(), externalURL)
+		if err != nil {
+			slog.InfoContext(r.Context(), "failed to fetch link details", "url", externalURL, "error", err)
+			render(w, r, views.LinkDetailsResponse(recipe, nil, "Could not read the details of this link"))
+			return
+		}
+
+		// Never overwrite what the user already typed
+		if strings.TrimSpace(recipe.Name) == "" {
+			recipe.Name = link.Name
+		}
+		if strings.TrimSpace(description) == "" {
+			recipe.Description = &link.Description
+		}
+		recipe.ExternalURL = &link.URL
+
+		render(w, r, views.LinkDetailsResponse(recipe, &link, ""))
+	}
+}
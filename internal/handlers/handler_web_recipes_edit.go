@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
 	views "github.com/quangd42/meal-org/internal/views/recipes"
 )
 
@@ -26,13 +29,29 @@ func editRecipePageHandler(sm *scs.SessionManager, rds RendererService, rs Recip
 		}
 
 		if r.Method == http.MethodPost {
-			rr, err := createMockRecipeRequest(r)
+			rr, version, err := createMockRecipeRequest(r)
 			if err != nil {
 				http.Error(w, fmt.Sprintf("failed to mock recipe: %s", err.Error()), http.StatusInternalServerError)
 				return
 			}
 
-			recipe, err := rs.UpdateRecipeByID(r.Context(), userID, recipeID, rr)
+			recipe, err := rs.UpdateRecipeByID(r.Context(), userID, recipeID, rr, version)
+			if errors.Is(err, services.ErrVersionConflict) {
+				current, err := rs.GetRecipeByID(r.Context(), recipeID)
+				if err != nil {
+					http.Error(w, "failed to update recipe", http.StatusInternalServerError)
+					return
+				}
+				mine := &models.Recipe{
+					ID:          recipeID,
+					Name:        rr.Name,
+					Description: rr.Description,
+					ExternalURL: rr.ExternalURL,
+					Version:     current.Version,
+				}
+				render(w, r, views.RecipeConflictForm(mine, current))
+				return
+			}
 			if err != nil {
 				http.Error(w, "failed to update recipe", http.StatusInternalServerError)
 				return
@@ -48,7 +67,30 @@ func editRecipePageHandler(sm *scs.SessionManager, rds RendererService, rs Recip
 			return
 		}
 
-		vm := views.NewEditRecipeVM(userID, rds.GetNavItems(userID != uuid.Nil, r.URL.Path), recipe, nil)
+		tags, err := rs.ListRecipeTags(r.Context(), userID, recipeID)
+		if err != nil && !errors.Is(err, services.ErrResourceNotFound) {
+			http.Error(w, "failed to list tags", http.StatusInternalServerError)
+			return
+		}
+		collections, err := rs.ListCollections(r.Context(), userID)
+		if err != nil {
+			http.Error(w, "failed to list collections", http.StatusInternalServerError)
+			return
+		}
+		manual := []models.Collection{}
+		for _, c := range collections {
+			if !c.Smart {
+				manual = append(manual, c)
+			}
+		}
+
+		events, err := rs.ListCookEvents(r.Context(), userID, recipeID)
+		if err != nil && !errors.Is(err, services.ErrResourceNotFound) {
+			http.Error(w, "failed to list cook log", http.StatusInternalServerError)
+			return
+		}
+
+		vm := views.NewEditRecipeVM(userID, rds.GetNavItems(userID != uuid.Nil, r.URL.Path), recipe, tags, manual, events, nil)
 		render(w, r, views.EditRecipePage(vm))
 	}
 }
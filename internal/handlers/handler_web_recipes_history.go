@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/services"
+	views "github.com/quangd42/meal-org/internal/views/recipes"
+)
+
+func recipeHistoryPageHandler(sm *scs.SessionManager, rds RendererService, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		revisions, err := rs.ListRecipeRevisions(r.Context(), userID, recipeID)
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) || errors.Is(err, services.ErrUnauthorized) {
+				http.Error(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to list revisions", http.StatusInternalServerError)
+			return
+		}
+
+		vm := views.NewRecipeHistoryVM(userID, rds.GetNavItems(userID != uuid.Nil, r.URL.Path), recipeID, revisions)
+		render(w, r, views.RecipeHistoryPage(vm))
+	}
+}
+
+func restoreRecipeRevisionPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		rev, err := getRevisionParam(r, "rev")
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		_, err = rs.RestoreRecipeRevision(r.Context(), userID, recipeID, rev)
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) || errors.Is(err, services.ErrUnauthorized) {
+				http.Error(w, "revision not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to restore revision", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("HX-Redirect", fmt.Sprintf("/recipes/%s/history", recipeID))
+		w.WriteHeader(http.StatusOK)
+	}
+}
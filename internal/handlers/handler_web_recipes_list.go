@@ -15,11 +15,12 @@ func listRecipesPageHandler(sm *scs.SessionManager, rds RendererService, rs Reci
 			return
 		}
 
-		recipes, err := rs.ListRecipesWithCuisinesByUserID(r.Context(), userID, getPaginationParams(r))
+		pgn := getPaginationParams(r)
+		recipes, err := rs.ListRecipesWithCuisinesByUserID(r.Context(), userID, pgn)
 		if err != nil {
 			http.Error(w, "internal error", 500)
 			return
 		}
-		render(w, r, views.ListRecipesPage(views.NewListRecipesVM(rds.GetNavItems(true, r.URL.Path), recipes, nil)))
+		render(w, r, views.ListRecipesPage(views.NewListRecipesVM(rds.GetNavItems(true, r.URL.Path), recipes, pgn.Sort, nil)))
 	}
 }
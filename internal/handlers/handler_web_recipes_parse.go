@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/quangd42/meal-org/internal/models"
+	views "github.com/quangd42/meal-org/internal/views/recipes"
+)
+
+// parseRecipePageHandler re-renders the basic info of the recipe form,
+// filling the fields left empty with the recipe read from the pasted text.
+func parseRecipePageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxRecipeTextBytes)
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		name, description, externalURL := r.PostForm.Get("name"), r.PostForm.Get("description"), r.PostForm.Get("external_url")
+		recipe := &models.Recipe{
+			Name:        name,
+			Description: &description,
+			ExternalURL: &externalURL,
+		}
+
+		text := r.PostForm.Get("recipe_text")
+		if strings.TrimSpace(text) == "" {
+			render(w, r, views.ParsedRecipeResponse(recipe, nil, "Paste a recipe first"))
+			return
+		}
+
+		draft, err := rs.ParseRecipeText(r.Context(), text)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to parse recipe text", "error", err)
+			render(w, r, views.ParsedRecipeResponse(recipe, nil, "Could not read this recipe"))
+			return
+		}
+
+		// Never overwrite what the user already typed
+		if strings.TrimSpace(recipe.Name) == "" {
+			recipe.Name = draft.Recipe.Name
+		}
+		if strings.TrimSpace(description) == "" && draft.Recipe.Description != nil {
+			recipe.Description = draft.Recipe.Description
+		}
+
+		render(w, r, views.ParsedRecipeResponse(recipe, &draft, ""))
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/cookbook"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/services"
+	views "github.com/quangd42/meal-org/internal/views/recipes"
+)
+
+func printRecipePageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		recipe, err := rs.GetRecipeByID(r.Context(), recipeID)
+		if err != nil || recipe.UserID != userID {
+			http.Error(w, "recipe not found", http.StatusNotFound)
+			return
+		}
+
+		render(w, r, views.PrintRecipePage(recipe))
+	}
+}
+
+// cookbookPageHandler sends the recipes ticked in the list, given as ?id=,
+// as a PDF or EPUB cookbook.
+func cookbookPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		q := r.URL.Query()
+		format := q.Get("format")
+		if !cookbook.IsFormat(format) {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		arg := models.CookbookRequest{Title: q.Get("title")}
+		for _, s := range q["id"] {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			arg.RecipeIDs = append(arg.RecipeIDs, id)
+		}
+		if err := arg.Validate(r.Context()); err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		data, err := rs.RecipeCookbook(r.Context(), userID, format, arg)
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				http.Error(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to print cookbook", http.StatusInternalServerError)
+			return
+		}
+
+		respondFile(w, cookbook.ContentType(format), cookbookFilename(arg.Title, format), data)
+	}
+}
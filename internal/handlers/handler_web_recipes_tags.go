@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/services"
+	views "github.com/quangd42/meal-org/internal/views/recipes"
+)
+
+// tagSuggestionsHandler suggests tags for the last one being typed in the
+// tags field, as options of its datalist.
+func tagSuggestionsHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		typed := r.URL.Query().Get("tags")
+		prefix := typed[strings.LastIndex(typed, ",")+1:]
+		if strings.TrimSpace(prefix) == "" {
+			return
+		}
+
+		tags, err := rs.ListTags(r.Context(), userID, prefix)
+		if err != nil {
+			http.Error(w, "failed to list tags", http.StatusInternalServerError)
+			return
+		}
+
+		render(w, r, views.TagSuggestions(typed, tags))
+	}
+}
+
+func setRecipeTagsPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		tags, err := rs.SetRecipeTags(r.Context(), userID, recipeID, strings.Split(r.FormValue("tags"), ","))
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				http.Error(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to save tags", http.StatusInternalServerError)
+			return
+		}
+
+		render(w, r, views.RecipeTagsForm(recipeID.String(), tags, true))
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/services"
+	views "github.com/quangd42/meal-org/internal/views/recipes"
+)
+
+func trashPageHandler(sm *scs.SessionManager, rds RendererService, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipes, err := rs.ListDeletedRecipes(r.Context(), userID)
+		if err != nil {
+			http.Error(w, "failed to list deleted recipes", http.StatusInternalServerError)
+			return
+		}
+
+		vm := views.NewTrashVM(userID, rds.GetNavItems(true, r.URL.Path), recipes)
+		render(w, r, views.TrashPage(vm))
+	}
+}
+
+func restoreDeletedRecipePageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = rs.RestoreDeletedRecipe(r.Context(), userID, recipeID)
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				http.Error(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to restore recipe", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func deleteRecipeForeverPageHandler(sm *scs.SessionManager, rs RecipeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromCtx(r.Context(), sm)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = rs.DeleteRecipeForever(r.Context(), userID, recipeID)
+		if err != nil {
+			if errors.Is(err, services.ErrResourceNotFound) {
+				http.Error(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to delete recipe", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"strconv"
@@ -35,16 +35,22 @@ func getPaginationParams(r *http.Request) models.RecipesPagination {
 	var limit, offset int32
 	limit = getPaginationParamValue(r, "limit", 20)
 	offset = getPaginationParamValue(r, "offset", 0)
+	sort := r.URL.Query().Get("sort")
+	if !models.IsRecipeSort(sort) {
+		sort = models.RecipeSortName
+	}
 	return models.RecipesPagination{
 		Limit:  limit,
 		Offset: offset,
+		Sort:   sort,
 	}
 }
 
 func disableCacheInDevMode(next http.Handler) http.Handler {
 	dev := false
 	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
-		log.Fatal("error loading env")
+		slog.Error("error loading env", "error", err)
+		os.Exit(1)
 	}
 
 	devStr := os.Getenv("DEV_MODE")
@@ -76,12 +82,72 @@ func getUserIDFromCtx(ctx context.Context, sm *scs.SessionManager) (uuid.UUID, e
 }
 
 func getResourceIDFromURL(r *http.Request) (uuid.UUID, error) {
-	resourceIDString := chi.URLParam(r, "id")
-	resourceID, err := uuid.Parse(resourceIDString)
+	return getIDParam(r, "id")
+}
+
+// getIDParam reads the ID in the URL parameter name, for routes with more
+// than one resource.
+func getIDParam(r *http.Request, name string) (uuid.UUID, error) {
+	resourceID, err := uuid.Parse(chi.URLParam(r, name))
 	if err != nil {
 		err := validator.NewValidationErrors()
-		err["id"] = []string{"invalid"}
+		err[name] = []string{"invalid"}
 		return uuid.UUID{}, err
 	}
 	return resourceID, nil
 }
+
+// getRevisionParam reads a revision number, from the URL or the query.
+func getRevisionParam(r *http.Request, name string) (int, error) {
+	s := chi.URLParam(r, name)
+	if s == "" {
+		s = r.URL.Query().Get(name)
+	}
+	rev, err := strconv.Atoi(s)
+	if err != nil || rev < 1 {
+		err := validator.NewValidationErrors()
+		err[name] = []string{"invalid"}
+		return 0, err
+	}
+	return rev, nil
+}
+
+func setRecipeETag(w http.ResponseWriter, recipe models.Recipe) {
+	w.Header().Set("ETag", `"`+recipe.Version+`"`)
+}
+
+// ifMatchVersion returns the recipe version in the If-Match header. It is
+// empty for "*", which matches any version.
+func ifMatchVersion(r *http.Request) (string, bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" {
+		return "", false
+	}
+	if v == "*" {
+		return "", true
+	}
+	// A weak tag keeps its W/ prefix, so that it never matches, as If-Match
+	// requires
+	return strings.Trim(v, `"`), true
+}
+
+// etagMatches reports whether the If-None-Match header lists version.
+func etagMatches(header, version string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || strings.Trim(tag, `"`) == version {
+			return true
+		}
+	}
+	return false
+}
+
+// baseURL is the scheme and host the request was sent to, through a proxy
+// that says so or not.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
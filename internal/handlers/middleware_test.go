@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quangd42/meal-org/internal/logging"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCalendarTokenRedacted(t *testing.T) {
+	const token = "s3cr3t-calendar-token"
+
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(logging.New(&logs))
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	r := chi.NewRouter()
+	r.Use(requestTracer)
+	r.Use(requestLogger)
+	r.Get("/calendar/{token}.ics", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/"+token+".ics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200", w.Code)
+	}
+
+	if logs.Len() == 0 {
+		t.Fatal("nothing was logged")
+	}
+	if strings.Contains(logs.String(), token) {
+		t.Errorf("the token is in the logs: %s", logs.String())
+	}
+	spans := exporter.GetSpans()
+	if len(spans) == 0 {
+		t.Fatal("no span was recorded")
+	}
+	for _, s := range spans {
+		if strings.Contains(s.Name, token) {
+			t.Errorf("the token is in the name of span %q", s.Name)
+		}
+		for _, a := range s.Attributes {
+			if strings.Contains(a.Value.Emit(), token) {
+				t.Errorf("the token is in the span attribute %s=%s", a.Key, a.Value.Emit())
+			}
+		}
+	}
+}
@@ -16,24 +16,38 @@ func AddRoutes(
 	us UserService,
 	as AuthService,
 	rs RecipeService,
+	hs HealthService,
 ) {
 	// Top level middlewares
+	r.Use(requestTracer)
+	r.Use(requestLogger)
+	r.Use(requestMetrics)
 	r.Use(middleware.StripSlashes)
 	r.Use(sm.LoadAndSave)
 	r.Use(cors.Handler(cors.Options{
 		// AllowedOrigins:   []string{"https://foo.com"}, // Use this to allow specific origin hosts
 		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Request-ID", "traceparent", "tracestate", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"ETag", "Link", "X-Request-ID"},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 
+	// Probes
+	r.Get("/livez", livenessHandler)
+	r.Get("/readyz", readinessHandler(hs))
+
 	// Static assets
 	fs := disableCacheInDevMode(http.FileServer(http.Dir("assets")))
 	r.Handle("/assets/*", http.StripPrefix("/assets", fs))
 
+	// Cached images of external sites
+	r.Get("/images/proxy/{recipeID}", recipeImageProxyHandler(sm, rs))
+
+	// Calendar feed of the meal plans, for calendar apps
+	r.Get("/calendar/{token}.ics", mealPlanCalendarHandler(rs))
+
 	// Public pages
 	r.Get("/login", loginPageHandler(sm, rds, as))
 	r.Post("/login", loginPageHandler(sm, rds, as))
@@ -46,6 +60,8 @@ func AddRoutes(
 	// Add
 	r.Get("/recipes/add", addRecipePageHandler(sm, rds, rs))
 	r.Post("/recipes", addRecipePageHandler(sm, rds, rs))
+	r.Get("/recipes/fetch-details", fetchRecipeDetailsHandler(sm, rs))
+	r.Post("/recipes/parse", parseRecipePageHandler(sm, rs))
 	// List
 	r.Get("/recipes", listRecipesPageHandler(sm, rds, rs))
 	// Edit
@@ -53,10 +69,37 @@ func AddRoutes(
 	r.Get("/recipes/{recipeID}", editRecipePageHandler(sm, rds, rs))
 	// Delete
 	r.Delete("/recipes/{recipeID}", deleteRecipePageHandler(sm, rs))
+	// Trash
+	r.Get("/recipes/trash", trashPageHandler(sm, rds, rs))
+	r.Post("/recipes/trash/{recipeID}/restore", restoreDeletedRecipePageHandler(sm, rs))
+	r.Delete("/recipes/trash/{recipeID}", deleteRecipeForeverPageHandler(sm, rs))
+	// Print
+	r.Get("/recipes/{recipeID}/print", printRecipePageHandler(sm, rs))
+	r.Get("/recipes/cookbook", cookbookPageHandler(sm, rs))
+	// History
+	r.Get("/recipes/{recipeID}/history", recipeHistoryPageHandler(sm, rds, rs))
+	r.Post("/recipes/{recipeID}/revisions/{rev}/restore", restoreRecipeRevisionPageHandler(sm, rs))
+	// Tags
+	r.Get("/tags/suggest", tagSuggestionsHandler(sm, rs))
+	r.Put("/recipes/{recipeID}/tags", setRecipeTagsPageHandler(sm, rs))
+	// Favorites and cook log
+	r.Post("/recipes/{recipeID}/favorite", favoriteRecipePageHandler(sm, rs))
+	r.Post("/recipes/{recipeID}/cooks", createCookEventPageHandler(sm, rs))
+	r.Post("/recipes/{recipeID}/cooks/quick", quickCookPageHandler(sm, rs))
+	r.Delete("/recipes/{recipeID}/cooks/{cookID}", deleteCookEventPageHandler(sm, rs))
+	// Collections
+	r.Get("/collections", collectionsPageHandler(sm, rds, rs))
+	r.Post("/collections", collectionsPageHandler(sm, rds, rs))
+	r.Post("/collections/recipes", addRecipeToCollectionPageHandler(sm, rs))
+	r.Get("/collections/{collectionID}", collectionPageHandler(sm, rds, rs))
+	r.Delete("/collections/{collectionID}", deleteCollectionPageHandler(sm, rs))
+	r.Get("/collections/{collectionID}/cookbook", collectionCookbookPageHandler(sm, rs))
+	r.Post("/collections/{collectionID}/recipes/{recipeID}/move", moveCollectionRecipePageHandler(sm, rs))
+	r.Delete("/collections/{collectionID}/recipes/{recipeID}", removeCollectionRecipePageHandler(sm, rs))
 
 	// API router
 	r.Route("/v1", func(r chi.Router) {
-		r.Get("/healthz", readinessHandler)
+		r.Get("/healthz", readinessHandler(hs))
 		r.Get("/err", errorHandler)
 
 		r.Mount("/users", usersAPIRouter(us, as))
@@ -64,6 +107,10 @@ func AddRoutes(
 		r.Mount("/recipes", recipesAPIRouter(rs, as))
 		r.Mount("/ingredients", ingredientsAPIRouter(rs, as))
 		r.Mount("/cuisines", cuisinesAPIRouter(rs, as))
+		r.Mount("/tags", tagsAPIRouter(rs, as))
+		r.Mount("/collections", collectionsAPIRouter(rs, as))
+		r.Mount("/meal-plans", mealPlansAPIRouter(rs, as))
+		r.Mount("/pantry", pantryAPIRouter(rs, as))
 	})
 }
 
@@ -99,10 +146,35 @@ func recipesAPIRouter(rs RecipeService, as AuthService) http.Handler {
 	r.Use(as.AuthVerifier())
 	r.Post("/", createRecipeHandler(rs))
 	r.Get("/", listRecipesHandler(rs))
+	r.Get("/export", exportRecipesHandler(rs))
+	r.Post("/import/bulk", importRecipesHandler(rs))
+	r.Post("/parse", parseRecipeHandler(rs))
+	r.Post("/cookbook", createCookbookHandler(rs))
+	r.Get("/cookable", listCookableRecipesHandler(rs))
+
+	r.Get("/trash", listDeletedRecipesHandler(rs))
+	r.Post("/trash/{id}/restore", restoreDeletedRecipeHandler(rs))
+	r.Delete("/trash/{id}", deleteRecipeForeverHandler(rs))
 
 	r.Get("/{id}", getRecipeHandler(rs))
 	r.Put("/{id}", updateRecipeHandler(rs))
+	r.Patch("/{id}", patchRecipeHandler(rs))
 	r.Delete("/{id}", deleteRecipeHandler(rs))
+	r.Get("/{id}/jobs", listRecipeJobsHandler(rs))
+	r.Get("/{id}/export", exportRecipeHandler(rs))
+	r.Get("/{id}/tags", listRecipeTagsHandler(rs))
+	r.Put("/{id}/tags", setRecipeTagsHandler(rs))
+	r.Put("/{id}/favorite", setFavoriteHandler(rs))
+
+	r.Get("/{id}/cooks", listCookEventsHandler(rs))
+	r.Post("/{id}/cooks", createCookEventHandler(rs))
+	r.Put("/{id}/cooks/{cookID}", updateCookEventHandler(rs))
+	r.Delete("/{id}/cooks/{cookID}", deleteCookEventHandler(rs))
+
+	r.Get("/{id}/revisions", listRecipeRevisionsHandler(rs))
+	r.Get("/{id}/revisions/diff", diffRecipeRevisionsHandler(rs))
+	r.Get("/{id}/revisions/{rev}", getRecipeRevisionHandler(rs))
+	r.Post("/{id}/revisions/{rev}/restore", restoreRecipeRevisionHandler(rs))
 
 	// TODO: add search & filter
 
@@ -122,16 +194,87 @@ func ingredientsAPIRouter(rs RecipeService, as AuthService) http.Handler {
 	return r
 }
 
-// TODO: create, update and delete should be restricted to admin only
+// cuisinesAPIRouter lets every user list the cuisines, which are shared,
+// and only admins change them.
 func cuisinesAPIRouter(rs RecipeService, as AuthService) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(as.AuthVerifier())
-	r.Post("/", createCuisineHandler(rs))
 	r.Get("/", listCuisinesHandler(rs))
 
-	r.Put("/{id}", updateCuisineHandler(rs))
-	r.Delete("/{id}", deleteCuisineHandler(rs))
+	r.Group(func(r chi.Router) {
+		r.Use(as.AdminVerifier())
+		r.Post("/", createCuisineHandler(rs))
+		r.Put("/{id}", updateCuisineHandler(rs))
+		r.Delete("/{id}", deleteCuisineHandler(rs))
+	})
+
+	return r
+}
+
+func tagsAPIRouter(rs RecipeService, as AuthService) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(as.AuthVerifier())
+	r.Post("/", createTagHandler(rs))
+	r.Get("/", listTagsHandler(rs))
+
+	r.Put("/{id}", updateTagHandler(rs))
+	r.Delete("/{id}", deleteTagHandler(rs))
+
+	return r
+}
+
+func collectionsAPIRouter(rs RecipeService, as AuthService) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(as.AuthVerifier())
+	r.Post("/", createCollectionHandler(rs))
+	r.Get("/", listCollectionsHandler(rs))
+
+	r.Get("/{id}", getCollectionHandler(rs))
+	r.Put("/{id}", updateCollectionHandler(rs))
+	r.Delete("/{id}", deleteCollectionHandler(rs))
+	r.Get("/{id}/cookbook", collectionCookbookHandler(rs))
+
+	r.Put("/{id}/recipes", setCollectionRecipesHandler(rs))
+	r.Post("/{id}/recipes", addCollectionRecipeHandler(rs))
+	r.Delete("/{id}/recipes/{recipeID}", removeCollectionRecipeHandler(rs))
+
+	return r
+}
+
+func mealPlansAPIRouter(rs RecipeService, as AuthService) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(as.AuthVerifier())
+	r.Post("/suggest", suggestMealPlanHandler(rs))
+	r.Post("/", createMealPlanHandler(rs))
+	r.Get("/", listMealPlansHandler(rs))
+
+	r.Get("/calendar", getCalendarFeedHandler(rs))
+	r.Post("/calendar", resetCalendarFeedHandler(rs))
+	r.Delete("/calendar", deleteCalendarFeedHandler(rs))
+
+	r.Get("/{id}", getMealPlanHandler(rs))
+	r.Put("/{id}", updateMealPlanHandler(rs))
+	r.Delete("/{id}", deleteMealPlanHandler(rs))
+
+	return r
+}
+
+// pantryAPIRouter manages the pantry of the user. Adding what was bought
+// when a shopping list is done is left out until the app has shopping lists,
+// items are added one at a time.
+func pantryAPIRouter(rs RecipeService, as AuthService) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(as.AuthVerifier())
+	r.Get("/", listPantryItemsHandler(rs))
+	r.Post("/", createPantryItemHandler(rs))
+
+	r.Put("/{id}", updatePantryItemHandler(rs))
+	r.Delete("/{id}", deletePantryItemHandler(rs))
 
 	return r
 }
@@ -0,0 +1,112 @@
+// Package images turns downloaded pictures into the variants shown by the UI.
+package images
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	// Formats that recipe sites serve their preview images in
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	VariantCard   = "card"
+	VariantDetail = "detail"
+
+	ContentType = "image/jpeg"
+
+	// Larger images are refused before decoding, they can take a lot of
+	// memory for a small download.
+	maxPixels   = 40_000_000
+	jpegQuality = 82
+)
+
+var ErrUnsupported = errors.New("images: unsupported image")
+
+type variant struct {
+	width, height int
+	// crop fills the whole box, cutting the overflow, instead of fitting in
+	// it.
+	crop bool
+}
+
+var variants = map[string]variant{
+	VariantCard:   {width: 600, height: 400, crop: true},
+	VariantDetail: {width: 1200, height: 1200},
+}
+
+// IsVariant reports whether name is one of the variants.
+func IsVariant(name string) bool {
+	_, ok := variants[name]
+	return ok
+}
+
+// Variants decodes src and returns every variant, encoded as JPEG.
+func Variants(src []byte) (map[string][]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d is too large", ErrUnsupported, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, err)
+	}
+
+	out := map[string][]byte{}
+	for name, v := range variants {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(img, v), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		out[name] = buf.Bytes()
+	}
+	return out, nil
+}
+
+func resize(src image.Image, v variant) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	srcRect := b
+	var dw, dh int
+	if v.crop {
+		// Take the largest centered region with the variant's aspect ratio
+		if w*v.height > h*v.width {
+			cw := h * v.width / v.height
+			srcRect = image.Rect(b.Min.X+(w-cw)/2, b.Min.Y, b.Min.X+(w-cw)/2+cw, b.Max.Y)
+		} else {
+			ch := w * v.height / v.width
+			srcRect = image.Rect(b.Min.X, b.Min.Y+(h-ch)/2, b.Max.X, b.Min.Y+(h-ch)/2+ch)
+		}
+		dw, dh = v.width, v.height
+		if srcRect.Dx() < dw {
+			// Never upscale
+			dw, dh = srcRect.Dx(), srcRect.Dy()
+		}
+	} else {
+		dw, dh = w, h
+		if dw > v.width {
+			dw, dh = v.width, h*v.width/w
+		}
+		if dh > v.height {
+			dw, dh = dw*v.height/dh, v.height
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, max(dw, 1), max(dh, 1)))
+	// JPEG has no transparency, so it is flattened on white
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, srcRect, draw.Over, nil)
+	return dst
+}
@@ -0,0 +1,203 @@
+package importer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// readCooklang reads a recipe written in https://cooklang.org. Ingredients
+// are taken from where the steps mention them, cookware and timers are kept
+// as text.
+func readCooklang(name string, data []byte) ([]Entry, error) {
+	r := Recipe{SourceID: name}
+	meta := map[string]string{}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = stripBlockComments(text)
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		if front, body, ok := strings.Cut(rest, "\n---"); ok {
+			for _, line := range strings.Split(front, "\n") {
+				addMetadata(meta, line)
+			}
+			text = body
+		}
+	}
+
+	var step []string
+	var notes []string
+	flush := func() {
+		if len(step) > 0 {
+			r.Instructions = append(r.Instructions, parseCooklangStep(&r, strings.Join(step, " ")))
+			step = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.Index(line, "--"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, ">>"):
+			flush()
+			addMetadata(meta, strings.TrimPrefix(line, ">>"))
+		case strings.HasPrefix(line, ">"):
+			flush()
+			notes = append(notes, strings.TrimSpace(strings.TrimPrefix(line, ">")))
+		case strings.HasPrefix(line, "="):
+			// Section headings are left out, their steps are kept
+			flush()
+		default:
+			step = append(step, line)
+		}
+	}
+	flush()
+
+	r.Name = firstOf(meta, "title", "name")
+	if r.Name == "" {
+		r.Name = fileStem(name)
+	}
+	r.Description = firstOf(meta, "description", "introduction")
+	r.URL = firstOf(meta, "source", "source.url", "url")
+	r.Servings, r.Yield = parseServings(firstOf(meta, "servings", "serves"))
+	if y := meta["yield"]; y != "" {
+		r.Yield = y
+	}
+	r.CookTimeInMinutes = parseMinutes(firstOf(meta, "time", "total time", "time required", "duration", "cook time"))
+	if r.CookTimeInMinutes == 0 {
+		r.CookTimeInMinutes = parseMinutes(meta["prep time"]) + parseMinutes(meta["cook time"])
+	}
+	r.Cuisines = splitList(firstOf(meta, "cuisine", "cuisines", "category", "course"))
+	r.Notes = strings.Join(notes, "\n")
+	if id := meta["id"]; id != "" {
+		r.SourceID = id
+	}
+
+	return []Entry{{File: name, Recipe: r}}, nil
+}
+
+func addMetadata(meta map[string]string, line string) {
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	if key != "" && value != "" {
+		meta[key] = value
+	}
+}
+
+func firstOf(meta map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := meta[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func stripBlockComments(s string) string {
+	for {
+		start := strings.Index(s, "[-")
+		if start < 0 {
+			return s
+		}
+		end := strings.Index(s[start:], "-]")
+		if end < 0 {
+			return s[:start]
+		}
+		s = s[:start] + s[start+end+2:]
+	}
+}
+
+// parseCooklangStep turns the marks of a step into plain text, adding the
+// ingredients it mentions to r.
+func parseCooklangStep(r *Recipe, step string) string {
+	var b strings.Builder
+	for i := 0; i < len(step); i++ {
+		c := step[i]
+		switch {
+		case c == '\\' && i+1 < len(step):
+			i++
+			b.WriteByte(step[i])
+		case c == '@' || c == '#' || c == '~':
+			name, amount, note, n := readCooklangMark(step[i+1:])
+			if n == 0 {
+				b.WriteByte(c)
+				continue
+			}
+			i += n
+			switch c {
+			case '@':
+				addCooklangIngredient(r, name, amount, note)
+				b.WriteString(name)
+			case '#':
+				b.WriteString(name)
+			case '~':
+				b.WriteString(strings.TrimSpace(strings.Join(nonEmpty(amount, name), " ")))
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// readCooklangMark reads what follows @, # or ~: a name of several words
+// up to {quantity%unit}, or a single word without braces, then an optional
+// (note). n is how many bytes were read.
+func readCooklangMark(s string) (name, amount, note string, n int) {
+	if brace := strings.IndexByte(s, '{'); brace >= 0 && isMarkName(s[:brace]) {
+		end := strings.IndexByte(s[brace:], '}')
+		if end >= 0 {
+			name = strings.TrimSpace(s[:brace])
+			amount = strings.TrimSpace(strings.ReplaceAll(s[brace+1:brace+end], "%", " "))
+			amount = strings.Join(strings.Fields(amount), " ")
+			n = brace + end + 1
+		}
+	}
+	if n == 0 {
+		for n < len(s) {
+			r := rune(s[n])
+			if r >= 0x80 || unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' {
+				n++
+				continue
+			}
+			break
+		}
+		name = s[:n]
+	}
+	if n > 0 && strings.HasPrefix(s[n:], "(") {
+		if end := strings.IndexByte(s[n:], ')'); end >= 0 {
+			note = strings.TrimSpace(s[n+1 : n+end])
+			n += end + 1
+		}
+	}
+	return name, amount, note, n
+}
+
+func isMarkName(s string) bool {
+	if strings.TrimSpace(s) == "" && s != "" {
+		return false
+	}
+	for _, r := range s {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '-' || r == '\'' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func addCooklangIngredient(r *Recipe, name, amount, note string) {
+	if name == "" {
+		return
+	}
+	for _, i := range r.Ingredients {
+		if strings.EqualFold(i.Name, name) && (amount == "" || i.Amount == amount) {
+			return
+		}
+	}
+	r.Ingredients = append(r.Ingredients, Ingredient{Amount: amount, Name: name, PrepNote: note})
+}
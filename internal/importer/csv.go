@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// csvAliases are the headers that spreadsheets tend to use for each field
+// of a recipe.
+var csvAliases = map[string][]string{
+	"id":           {"id", "source_id", "uid"},
+	"name":         {"name", "title", "recipe"},
+	"url":          {"url", "source", "source_url", "link"},
+	"description":  {"description", "summary"},
+	"servings":     {"servings", "serves"},
+	"yield":        {"yield"},
+	"time":         {"cook_time", "cook time", "time", "total_time", "total time", "minutes"},
+	"cuisines":     {"cuisine", "cuisines", "category", "categories"},
+	"ingredients":  {"ingredients"},
+	"instructions": {"instructions", "directions", "steps", "method"},
+	"notes":        {"notes", "note"},
+}
+
+// csvColumns maps a header to its field.
+var csvColumns = map[string]string{}
+
+func init() {
+	for field, aliases := range csvAliases {
+		for _, a := range aliases {
+			csvColumns[a] = field
+		}
+	}
+}
+
+// readCSV reads one recipe per row. Ingredients and instructions are one per
+// line of their cell, or separated by semicolons when the cell has a single
+// line.
+func readCSV(name string, data []byte) ([]Entry, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("invalid CSV: no recipes found")
+	}
+	if len(rows)-1 > maxEntries {
+		return nil, fmt.Errorf("%w: more than %d recipes", ErrTooLarge, maxEntries)
+	}
+
+	columns := make([]string, len(rows[0]))
+	found := false
+	for i, h := range rows[0] {
+		columns[i] = csvColumns[strings.ToLower(strings.TrimSpace(h))]
+		found = found || columns[i] == "name"
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid CSV: missing a name or title column")
+	}
+
+	var entries []Entry
+	for n, row := range rows[1:] {
+		fields := map[string]string{}
+		for i, v := range row {
+			if i < len(columns) && columns[i] != "" {
+				fields[columns[i]] = strings.TrimSpace(v)
+			}
+		}
+
+		r := Recipe{
+			Name:        fields["name"],
+			URL:         fields["url"],
+			Description: fields["description"],
+			Notes:       fields["notes"],
+			Cuisines:    splitList(fields["cuisines"]),
+		}
+		r.Servings, r.Yield = parseServings(fields["servings"])
+		if y := fields["yield"]; y != "" {
+			r.Yield = y
+		}
+		r.CookTimeInMinutes = parseMinutes(fields["time"])
+		r.Ingredients = splitIngredients(&r, splitCell(fields["ingredients"]))
+		r.Instructions = splitSteps(splitCell(fields["instructions"]))
+
+		// Without an id, rows are told apart by what they point to
+		r.SourceID = fields["id"]
+		if r.SourceID == "" {
+			r.SourceID = strings.ToLower(r.Name) + "|" + r.URL
+		}
+
+		entries = append(entries, Entry{File: strings.TrimSpace(fmt.Sprintf("%s row %d", name, n+2)), Recipe: r})
+	}
+	return entries, nil
+}
+
+func splitCell(s string) string {
+	if !strings.Contains(s, "\n") {
+		return strings.ReplaceAll(s, ";", "\n")
+	}
+	return s
+}
@@ -0,0 +1,261 @@
+// Package importer reads the recipes exported by other recipe managers.
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	FormatPaprika  = "paprika"
+	FormatMealie   = "mealie"
+	FormatCooklang = "cooklang"
+	FormatCSV      = "csv"
+
+	// Entries of an archive are refused above this size once uncompressed,
+	// and archives above maxTotalBytes, a small upload can otherwise take a
+	// lot of memory.
+	maxEntryBytes = 16 << 20
+	maxTotalBytes = 128 << 20
+	maxEntries    = 5000
+)
+
+var (
+	ErrUnknownFormat = errors.New("importer: unknown format")
+	ErrTooLarge      = errors.New("importer: archive is too large")
+)
+
+// Recipe is a recipe as read from another app, before its ingredients and
+// cuisines are matched with ours.
+type Recipe struct {
+	// SourceID identifies the recipe in the app it comes from, so that
+	// importing it again can be detected.
+	SourceID          string
+	Name              string
+	URL               string
+	Description       string
+	Servings          int
+	Yield             string
+	CookTimeInMinutes int
+	Notes             string
+	Cuisines          []string
+	Ingredients       []Ingredient
+	Instructions      []string
+	// Warnings are about what could not be read or was guessed.
+	Warnings []string
+}
+
+func (r *Recipe) warn(format string, a ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, a...))
+}
+
+type Ingredient struct {
+	Amount   string
+	Name     string
+	PrepNote string
+}
+
+// Entry is one recipe of an upload. Err is set when the recipe could not be
+// read, File tells which one it was.
+type Entry struct {
+	File   string
+	Recipe Recipe
+	Err    error
+}
+
+// Formats lists the formats that can be read.
+func Formats() []string {
+	return []string{FormatCooklang, FormatCSV, FormatMealie, FormatPaprika}
+}
+
+// Detect tells the format of an upload from its file name and content.
+func Detect(filename string, data []byte) (string, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".paprikarecipes", ".paprikarecipe":
+		return FormatPaprika, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".cook":
+		return FormatCooklang, nil
+	case ".json":
+		return FormatMealie, nil
+	}
+
+	if isZip(data) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return "", fmt.Errorf("importer: cannot read archive: %w", err)
+		}
+		for _, f := range zr.File {
+			switch strings.ToLower(path.Ext(f.Name)) {
+			case ".paprikarecipe":
+				return FormatPaprika, nil
+			case ".cook":
+				return FormatCooklang, nil
+			case ".json":
+				return FormatMealie, nil
+			case ".csv":
+				return FormatCSV, nil
+			}
+		}
+		return "", fmt.Errorf("%w: no recipes found in the archive", ErrUnknownFormat)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return "", fmt.Errorf("%w: the upload is empty", ErrUnknownFormat)
+	case trimmed[0] == '{' || trimmed[0] == '[':
+		return FormatMealie, nil
+	case bytes.HasPrefix(trimmed, []byte(">>")) || bytes.HasPrefix(trimmed, []byte("---")):
+		return FormatCooklang, nil
+	case bytes.ContainsRune(firstLine(trimmed), ','):
+		return FormatCSV, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Read calls yield with each recipe of an upload in format. The entries of
+// an archive are read and handed over one at a time, the archive is never
+// held uncompressed. A recipe that cannot be read is yielded as an Entry with
+// Err set, so that it doesn't stop the others. An error is returned, before
+// any recipe is yielded, when the upload can't be read at all.
+func Read(format, filename string, data []byte, yield func(Entry)) error {
+	var read func(name string, data []byte) ([]Entry, error)
+	var ext string
+	switch format {
+	case FormatPaprika:
+		read, ext = readPaprikaRecipe, ".paprikarecipe"
+	case FormatMealie:
+		read, ext = readMealie, ".json"
+	case FormatCooklang:
+		read, ext = readCooklang, ".cook"
+	case FormatCSV:
+		read, ext = readCSV, ".csv"
+	default:
+		return fmt.Errorf("%w %q, use one of: %s", ErrUnknownFormat, format, strings.Join(Formats(), ", "))
+	}
+
+	if !isZip(data) {
+		entries, err := read(filename, data)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			yield(e)
+		}
+		return nil
+	}
+
+	files, err := zipEntries(data, ext)
+	if err != nil {
+		return err
+	}
+	left := int64(maxTotalBytes)
+	for _, f := range files {
+		data, err := readZipFile(f, &left)
+		if err != nil {
+			yield(Entry{File: f.Name, Err: err})
+			continue
+		}
+		entries, err := read(f.Name, data)
+		if err != nil {
+			yield(Entry{File: f.Name, Err: err})
+			continue
+		}
+		for _, e := range entries {
+			yield(e)
+		}
+	}
+	return nil
+}
+
+// zipEntries returns the files of the archive that end in ext. The archive
+// is refused when they are too many, or too large together by the sizes
+// the archive declares.
+func zipEntries(data []byte, ext string) ([]*zip.File, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("importer: cannot read archive: %w", err)
+	}
+
+	var files []*zip.File
+	var total uint64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ext) || isHidden(f.Name) {
+			continue
+		}
+		if len(files) == maxEntries {
+			return nil, fmt.Errorf("%w: more than %d recipes", ErrTooLarge, maxEntries)
+		}
+		total += f.UncompressedSize64
+		if total > maxTotalBytes {
+			return nil, fmt.Errorf("%w: more than %d MB uncompressed", ErrTooLarge, maxTotalBytes>>20)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// readZipFile reads an entry of an archive, taking what it reads from the
+// bytes left to the whole archive.
+func readZipFile(f *zip.File, left *int64) ([]byte, error) {
+	if f.UncompressedSize64 > maxEntryBytes || f.UncompressedSize64 > uint64(*left) {
+		return nil, ErrTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := readLimited(io.LimitReader(rc, *left+1))
+	*left -= int64(len(data))
+	if err != nil {
+		return nil, err
+	}
+	if *left < 0 {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxEntryBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxEntryBytes {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+func isZip(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+}
+
+// isHidden skips the metadata that archivers add, such as __MACOSX.
+func isHidden(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || strings.HasPrefix(part, "__") {
+			return true
+		}
+	}
+	return false
+}
+
+func firstLine(data []byte) []byte {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	return line
+}
+
+// fileStem is the name of a file without its directory and extension.
+func fileStem(name string) string {
+	base := path.Base(name)
+	return strings.TrimSuffix(base, path.Ext(base))
+}
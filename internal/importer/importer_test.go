@@ -0,0 +1,337 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// zipOf archives files, by name.
+func zipOf(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		filename string
+		data     []byte
+		want     string
+		wantErr  error
+	}{
+		{filename: "recipes.paprikarecipes", data: readTestdata(t, "recipes.paprikarecipes"), want: FormatPaprika},
+		{filename: "soup.paprikarecipe", data: readTestdata(t, "soup.paprikarecipe"), want: FormatPaprika},
+		{filename: "soup.json", data: readTestdata(t, "soup.json"), want: FormatMealie},
+		{filename: "soup.cook", data: readTestdata(t, "soup.cook"), want: FormatCooklang},
+		{filename: "recipes.csv", data: readTestdata(t, "recipes.csv"), want: FormatCSV},
+		// Without an extension the content tells.
+		{filename: "upload", data: readTestdata(t, "recipes.paprikarecipes"), want: FormatPaprika},
+		{filename: "upload", data: readTestdata(t, "soup.json"), want: FormatMealie},
+		{filename: "upload", data: readTestdata(t, "soup.cook"), want: FormatCooklang},
+		{filename: "upload", data: []byte(">> servings: 2\nBoil @water{1%l}."), want: FormatCooklang},
+		{filename: "upload", data: readTestdata(t, "recipes.csv"), want: FormatCSV},
+		{filename: "mealie.zip", data: zipOf(t, map[string][]byte{"recipes/soup.json": readTestdata(t, "soup.json")}), want: FormatMealie},
+		{filename: "cook.zip", data: zipOf(t, map[string][]byte{"soup.cook": readTestdata(t, "soup.cook")}), want: FormatCooklang},
+		{filename: "empty.zip", data: zipOf(t, map[string][]byte{"readme.txt": []byte("hi")}), wantErr: ErrUnknownFormat},
+		{filename: "upload", data: []byte("  \n"), wantErr: ErrUnknownFormat},
+		{filename: "upload", data: []byte("just some text"), wantErr: ErrUnknownFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename+" "+tt.want, func(t *testing.T) {
+			got, err := Detect(tt.filename, tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// tomatoSoup is the recipe of the testdata files, as each format can hold it.
+var tomatoSoup = Recipe{
+	SourceID:          "A1B2C3D4-SOUP",
+	Name:              "Tomato Soup",
+	URL:               "https://example.com/tomato-soup",
+	Description:       "A quick soup.",
+	Servings:          4,
+	CookTimeInMinutes: 35,
+	Notes:             "Freezes well.",
+	Cuisines:          []string{"Italian"},
+	Ingredients: []Ingredient{
+		{Amount: "2 tbsp", Name: "olive oil"},
+		{Amount: "1", Name: "onion", PrepNote: "chopped"},
+		{Amount: "2 cloves", Name: "garlic", PrepNote: "minced"},
+		{Amount: "1 (28 oz) can", Name: "whole tomatoes"},
+	},
+	Instructions: []string{
+		"Cook the onion in the oil until soft.",
+		"Add the garlic and tomatoes and simmer for 20 minutes.",
+	},
+}
+
+func soupWith(change func(r *Recipe)) Recipe {
+	r := tomatoSoup
+	r.Ingredients = append([]Ingredient(nil), tomatoSoup.Ingredients...)
+	r.Instructions = append([]string(nil), tomatoSoup.Instructions...)
+	change(&r)
+	return r
+}
+
+var beanStew = Recipe{
+	Name:              "Bean Stew",
+	Servings:          2,
+	CookTimeInMinutes: 65,
+	Ingredients: []Ingredient{
+		{Amount: "1 can", Name: "white beans"},
+		{Name: "salt"},
+	},
+	Instructions: []string{"Warm the beans.", "Season with salt."},
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		file     string
+		want     []Entry
+		wantErrs []string
+	}{
+		{
+			name:   "gzipped paprika recipe",
+			format: FormatPaprika,
+			file:   "soup.paprikarecipe",
+			want:   []Entry{{File: "soup.paprikarecipe", Recipe: tomatoSoup}},
+		},
+		{
+			name:   "paprika archive",
+			format: FormatPaprika,
+			file:   "recipes.paprikarecipes",
+			want: []Entry{
+				{File: "Tomato Soup.paprikarecipe", Recipe: tomatoSoup},
+				{File: "Bean Stew.paprikarecipe", Recipe: func() Recipe {
+					r := beanStew
+					r.SourceID = "E5F6-STEW"
+					return r
+				}()},
+			},
+			// The __MACOSX entry is skipped, the broken one doesn't stop the
+			// others.
+			wantErrs: []string{"Broken.paprikarecipe"},
+		},
+		{
+			name:   "mealie",
+			format: FormatMealie,
+			file:   "soup.json",
+			want: []Entry{{File: "soup.json", Recipe: soupWith(func(r *Recipe) {
+				r.SourceID = "6d1b2c9e-mealie-soup"
+				r.Notes = "Storage: Freezes well."
+				r.Ingredients = r.Ingredients[:3]
+				r.Instructions[1] = "Add the garlic and simmer for 20 minutes."
+				r.Warnings = []string{"tags were left out"}
+			})}},
+		},
+		{
+			name:   "cooklang",
+			format: FormatCooklang,
+			file:   "soup.cook",
+			want: []Entry{{File: "soup.cook", Recipe: soupWith(func(r *Recipe) {
+				r.SourceID = "soup.cook"
+				r.Description = ""
+				r.Ingredients = []Ingredient{
+					{Amount: "1", Name: "onion", PrepNote: "chopped"},
+					{Amount: "2 tbsp", Name: "olive oil"},
+					{Amount: "2 cloves", Name: "garlic", PrepNote: "minced"},
+					{Amount: "28 oz", Name: "whole tomatoes"},
+				}
+				r.Instructions = []string{
+					"Cook onion in olive oil in a pot until soft.",
+					"Add garlic and whole tomatoes, then simmer for 20 minutes.",
+				}
+			})}},
+		},
+		{
+			name:   "csv",
+			format: FormatCSV,
+			file:   "recipes.csv",
+			want: []Entry{
+				{File: "recipes.csv row 2", Recipe: soupWith(func(r *Recipe) {
+					r.SourceID = "tomato soup|https://example.com/tomato-soup"
+					r.Description = ""
+					r.Notes = ""
+					r.Ingredients = r.Ingredients[:3]
+					r.Instructions = []string{"Cook the onion in the oil.", "Add the garlic and simmer."}
+				})},
+				{File: "recipes.csv row 3", Recipe: func() Recipe {
+					r := beanStew
+					r.SourceID = "bean stew|"
+					r.CookTimeInMinutes = 60
+					return r
+				}()},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Entry
+			var gotErrs []string
+			err := Read(tt.format, tt.file, readTestdata(t, tt.file), func(e Entry) {
+				if e.Err != nil {
+					gotErrs = append(gotErrs, e.File)
+					return
+				}
+				got = append(got, e)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d recipes, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !reflect.DeepEqual(normalize(got[i]), normalize(tt.want[i])) {
+					t.Errorf("entry %d:\ngot  %+v\nwant %+v", i, got[i], tt.want[i])
+				}
+			}
+			if !reflect.DeepEqual(gotErrs, tt.wantErrs) {
+				t.Errorf("got errors for %q, want %q", gotErrs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+// normalize makes empty and nil slices equal.
+func normalize(e Entry) Entry {
+	if len(e.Recipe.Cuisines) == 0 {
+		e.Recipe.Cuisines = nil
+	}
+	if len(e.Recipe.Warnings) == 0 {
+		e.Recipe.Warnings = nil
+	}
+	return e
+}
+
+func TestReadUnknownFormat(t *testing.T) {
+	err := Read("docx", "soup.docx", []byte("soup"), func(Entry) {})
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("got %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestReadTooLarge(t *testing.T) {
+	// entry declares size in the archive without holding the data, the
+	// declared sizes are checked before anything is read.
+	entry := func(zw *zip.Writer, name string, size uint64) {
+		_, err := zw.CreateRaw(&zip.FileHeader{
+			Name:               name,
+			Method:             zip.Store,
+			UncompressedSize64: size,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("archive", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for i := 0; i*maxEntryBytes <= maxTotalBytes; i++ {
+			entry(zw, fmt.Sprintf("recipes/%d.cook", i), maxEntryBytes)
+		}
+		zw.Close()
+
+		yielded := false
+		err := Read(FormatCooklang, "recipes.zip", buf.Bytes(), func(Entry) { yielded = true })
+		if !errors.Is(err, ErrTooLarge) {
+			t.Errorf("got %v, want ErrTooLarge", err)
+		}
+		if yielded {
+			t.Error("recipes were read from an archive that is too large")
+		}
+	})
+
+	t.Run("too many entries", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for i := 0; i <= maxEntries; i++ {
+			entry(zw, fmt.Sprintf("recipes/%d.cook", i), 1)
+		}
+		zw.Close()
+
+		err := Read(FormatCooklang, "recipes.zip", buf.Bytes(), func(Entry) {})
+		if !errors.Is(err, ErrTooLarge) {
+			t.Errorf("got %v, want ErrTooLarge", err)
+		}
+	})
+
+	t.Run("entry", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		entry(zw, "huge.cook", maxEntryBytes+1)
+		w, _ := zw.Create("soup.cook")
+		w.Write(readTestdata(t, "soup.cook"))
+		zw.Close()
+
+		var errs []error
+		var names []string
+		err := Read(FormatCooklang, "recipes.zip", buf.Bytes(), func(e Entry) {
+			if e.Err != nil {
+				errs = append(errs, e.Err)
+				return
+			}
+			names = append(names, e.Recipe.Name)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], ErrTooLarge) {
+			t.Errorf("got errors %v, want one ErrTooLarge", errs)
+		}
+		if !reflect.DeepEqual(names, []string{"Tomato Soup"}) {
+			t.Errorf("got recipes %q, want the soup", names)
+		}
+	})
+
+	t.Run("gzipped paprika recipe", func(t *testing.T) {
+		// A few KB that gunzip to more than an entry may hold.
+		var gz bytes.Buffer
+		gw := gzip.NewWriter(&gz)
+		gw.Write([]byte(`{"name": "`))
+		gw.Write(make([]byte, maxEntryBytes))
+		gw.Write([]byte(`"}`))
+		gw.Close()
+
+		err := Read(FormatPaprika, "bomb.paprikarecipe", gz.Bytes(), func(Entry) {})
+		if !errors.Is(err, ErrTooLarge) {
+			t.Errorf("got %v, want ErrTooLarge", err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// mealieRecipe is a recipe of a Mealie export, which holds one JSON file
+// per recipe. The same shape is returned by the Mealie API.
+type mealieRecipe struct {
+	ID                 string             `json:"id"`
+	Slug               string             `json:"slug"`
+	Name               string             `json:"name"`
+	Description        string             `json:"description"`
+	OrgURL             string             `json:"orgURL"`
+	RecipeYield        string             `json:"recipeYield"`
+	RecipeServings     float64            `json:"recipeServings"`
+	TotalTime          string             `json:"totalTime"`
+	PrepTime           string             `json:"prepTime"`
+	CookTime           string             `json:"cookTime"`
+	PerformTime        string             `json:"performTime"`
+	RecipeCategory     []mealieName       `json:"recipeCategory"`
+	Tags               []mealieName       `json:"tags"`
+	RecipeIngredient   []mealieIngredient `json:"recipeIngredient"`
+	RecipeInstructions []mealieText       `json:"recipeInstructions"`
+	Notes              []mealieText       `json:"notes"`
+}
+
+type mealieIngredient struct {
+	Quantity     float64     `json:"quantity"`
+	Unit         *mealieName `json:"unit"`
+	Food         *mealieName `json:"food"`
+	Note         string      `json:"note"`
+	Display      string      `json:"display"`
+	OriginalText string      `json:"originalText"`
+}
+
+type mealieText struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// mealieName is an object with a name, or just the name in older exports.
+type mealieName string
+
+func (n *mealieName) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*n = mealieName(s)
+		return nil
+	}
+	var v struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = mealieName(v.Name)
+	return nil
+}
+
+func readMealie(name string, data []byte) ([]Entry, error) {
+	var recipes []mealieRecipe
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &recipes); err != nil {
+			return nil, fmt.Errorf("invalid Mealie recipes: %w", err)
+		}
+	} else {
+		var mr mealieRecipe
+		if err := json.Unmarshal(data, &mr); err != nil {
+			return nil, fmt.Errorf("invalid Mealie recipe: %w", err)
+		}
+		recipes = append(recipes, mr)
+	}
+
+	entries := make([]Entry, 0, len(recipes))
+	for _, mr := range recipes {
+		// Exports hold other JSON files too, such as the site settings
+		if mr.Name == "" && len(mr.RecipeIngredient) == 0 {
+			continue
+		}
+		entries = append(entries, Entry{File: name, Recipe: mealieToRecipe(name, mr)})
+	}
+	return entries, nil
+}
+
+func mealieToRecipe(file string, mr mealieRecipe) Recipe {
+	r := Recipe{
+		SourceID:    mr.ID,
+		Name:        strings.TrimSpace(mr.Name),
+		URL:         strings.TrimSpace(mr.OrgURL),
+		Description: strings.TrimSpace(mr.Description),
+	}
+	if r.SourceID == "" {
+		r.SourceID = mr.Slug
+	}
+	if r.SourceID == "" {
+		r.SourceID = fileStem(file)
+	}
+
+	r.Servings = int(mr.RecipeServings)
+	servings, yield := parseServings(mr.RecipeYield)
+	if r.Servings == 0 {
+		r.Servings = servings
+	}
+	r.Yield = yield
+
+	r.CookTimeInMinutes = parseMinutes(mr.TotalTime)
+	if r.CookTimeInMinutes == 0 {
+		r.CookTimeInMinutes = parseMinutes(mr.PrepTime) + parseMinutes(mr.CookTime) + parseMinutes(mr.PerformTime)
+	}
+
+	for _, c := range mr.RecipeCategory {
+		r.Cuisines = append(r.Cuisines, string(c))
+	}
+	if len(mr.Tags) > 0 {
+		r.warn("tags were left out")
+	}
+
+	for _, mi := range mr.RecipeIngredient {
+		if mi.Food == nil || *mi.Food == "" {
+			// Ingredients that Mealie did not parse only have text
+			line := strings.TrimSpace(mi.OriginalText)
+			if line == "" {
+				line = strings.TrimSpace(strings.Join(nonEmpty(mi.Display, mi.Note), " "))
+			}
+			if line != "" {
+				r.Ingredients = append(r.Ingredients, ParseIngredientLine(line))
+			}
+			continue
+		}
+
+		var amount []string
+		if mi.Quantity > 0 {
+			amount = append(amount, formatQuantity(mi.Quantity))
+		}
+		if mi.Unit != nil && *mi.Unit != "" {
+			amount = append(amount, string(*mi.Unit))
+		}
+		r.Ingredients = append(r.Ingredients, Ingredient{
+			Amount:   strings.Join(amount, " "),
+			Name:     strings.TrimSpace(string(*mi.Food)),
+			PrepNote: strings.TrimSpace(mi.Note),
+		})
+	}
+
+	for _, step := range mr.RecipeInstructions {
+		if text := strings.TrimSpace(step.Text); text != "" {
+			r.Instructions = append(r.Instructions, text)
+		}
+	}
+
+	var notes []string
+	for _, n := range mr.Notes {
+		text := strings.TrimSpace(n.Text)
+		if n.Title != "" {
+			text = strings.TrimSpace(n.Title + ": " + text)
+		}
+		if text != "" {
+			notes = append(notes, text)
+		}
+	}
+	r.Notes = strings.Join(notes, "\n\n")
+
+	return r
+}
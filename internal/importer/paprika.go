@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// paprikaRecipe is a recipe of a Paprika export. The .paprikarecipes file is
+// a zip archive of .paprikarecipe files, each a gzipped JSON document.
+type paprikaRecipe struct {
+	UID         string   `json:"uid"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Ingredients string   `json:"ingredients"`
+	Directions  string   `json:"directions"`
+	Notes       string   `json:"notes"`
+	Servings    string   `json:"servings"`
+	Source      string   `json:"source"`
+	SourceURL   string   `json:"source_url"`
+	PrepTime    string   `json:"prep_time"`
+	CookTime    string   `json:"cook_time"`
+	TotalTime   string   `json:"total_time"`
+	Categories  []string `json:"categories"`
+}
+
+func readPaprikaRecipe(name string, data []byte) ([]Entry, error) {
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		if data, err = readLimited(zr); err != nil {
+			return nil, err
+		}
+	}
+
+	var pr paprikaRecipe
+	if err := json.Unmarshal(data, &pr); err != nil {
+		return nil, fmt.Errorf("invalid Paprika recipe: %w", err)
+	}
+
+	r := Recipe{
+		SourceID:    pr.UID,
+		Name:        strings.TrimSpace(pr.Name),
+		URL:         strings.TrimSpace(pr.SourceURL),
+		Description: strings.TrimSpace(pr.Description),
+		Notes:       strings.TrimSpace(pr.Notes),
+		Cuisines:    pr.Categories,
+	}
+	if r.SourceID == "" {
+		r.SourceID = fileStem(name)
+	}
+	if r.URL == "" && strings.HasPrefix(pr.Source, "http") {
+		r.URL = strings.TrimSpace(pr.Source)
+	}
+	r.Servings, r.Yield = parseServings(pr.Servings)
+
+	r.CookTimeInMinutes = parseMinutes(pr.TotalTime)
+	if r.CookTimeInMinutes == 0 {
+		r.CookTimeInMinutes = parseMinutes(pr.PrepTime) + parseMinutes(pr.CookTime)
+	}
+
+	r.Ingredients = splitIngredients(&r, pr.Ingredients)
+	r.Instructions = splitSteps(pr.Directions)
+
+	return []Entry{{File: name, Recipe: r}}, nil
+}
@@ -0,0 +1,216 @@
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// units are the words read as part of an ingredient's amount when they
+// follow a quantity.
+var units = map[string]bool{}
+
+func init() {
+	for _, u := range []string{
+		"c", "cup", "cups",
+		"tbsp", "tbs", "tbl", "tablespoon", "tablespoons",
+		"tsp", "teaspoon", "teaspoons",
+		"oz", "ounce", "ounces",
+		"lb", "lbs", "pound", "pounds",
+		"g", "gram", "grams", "kg", "kilogram", "kilograms",
+		"ml", "milliliter", "milliliters", "millilitre", "millilitres",
+		"l", "liter", "liters", "litre", "litres", "dl", "cl",
+		"pt", "pint", "pints", "qt", "quart", "quarts", "gal", "gallon", "gallons",
+		"pinch", "pinches", "dash", "dashes", "drop", "drops",
+		"clove", "cloves", "can", "cans", "jar", "jars", "package", "packages", "pkg",
+		"stick", "sticks", "slice", "slices", "piece", "pieces",
+		"bunch", "bunches", "sprig", "sprigs", "head", "heads", "handful", "handfuls",
+		"inch", "inches", "bag", "bags", "bottle", "bottles", "box", "boxes",
+	} {
+		units[u] = true
+	}
+}
+
+const fractions = "½⅓⅔¼¾⅕⅖⅗⅘⅙⅚⅛⅜⅝⅞"
+
+// ParseIngredientLine splits a line such as "2 cloves garlic, minced" into
+// its amount ("2 cloves"), name ("garlic") and prep note ("minced").
+func ParseIngredientLine(line string) Ingredient {
+	line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•·▢□"))
+	words := strings.Fields(line)
+
+	n := 0
+	for n < len(words) && isQuantity(words[n], n > 0) {
+		n++
+	}
+	// A size in parentheses belongs to the amount: 1 (14 oz) can
+	if n > 0 && n < len(words) && strings.HasPrefix(words[n], "(") {
+		for j := n; j < len(words); j++ {
+			if strings.HasSuffix(words[j], ")") {
+				n = j + 1
+				break
+			}
+		}
+	}
+	if n > 0 && n < len(words) && isUnit(words[n]) {
+		n++
+		if n < len(words)-1 && strings.EqualFold(words[n], "of") {
+			n++
+		}
+	}
+
+	var ing Ingredient
+	if n > 0 && strings.EqualFold(words[n-1], "of") {
+		ing.Amount = strings.Join(words[:n-1], " ")
+	} else {
+		ing.Amount = strings.Join(words[:n], " ")
+	}
+	rest := strings.Join(words[n:], " ")
+
+	name, note, _ := strings.Cut(rest, ",")
+	name, note = strings.TrimSpace(name), strings.TrimSpace(note)
+	if i := strings.Index(name, "("); i > 0 && strings.HasSuffix(name, ")") {
+		paren := strings.TrimSpace(name[i+1 : len(name)-1])
+		name = strings.TrimSpace(name[:i])
+		note = strings.TrimSpace(strings.Join(nonEmpty(paren, note), ", "))
+	}
+	if name == "" {
+		// Nothing but an amount, keep the line as it is
+		return Ingredient{Name: line}
+	}
+	ing.Name, ing.PrepNote = name, note
+	return ing
+}
+
+func isQuantity(word string, afterQuantity bool) bool {
+	if afterQuantity && (strings.EqualFold(word, "to") || word == "-" || word == "–") {
+		return true
+	}
+	hasDigit := false
+	for _, r := range word {
+		switch {
+		case unicode.IsDigit(r) || strings.ContainsRune(fractions, r):
+			hasDigit = true
+		case strings.ContainsRune("./,-–", r):
+		default:
+			return false
+		}
+	}
+	return hasDigit
+}
+
+func isUnit(word string) bool {
+	return units[strings.ToLower(strings.TrimSuffix(word, "."))]
+}
+
+var (
+	isoDuration  = regexp.MustCompile(`(?i)^P(?:\d+D)?T?(?:(\d+)H)?(?:(\d+)M)?(?:\d+S)?$`)
+	hoursPart    = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(?:h|hr|hrs|hour|hours)\b`)
+	minutesPart  = regexp.MustCompile(`(?i)(\d+)\s*(?:m|min|mins|minute|minutes)\b`)
+	leadingCount = regexp.MustCompile(`^\s*(\d+)`)
+)
+
+// parseMinutes reads a duration such as "1 hr 30 mins", "45" or "PT1H30M"
+// in minutes. It is 0 when the duration can't be read.
+func parseMinutes(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	if m := isoDuration.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		mins, _ := strconv.Atoi(m[2])
+		return h*60 + mins
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+
+	total := 0.0
+	if m := hoursPart.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64)
+		total += h * 60
+	}
+	if m := minutesPart.FindStringSubmatch(s); m != nil {
+		mins, _ := strconv.Atoi(m[1])
+		total += float64(mins)
+	}
+	return int(total)
+}
+
+// parseServings reads the number of servings from text such as "4", "4-6"
+// or "makes 12 cookies". Text that says more than the number of servings is
+// also returned as the yield.
+func parseServings(s string) (servings int, yield string) {
+	s = strings.TrimSpace(s)
+	m := leadingCount.FindStringSubmatch(s)
+	if m == nil {
+		return 0, s
+	}
+	servings, _ = strconv.Atoi(m[1])
+	switch strings.ToLower(strings.TrimSpace(s[len(m[0]):])) {
+	case "", "serving", "servings", "people", "persons", "portions":
+		return servings, ""
+	}
+	return servings, s
+}
+
+var stepNumber = regexp.MustCompile(`(?i)^(?:step\s*)?\d+\s*[.):-]?\s+`)
+
+// splitSteps turns a block of directions into steps, one per non empty
+// line, without their numbering.
+func splitSteps(text string) []string {
+	var steps []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(stepNumber.ReplaceAllString(line, ""))
+		if line != "" {
+			steps = append(steps, line)
+		}
+	}
+	return steps
+}
+
+// splitIngredients reads one ingredient per non empty line. Lines that look
+// like the heading of a group, such as "For the sauce:", are left out.
+func splitIngredients(r *Recipe, text string) []Ingredient {
+	var ingredients []Ingredient
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasSuffix(line, ":") {
+			r.warn("ingredient group %q was left out", strings.TrimSuffix(line, ":"))
+			continue
+		}
+		ingredients = append(ingredients, ParseIngredientLine(line))
+	}
+	return ingredients
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func nonEmpty(ss ...string) []string {
+	var out []string
+	for _, s := range ss {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// formatQuantity writes a quantity without trailing zeros.
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
@@ -0,0 +1,211 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	sectionIngredients  = "ingredients"
+	sectionInstructions = "instructions"
+	sectionNotes        = "notes"
+)
+
+var sectionHeadings = map[string]string{
+	"ingredients":       sectionIngredients,
+	"ingredient list":   sectionIngredients,
+	"you will need":     sectionIngredients,
+	"you'll need":       sectionIngredients,
+	"what you need":     sectionIngredients,
+	"directions":        sectionInstructions,
+	"instructions":      sectionInstructions,
+	"method":            sectionInstructions,
+	"steps":             sectionInstructions,
+	"preparation":       sectionInstructions,
+	"how to make it":    sectionInstructions,
+	"notes":             sectionNotes,
+	"note":              sectionNotes,
+	"tips":              sectionNotes,
+	"cook's notes":      sectionNotes,
+	"recipe notes":      sectionNotes,
+	"notes and tips":    sectionNotes,
+	"tips and notes":    sectionNotes,
+	"variations":        sectionNotes,
+	"storage":           sectionNotes,
+	"make ahead":        sectionNotes,
+	"serving":           sectionNotes,
+	"to serve":          sectionNotes,
+	"equipment":         sectionNotes,
+	"special equipment": sectionNotes,
+}
+
+var (
+	servingsLine = regexp.MustCompile(`(?i)^(serves|servings|serving size|portions)\s*:?\s*(.+)$`)
+	yieldLine    = regexp.MustCompile(`(?i)^(yield|yields|makes)\s*:?\s*(.+)$`)
+	// numberedStep is stricter than stepNumber, as "1 cup flour" is not a
+	// step
+	numberedStep = regexp.MustCompile(`(?i)^(?:step\s*\d+[.):]?|\d+[.):])\s+`)
+	timeLine     = regexp.MustCompile(`(?i)^(prep(?:aration)? time|cook(?:ing)? time|bake time|total time|ready in|time)\s*:?\s*(.+)$`)
+)
+
+// ParseText reads a recipe pasted as plain text. The first line is taken
+// as the title, "Ingredients" and "Directions" headings split the rest, and
+// lines such as "Serves 4" or "Cook time: 30 minutes" are read wherever they
+// are. Without headings, lines that start with an amount are taken as
+// ingredients and those after them as steps.
+func ParseText(text string) Recipe {
+	var r Recipe
+	var intro, ingredientLines, stepLines, notes []string
+	var prepTime, cookTime, totalTime int
+	section, headings := "", false
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if s, ok := sectionHeadings[headingKey(line)]; ok {
+			section, headings = s, true
+			continue
+		}
+
+		if m := metadataLine(servingsLine, line, section); m != nil {
+			r.Servings, _ = parseServings(m[2])
+			continue
+		}
+		if m := metadataLine(yieldLine, line, section); m != nil {
+			servings, _ := parseServings(m[2])
+			if r.Servings == 0 {
+				r.Servings = servings
+			}
+			r.Yield = strings.TrimSpace(m[2])
+			continue
+		}
+		if m := metadataLine(timeLine, line, section); m != nil {
+			minutes := parseMinutes(m[2])
+			switch kind := strings.ToLower(m[1]); {
+			case strings.HasPrefix(kind, "prep"):
+				prepTime = minutes
+			case strings.HasPrefix(kind, "cook"), strings.HasPrefix(kind, "bake"):
+				cookTime = minutes
+			default:
+				totalTime = minutes
+			}
+			continue
+		}
+
+		if r.Name == "" && section == "" {
+			r.Name = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		}
+
+		switch section {
+		case sectionIngredients:
+			ingredientLines = append(ingredientLines, line)
+		case sectionInstructions:
+			stepLines = append(stepLines, line)
+		case sectionNotes:
+			notes = append(notes, line)
+		default:
+			intro = append(intro, line)
+		}
+	}
+
+	// Without headings, tell the parts apart by how their lines look
+	var description []string
+	for _, line := range intro {
+		switch {
+		case headings:
+			description = append(description, line)
+		case numberedStep.MatchString(line):
+			stepLines = append(stepLines, line)
+		case len(stepLines) == 0 && looksLikeIngredient(line):
+			ingredientLines = append(ingredientLines, line)
+		case len(ingredientLines) > 0:
+			stepLines = append(stepLines, line)
+		default:
+			description = append(description, line)
+		}
+	}
+
+	r.Description = strings.Join(description, "\n")
+	r.Ingredients = splitIngredients(&r, strings.Join(ingredientLines, "\n"))
+	r.Instructions = joinSteps(stepLines)
+	r.Notes = strings.Join(notes, "\n")
+
+	r.CookTimeInMinutes = totalTime
+	if r.CookTimeInMinutes == 0 {
+		r.CookTimeInMinutes = prepTime + cookTime
+	}
+
+	if r.Name == "" {
+		r.warn("no title found")
+	}
+	if len(r.Ingredients) == 0 {
+		r.warn("no ingredients found")
+	}
+	if len(r.Instructions) == 0 {
+		r.warn("no steps found")
+	}
+	if r.Servings == 0 {
+		r.warn("servings not found")
+	}
+	if r.CookTimeInMinutes == 0 {
+		r.warn("cook time not found")
+	}
+	return r
+}
+
+// metadataLine matches a line such as "Serves 4". Among the directions,
+// where "Makes a thick batter" is a step, the label must end with a colon.
+func metadataLine(re *regexp.Regexp, line, section string) []string {
+	m := re.FindStringSubmatch(line)
+	if m == nil || section != sectionInstructions {
+		return m
+	}
+	if !strings.HasPrefix(strings.TrimSpace(line[len(m[1]):]), ":") {
+		return nil
+	}
+	return m
+}
+
+// headingKey is a line as it would be written as a heading, such as
+// "## Ingredients:" or "DIRECTIONS".
+func headingKey(line string) string {
+	line = strings.TrimLeft(line, "#*= ")
+	line = strings.TrimRight(line, ":*= ")
+	return strings.ToLower(strings.TrimSpace(line))
+}
+
+func looksLikeIngredient(line string) bool {
+	if strings.ContainsAny(line[:1], "-*•·▢□") {
+		return true
+	}
+	words := strings.Fields(line)
+	return len(line) < 80 && !strings.HasSuffix(line, ".") && isQuantity(words[0], false)
+}
+
+// joinSteps makes a step of each numbered line and the lines that follow
+// it. When steps are not numbered, each line is a step.
+func joinSteps(lines []string) []string {
+	numbered := false
+	for _, line := range lines {
+		if numberedStep.MatchString(line) {
+			numbered = true
+			break
+		}
+	}
+	if !numbered {
+		return splitSteps(strings.Join(lines, "\n"))
+	}
+
+	var steps []string
+	for _, line := range lines {
+		if numberedStep.MatchString(line) || len(steps) == 0 {
+			steps = append(steps, strings.TrimSpace(stepNumber.ReplaceAllString(line, "")))
+			continue
+		}
+		steps[len(steps)-1] += " " + line
+	}
+	return steps
+}
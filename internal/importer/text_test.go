@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIngredientLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Ingredient
+	}{
+		{line: "2 cloves garlic, minced", want: Ingredient{Amount: "2 cloves", Name: "garlic", PrepNote: "minced"}},
+		{line: "1 onion", want: Ingredient{Amount: "1", Name: "onion"}},
+		{line: "salt", want: Ingredient{Name: "salt"}},
+		{line: "salt and pepper, to taste", want: Ingredient{Name: "salt and pepper", PrepNote: "to taste"}},
+		{line: "1 1/2 cups flour", want: Ingredient{Amount: "1 1/2 cups", Name: "flour"}},
+		{line: "½ tsp salt", want: Ingredient{Amount: "½ tsp", Name: "salt"}},
+		{line: "2-3 tbsp. olive oil", want: Ingredient{Amount: "2-3 tbsp.", Name: "olive oil"}},
+		{line: "2 to 3 carrots, peeled and sliced", want: Ingredient{Amount: "2 to 3", Name: "carrots", PrepNote: "peeled and sliced"}},
+		{line: "1 (14 oz) can coconut milk", want: Ingredient{Amount: "1 (14 oz) can", Name: "coconut milk"}},
+		{line: "2 cups of water", want: Ingredient{Amount: "2 cups", Name: "water"}},
+		{line: "1 cup cheese (grated)", want: Ingredient{Amount: "1 cup", Name: "cheese", PrepNote: "grated"}},
+		{line: "1 cup walnuts (toasted), chopped", want: Ingredient{Amount: "1 cup", Name: "walnuts", PrepNote: "toasted, chopped"}},
+		{line: "- 3 eggs", want: Ingredient{Amount: "3", Name: "eggs"}},
+		{line: "• 500 g chicken thighs", want: Ingredient{Amount: "500 g", Name: "chicken thighs"}},
+		{line: "  4 ", want: Ingredient{Name: "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := ParseIngredientLine(tt.line); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Recipe
+	}{
+		{
+			name: "headings",
+			text: `Tomato Soup
+
+A quick soup.
+Serves 4
+Prep time: 10 minutes
+Cook time: 25 minutes
+
+Ingredients
+2 tbsp olive oil
+1 onion, chopped
+
+Directions
+1. Cook the onion in the oil
+until soft.
+2. Simmer for 20 minutes.
+
+Notes
+Freezes well.
+`,
+			want: Recipe{
+				Name:              "Tomato Soup",
+				Description:       "A quick soup.",
+				Servings:          4,
+				CookTimeInMinutes: 35,
+				Notes:             "Freezes well.",
+				Ingredients: []Ingredient{
+					{Amount: "2 tbsp", Name: "olive oil"},
+					{Amount: "1", Name: "onion", PrepNote: "chopped"},
+				},
+				Instructions: []string{
+					"Cook the onion in the oil until soft.",
+					"Simmer for 20 minutes.",
+				},
+			},
+		},
+		{
+			name: "markdown headings",
+			text: "# Tomato Soup\n## Ingredients:\n- 1 onion\n## METHOD\nStep 1: Chop the onion.\nStep 2: Cook it.\nTotal time: 1 hr\nServings: 2",
+			want: Recipe{
+				Name:              "Tomato Soup",
+				Servings:          2,
+				CookTimeInMinutes: 60,
+				Ingredients:       []Ingredient{{Amount: "1", Name: "onion"}},
+				Instructions:      []string{"Chop the onion.", "Cook it."},
+			},
+		},
+		{
+			name: "no headings",
+			text: "Pancakes\nMakes 8 pancakes\nTotal time: 20 min\n1 cup flour\n1 egg\nWhisk everything together.\nFry in a hot pan.",
+			want: Recipe{
+				Name:              "Pancakes",
+				Servings:          8,
+				Yield:             "8 pancakes",
+				CookTimeInMinutes: 20,
+				Ingredients: []Ingredient{
+					{Amount: "1 cup", Name: "flour"},
+					{Amount: "1", Name: "egg"},
+				},
+				Instructions: []string{"Whisk everything together.", "Fry in a hot pan."},
+			},
+		},
+		{
+			name: "numbered steps without headings",
+			text: "Toast\nA slice of bread.\n1. Toast the bread.\n2) Butter it.\nServes 1\nTime: 5",
+			want: Recipe{
+				Name:              "Toast",
+				Description:       "A slice of bread.",
+				Servings:          1,
+				CookTimeInMinutes: 5,
+				Instructions:      []string{"Toast the bread.", "Butter it."},
+				Warnings:          []string{"no ingredients found"},
+			},
+		},
+		{
+			name: "metadata words in the directions",
+			text: "Batter\nIngredients\n1 cup flour\nDirections\nWhisk the flour with water.\nMakes a thick batter.\nServes 4 as a side.\nYield: 2 cups\nCook time: 1 minute",
+			want: Recipe{
+				Name:              "Batter",
+				Servings:          2,
+				Yield:             "2 cups",
+				CookTimeInMinutes: 1,
+				Ingredients:       []Ingredient{{Amount: "1 cup", Name: "flour"}},
+				Instructions:      []string{"Whisk the flour with water.", "Makes a thick batter.", "Serves 4 as a side."},
+			},
+		},
+		{
+			name: "ingredient groups",
+			text: "Tacos\nIngredients\nFor the salsa:\n2 tomatoes, diced\nFor the tacos:\n8 tortillas\nInstructions\nMake the salsa.\nFill the tortillas.\nServes: 4\nReady in: 30 minutes",
+			want: Recipe{
+				Name:              "Tacos",
+				Servings:          4,
+				CookTimeInMinutes: 30,
+				Ingredients: []Ingredient{
+					{Amount: "2", Name: "tomatoes", PrepNote: "diced"},
+					{Amount: "8", Name: "tortillas"},
+				},
+				Instructions: []string{"Make the salsa.", "Fill the tortillas."},
+				Warnings: []string{
+					`ingredient group "For the salsa" was left out`,
+					`ingredient group "For the tacos" was left out`,
+				},
+			},
+		},
+		{
+			name: "empty",
+			text: "\n  \n",
+			want: Recipe{
+				Warnings: []string{
+					"no title found",
+					"no ingredients found",
+					"no steps found",
+					"servings not found",
+					"cook time not found",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseText(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
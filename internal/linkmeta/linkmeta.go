@@ -0,0 +1,193 @@
+// Package linkmeta extracts the details of a recipe page, such as its title
+// and site name, from OpenGraph, Twitter card and oEmbed metadata.
+package linkmeta
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type Metadata struct {
+	Title       string
+	Description string
+	SiteName    string
+	Author      string
+	ImageURL    string
+	// VideoURL is a URL that can be embedded in an iframe.
+	VideoURL string
+	// OEmbedURL is the JSON oEmbed endpoint the page advertises.
+	OEmbedURL string
+}
+
+// Parse reads the metadata of an HTML page. Relative URLs are resolved
+// against base, which is the URL of the page.
+func Parse(r io.Reader, base *url.URL) (Metadata, error) {
+	// Sources are kept apart, to prefer OpenGraph over Twitter cards over
+	// plain HTML whatever the order of the tags
+	var og, tw, plain Metadata
+
+	z := html.NewTokenizer(r)
+	inTitle := false
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return merge(base, og, tw, plain), nil
+			}
+			return Metadata{}, z.Err()
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = false
+			case atom.Head:
+				// Everything we read is in the head
+				return merge(base, og, tw, plain), nil
+			}
+		case html.TextToken:
+			if inTitle && plain.Title == "" {
+				plain.Title = strings.TrimSpace(html.UnescapeString(string(z.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			tag := atom.Lookup(name)
+			if tag == atom.Title {
+				inTitle = tt == html.StartTagToken
+				continue
+			}
+			if !hasAttr || (tag != atom.Meta && tag != atom.Link) {
+				continue
+			}
+			attrs := readAttrs(z)
+			if tag == atom.Link {
+				if strings.EqualFold(attrs["rel"], "alternate") && strings.EqualFold(attrs["type"], "application/json+oembed") {
+					setOnce(&plain.OEmbedURL, attrs["href"])
+				}
+				continue
+			}
+
+			content := strings.TrimSpace(attrs["content"])
+			key := attrs["property"]
+			if key == "" {
+				key = attrs["name"]
+			}
+			switch strings.ToLower(key) {
+			case "og:title":
+				setOnce(&og.Title, content)
+			case "og:description":
+				setOnce(&og.Description, content)
+			case "og:site_name":
+				setOnce(&og.SiteName, content)
+			case "og:image", "og:image:url", "og:image:secure_url":
+				setOnce(&og.ImageURL, content)
+			case "og:video:secure_url", "og:video:url", "og:video":
+				setOnce(&og.VideoURL, content)
+			case "article:author", "book:author":
+				// Often a profile URL rather than a name
+				if !strings.Contains(content, "://") {
+					setOnce(&og.Author, content)
+				}
+			case "twitter:title":
+				setOnce(&tw.Title, content)
+			case "twitter:description":
+				setOnce(&tw.Description, content)
+			case "twitter:image", "twitter:image:src":
+				setOnce(&tw.ImageURL, content)
+			case "twitter:player":
+				setOnce(&tw.VideoURL, content)
+			case "twitter:creator":
+				setOnce(&tw.Author, content)
+			case "description":
+				setOnce(&plain.Description, content)
+			case "author":
+				setOnce(&plain.Author, content)
+			case "application-name":
+				setOnce(&plain.SiteName, content)
+			}
+		}
+	}
+}
+
+func readAttrs(z *html.Tokenizer) map[string]string {
+	attrs := map[string]string{}
+	for {
+		k, v, more := z.TagAttr()
+		attrs[strings.ToLower(string(k))] = string(v)
+		if !more {
+			return attrs
+		}
+	}
+}
+
+func setOnce(dst *string, v string) {
+	if *dst == "" {
+		*dst = strings.TrimSpace(v)
+	}
+}
+
+func merge(base *url.URL, sources ...Metadata) Metadata {
+	var m Metadata
+	for _, s := range sources {
+		m.fill(s)
+	}
+	m.ImageURL = resolve(base, m.ImageURL)
+	m.VideoURL = resolve(base, m.VideoURL)
+	m.OEmbedURL = resolve(base, m.OEmbedURL)
+	return m
+}
+
+// fill sets the fields of m that are empty from o.
+func (m *Metadata) fill(o Metadata) {
+	setOnce(&m.Title, o.Title)
+	setOnce(&m.Description, o.Description)
+	setOnce(&m.SiteName, o.SiteName)
+	setOnce(&m.Author, o.Author)
+	setOnce(&m.ImageURL, o.ImageURL)
+	setOnce(&m.VideoURL, o.VideoURL)
+	setOnce(&m.OEmbedURL, o.OEmbedURL)
+}
+
+func resolve(base *url.URL, ref string) string {
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+// OEmbed is the part of an oEmbed response that we use.
+type OEmbed struct {
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+func ParseOEmbed(data []byte) (OEmbed, error) {
+	var o OEmbed
+	err := json.Unmarshal(data, &o)
+	return o, err
+}
+
+// MergeOEmbed fills the fields of m that the page left empty. oEmbed is
+// preferred for the author, since pages seldom name it.
+func (m *Metadata) MergeOEmbed(o OEmbed) {
+	if o.AuthorName != "" {
+		m.Author = strings.TrimSpace(o.AuthorName)
+	}
+	m.fill(Metadata{
+		Title:    o.Title,
+		SiteName: o.ProviderName,
+		ImageURL: o.ThumbnailURL,
+	})
+}
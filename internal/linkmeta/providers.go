@@ -0,0 +1,57 @@
+package linkmeta
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Provider is a site whose links we recognize without reading the page.
+type Provider struct {
+	Name string
+	// EmbedURL is the iframe URL of the video, if the link is to one.
+	EmbedURL string
+	// OEmbedURL is the JSON oEmbed endpoint for the link, if the provider
+	// has one that works without credentials.
+	OEmbedURL string
+}
+
+var youtubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// Recognize returns the provider of u, for YouTube and Instagram links.
+func Recognize(u *url.URL) (Provider, bool) {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch host {
+	case "youtube.com", "youtube-nocookie.com", "youtu.be":
+		var id string
+		switch {
+		case host == "youtu.be":
+			id = segments[0]
+		case segments[0] == "watch":
+			id = u.Query().Get("v")
+		case len(segments) == 2 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live"):
+			id = segments[1]
+		}
+		p := Provider{Name: "YouTube"}
+		if youtubeID.MatchString(id) {
+			p.EmbedURL = "https://www.youtube.com/embed/" + id
+			p.OEmbedURL = "https://www.youtube.com/oembed?format=json&url=" +
+				url.QueryEscape("https://www.youtube.com/watch?v="+id)
+		}
+		return p, true
+
+	case "instagram.com":
+		p := Provider{Name: "Instagram"}
+		if len(segments) >= 2 {
+			switch segments[0] {
+			case "reel", "reels", "tv":
+				p.EmbedURL = "https://www.instagram.com/reel/" + url.PathEscape(segments[1]) + "/embed"
+			}
+		}
+		return p, true
+	}
+	return Provider{}, false
+}
@@ -0,0 +1,135 @@
+// Package logging configures the slog logger used across the app and carries
+// the request ID through context.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys, and URL query parameters, whose values
+// never make it into the logs.
+var sensitiveKeys = map[string]bool{
+	"password":         true,
+	"confirm_password": true,
+	"hash":             true,
+	"token":            true,
+	"refresh_token":    true,
+	"authorization":    true,
+	"cookie":           true,
+	"set-cookie":       true,
+	"jwt_secret":       true,
+	"secret":           true,
+}
+
+type ctxKey int
+
+const requestIDCtxKey ctxKey = iota
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDCtxKey).(string)
+	return requestID
+}
+
+// New builds a logger from LOG_FORMAT (json or text, defaults to text) and
+// LOG_LEVEL (debug, info, warn or error, defaults to info).
+func New(w io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}
+
+	var h slog.Handler
+	if strings.ToLower(os.Getenv("LOG_FORMAT")) == "json" {
+		h = slog.NewJSONHandler(w, opts)
+	} else {
+		h = slog.NewTextHandler(w, opts)
+	}
+
+	return slog.New(contextHandler{h})
+}
+
+// secretPath matches the paths that carry a secret, the token of the calendar
+// feed being in its URL so that calendar apps can subscribe to it.
+var secretPath = regexp.MustCompile(`^(/calendar/)[^/]+(\.ics)$`)
+
+// contextHandler adds the request ID and trace ID found in the record's
+// context, so that callers only need to use the *Context logging functions.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		r.AddAttrs(slog.String("request_id", requestID))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	if a.Value.Kind() == slog.KindString && (a.Key == "url" || a.Key == "path") {
+		return slog.String(a.Key, RedactURL(a.Value.String()))
+	}
+	return a
+}
+
+// RedactURL hides the secret of the paths that carry one and the values of
+// sensitive query parameters.
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	changed := false
+	if secretPath.MatchString(u.Path) {
+		u.Path = secretPath.ReplaceAllString(u.Path, "${1}"+redacted+"${2}")
+		u.RawPath = u.Path
+		changed = true
+	}
+	if u.RawQuery != "" {
+		q := u.Query()
+		for k := range q {
+			if sensitiveKeys[strings.ToLower(k)] {
+				q.Set(k, redacted)
+				changed = true
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	if !changed {
+		return rawURL
+	}
+	return u.String()
+}
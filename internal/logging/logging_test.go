@@ -0,0 +1,24 @@
+package logging
+
+import "testing"
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "/v1/recipes?page=2", want: "/v1/recipes?page=2"},
+		{url: "/reset?token=abc&page=2", want: "/reset?page=2&token=%5BREDACTED%5D"},
+		{url: "/calendar/abc123.ics", want: "/calendar/[REDACTED].ics"},
+		{url: "/calendar/abc123.ics?token=abc", want: "/calendar/[REDACTED].ics?token=%5BREDACTED%5D"},
+		{url: "/v1/calendar", want: "/v1/calendar"},
+		{url: "/calendar/a/b.ics", want: "/calendar/a/b.ics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := RedactURL(tt.url); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
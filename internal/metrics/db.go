@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quangd42/meal-org/internal/database"
+)
+
+var (
+	dbAcquiredConns = prometheus.NewDesc(namespace+"_db_pool_acquired_conns",
+		"Connections currently in use.", nil, nil)
+	dbIdleConns = prometheus.NewDesc(namespace+"_db_pool_idle_conns",
+		"Idle connections in the pool.", nil, nil)
+	dbTotalConns = prometheus.NewDesc(namespace+"_db_pool_total_conns",
+		"Connections in the pool, in use, idle or being established.", nil, nil)
+	dbMaxConns = prometheus.NewDesc(namespace+"_db_pool_max_conns",
+		"Maximum size of the pool.", nil, nil)
+	dbAcquires = prometheus.NewDesc(namespace+"_db_pool_acquires_total",
+		"Successful connection acquisitions from the pool.", nil, nil)
+	dbEmptyAcquires = prometheus.NewDesc(namespace+"_db_pool_empty_acquires_total",
+		"Acquisitions that had to wait because the pool was empty.", nil, nil)
+	dbCanceledAcquires = prometheus.NewDesc(namespace+"_db_pool_canceled_acquires_total",
+		"Acquisitions canceled by their context.", nil, nil)
+	dbAcquireDuration = prometheus.NewDesc(namespace+"_db_pool_acquire_duration_seconds_total",
+		"Time spent acquiring connections from the pool.", nil, nil)
+	activeSessions = prometheus.NewDesc(namespace+"_sessions_active",
+		"Unexpired rows in the sessions table.", nil, nil)
+)
+
+// dbCollector reads the pool statistics and the sessions table size when
+// scraped, rather than keeping gauges up to date.
+type dbCollector struct {
+	store *database.Store
+}
+
+// RegisterDB adds the database metrics of store to the registry.
+func RegisterDB(store *database.Store) {
+	Registry.MustRegister(dbCollector{store})
+}
+
+func (c dbCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		dbAcquiredConns, dbIdleConns, dbTotalConns, dbMaxConns,
+		dbAcquires, dbEmptyAcquires, dbCanceledAcquires, dbAcquireDuration,
+		activeSessions,
+	} {
+		ch <- d
+	}
+}
+
+func (c dbCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.store.DB.Stat()
+	ch <- prometheus.MustNewConstMetric(dbAcquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(dbIdleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(dbTotalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(dbMaxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(dbAcquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(dbEmptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(dbCanceledAcquires, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(dbAcquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	count, err := c.store.Q.CountSessions(ctx)
+	if err != nil {
+		slog.Warn("failed to count sessions", "error", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(activeSessions, prometheus.GaugeValue, float64(count))
+}
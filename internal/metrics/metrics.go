@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mealorg"
+
+// Outcomes of fetching the OG image of a recipe's external URL.
+const (
+	ImageFetchSuccess   = "success"
+	ImageFetchTimeout   = "timeout"
+	ImageFetchNoOGImage = "no_og_image"
+	ImageFetchError     = "error"
+)
+
+// Outcomes of running a background job.
+const (
+	JobSucceeded = "succeeded"
+	JobRetried   = "retried"
+	JobDead      = "dead"
+)
+
+// Outcomes of a login attempt.
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+)
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route pattern, method and status code.",
+	}, []string{"route", "method", "code"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests, by route pattern and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	ImageFetches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "external_image_fetches_total",
+		Help:      "Attempts to fetch the image of a recipe's external URL, by outcome.",
+	}, []string{"outcome"})
+
+	JobsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_processed_total",
+		Help:      "Background jobs run, by kind and outcome.",
+	}, []string{"kind", "outcome"})
+
+	RecipesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recipes_created_total",
+		Help:      "Recipes created.",
+	})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts, by outcome.",
+	}, []string{"outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		ImageFetches,
+		JobsProcessed,
+		RecipesCreated,
+		Logins,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+type CollectionRequest struct {
+	Name        string  `json:"name" validate:"required,max=255"`
+	Description *string `json:"description"`
+	// Filter makes a smart collection, whose recipes are those matching the
+	// filter when the collection is read.
+	Filter *CollectionFilter `json:"filter"`
+}
+
+func (cr CollectionRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(cr)
+}
+
+// CollectionFilter is the saved query of a smart collection. A recipe
+// matches when it has every tag and ingredient, one of the cuisines or of
+// their sub-cuisines, and cooks within MaxCookTime minutes. Empty fields
+// match every recipe.
+type CollectionFilter struct {
+	Tags          []string    `json:"tags,omitempty" validate:"max=20,dive,max=64"`
+	CuisineIDs    []uuid.UUID `json:"cuisine_ids,omitempty" validate:"max=50"`
+	IngredientIDs []uuid.UUID `json:"ingredient_ids,omitempty" validate:"max=20"`
+	MaxCookTime   int         `json:"max_cook_time,omitempty" validate:"gte=0"`
+}
+
+// CollectionRecipesRequest replaces the recipes of a manual collection, in
+// the order given.
+type CollectionRecipesRequest struct {
+	RecipeIDs []uuid.UUID `json:"recipe_ids" validate:"max=1000"`
+}
+
+func (cr CollectionRecipesRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(cr)
+}
+
+type CollectionRecipeRequest struct {
+	RecipeID uuid.UUID `json:"recipe_id" validate:"required"`
+}
+
+func (cr CollectionRecipeRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(cr)
+}
+
+// Collection is a cookbook of a user. The recipes of a manual collection
+// are kept in the order they were put in, those of a smart collection are
+// found by its Filter.
+type Collection struct {
+	ID          uuid.UUID         `json:"id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	UserID      uuid.UUID         `json:"user_id"`
+	Name        string            `json:"name"`
+	Description *string           `json:"description"`
+	Smart       bool              `json:"smart"`
+	Filter      *CollectionFilter `json:"filter,omitempty"`
+	// Recipes is only set when reading a single collection, and null in
+	// lists.
+	Recipes []RecipeInList `json:"recipes"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+// CookbookRequest selects the recipes of a cookbook. Every recipe of the
+// user goes in when RecipeIDs is empty.
+type CookbookRequest struct {
+	Title     string      `json:"title" validate:"max=200"`
+	RecipeIDs []uuid.UUID `json:"recipe_ids" validate:"max=500"`
+}
+
+func (cr CookbookRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(cr)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+// CookEventRequest logs a time a recipe was cooked. CookedAt defaults to
+// now.
+type CookEventRequest struct {
+	CookedAt *time.Time `json:"cooked_at"`
+	CookedBy *string    `json:"cooked_by" validate:"omitempty,max=255"`
+	Rating   *int       `json:"rating" validate:"omitempty,min=1,max=5"`
+	Notes    *string    `json:"notes"`
+	PhotoURL *string    `json:"photo_url" validate:"omitempty,url"`
+}
+
+func (cr CookEventRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(cr)
+}
+
+type FavoriteRequest struct {
+	Favorite bool `json:"favorite"`
+}
+
+func (fr FavoriteRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(fr)
+}
+
+// CookEvent is a time a recipe was cooked, with how it turned out.
+type CookEvent struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	RecipeID  uuid.UUID `json:"recipe_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CookedAt  time.Time `json:"cooked_at"`
+	CookedBy  *string   `json:"cooked_by"`
+	Rating    *int      `json:"rating"`
+	Notes     *string   `json:"notes"`
+	PhotoURL  *string   `json:"photo_url"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"context"
+
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+type RecipeTextRequest struct {
+	Text string `json:"text" validate:"required"`
+}
+
+func (tr RecipeTextRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(tr)
+}
+
+// RecipeDraft is a recipe read from pasted text, to be checked and
+// completed before it is saved.
+type RecipeDraft struct {
+	Recipe RecipeRequest `json:"recipe"`
+	// Unmatched are the ingredients we don't have yet. Their ID is empty in
+	// the recipe.
+	Unmatched []string `json:"unmatched_ingredients"`
+	Warnings  []string `json:"warnings"`
+}
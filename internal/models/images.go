@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type Image struct {
+	ContentType string
+	Data        []byte
+	ETag        string
+	ModTime     time.Time
+}
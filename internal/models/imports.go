@@ -0,0 +1,31 @@
+package models
+
+import "github.com/google/uuid"
+
+const (
+	ImportStatusImported = "imported"
+	ImportStatusSkipped  = "skipped"
+	ImportStatusFailed   = "failed"
+)
+
+// ImportReport tells what became of each recipe of a bulk import.
+type ImportReport struct {
+	Format   string         `json:"format"`
+	Imported int            `json:"imported"`
+	Skipped  int            `json:"skipped"`
+	Failed   int            `json:"failed"`
+	Results  []ImportResult `json:"results"`
+}
+
+type ImportResult struct {
+	// File is where the recipe was read from in the upload.
+	File     string `json:"file"`
+	SourceID string `json:"source_id,omitempty"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	// RecipeID is the recipe created, or the one created by an earlier
+	// import of the same source.
+	RecipeID *uuid.UUID `json:"recipe_id,omitempty"`
+	Warnings []string   `json:"warnings,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
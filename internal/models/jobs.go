@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusCancelled = "cancelled"
+	JobStatusDead      = "dead"
+)
+
+type Job struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Kind        string    `json:"kind"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	LastError   *string   `json:"last_error"`
+	RunAt       time.Time `json:"run_at"`
+}
+
+// IsActive reports whether the job is still waiting or running.
+func (j Job) IsActive() bool {
+	return j.Status == JobStatusPending || j.Status == JobStatusRunning
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+const DefaultMealPlanDays = 7
+
+// MealPlanSuggestRequest sets the constraints of a suggested plan. The plan
+// starts today and lasts a week unless told otherwise, a limit left at 0
+// is no limit. The same Seed with the same recipes gives the same plan.
+type MealPlanSuggestRequest struct {
+	StartDate            *Date    `json:"start_date"`
+	Days                 int      `json:"days" validate:"gte=0,max=31"`
+	MaxWeeknightCookTime int      `json:"max_weeknight_cook_time" validate:"gte=0"`
+	MaxPerCuisine        int      `json:"max_per_cuisine" validate:"gte=0"`
+	NoRepeatDays         int      `json:"no_repeat_days" validate:"gte=0,max=365"`
+	FavoriteWeight       *float64 `json:"favorite_weight" validate:"omitempty,gte=0,max=10"`
+	OverlapWeight        *float64 `json:"overlap_weight" validate:"omitempty,gte=0,max=10"`
+	Seed                 *int64   `json:"seed"`
+}
+
+func (mr MealPlanSuggestRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(mr)
+}
+
+// MealPlanDraft is a suggested plan, not saved until posted back, edited or
+// not, as a MealPlanRequest.
+type MealPlanDraft struct {
+	StartDate Date          `json:"start_date"`
+	Meals     []PlannedMeal `json:"meals"`
+	Warnings  []string      `json:"warnings"`
+	Seed      int64         `json:"seed"`
+}
+
+type MealPlanRequest struct {
+	Name      string               `json:"name" validate:"max=255"`
+	StartDate Date                 `json:"start_date" validate:"required"`
+	Meals     []PlannedMealRequest `json:"meals" validate:"max=100,dive"`
+}
+
+func (mr MealPlanRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(mr)
+}
+
+// PlannedMealRequest is a dinner of a plan. Time is the time of day as
+// 15:04, left out for a meal without one.
+type PlannedMealRequest struct {
+	Date     Date      `json:"date" validate:"required"`
+	RecipeID uuid.UUID `json:"recipe_id" validate:"required"`
+	Time     *string   `json:"time" validate:"omitempty,datetime=15:04"`
+}
+
+type MealPlan struct {
+	ID        uuid.UUID     `json:"id"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	UserID    uuid.UUID     `json:"user_id"`
+	Name      string        `json:"name"`
+	StartDate Date          `json:"start_date"`
+	Meals     []PlannedMeal `json:"meals"`
+}
+
+// PlannedMeal is a dinner of a plan or of a draft, where it has no ID.
+type PlannedMeal struct {
+	ID         *uuid.UUID `json:"id,omitempty"`
+	Date       Date       `json:"date"`
+	Time       *string    `json:"time"`
+	RecipeID   uuid.UUID  `json:"recipe_id"`
+	RecipeName string     `json:"recipe_name"`
+}
+
+// CalendarFeed is the iCalendar subscription of the meal plans of a user.
+// Anyone with the URL can read the feed, resetting it makes a new URL.
+type CalendarFeed struct {
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
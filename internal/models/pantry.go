@@ -0,0 +1,54 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+// DefaultExpiringWithinDays is how soon an item must expire for the
+// recipes using it to be cooked first.
+const DefaultExpiringWithinDays = 3
+
+// PantryItemRequest puts an ingredient in the pantry. Quantity and Unit are
+// left out for what is simply there, like salt, and ExpiresOn for what
+// keeps.
+type PantryItemRequest struct {
+	IngredientID uuid.UUID `json:"ingredient_id" validate:"required"`
+	Quantity     *float64  `json:"quantity" validate:"omitempty,gte=0"`
+	Unit         *string   `json:"unit" validate:"omitempty,max=32"`
+	ExpiresOn    *Date     `json:"expires_on"`
+}
+
+func (pr PantryItemRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(pr)
+}
+
+type PantryItem struct {
+	ID             uuid.UUID `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	IngredientID   uuid.UUID `json:"ingredient_id"`
+	IngredientName string    `json:"ingredient_name"`
+	Quantity       *float64  `json:"quantity"`
+	Unit           *string   `json:"unit"`
+	ExpiresOn      *Date     `json:"expires_on"`
+}
+
+// CookableRecipe is a recipe scored by what of it the pantry has. Score is
+// the share of its ingredients in the pantry, Missing names the others.
+// ExpiresOn is the first day an item it uses expires, Expiring is set when
+// that is soon.
+type CookableRecipe struct {
+	ID                uuid.UUID `json:"id"`
+	Name              string    `json:"name"`
+	CookTimeInMinutes int       `json:"cook_time_in_minutes"`
+	Score             float64   `json:"score"`
+	Covered           int       `json:"covered"`
+	Total             int       `json:"total"`
+	Missing           []string  `json:"missing"`
+	ExpiresOn         *Date     `json:"expires_on"`
+	Expiring          bool      `json:"expiring"`
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// RecipePatchDocument is the form of a recipe that PATCH requests apply to.
+// Ingredients are keyed by their index and instructions by their step
+// number, so that a patch can address one of them, as in
+// "/instructions/2", without rewriting the list.
+type RecipePatchDocument struct {
+	Name              string                     `json:"name"`
+	ExternalURL       *string                    `json:"external_url"`
+	Description       *string                    `json:"description"`
+	Servings          int                        `json:"servings"`
+	Yield             *string                    `json:"yield"`
+	CookTimeInMinutes int                        `json:"cook_time_in_minutes"`
+	Notes             *string                    `json:"notes"`
+	Cuisines          []uuid.UUID                `json:"cuisines"`
+	Ingredients       map[string]IngredientPatch `json:"ingredients"`
+	Instructions      map[string]string          `json:"instructions"`
+}
+
+type IngredientPatch struct {
+	ID       uuid.UUID `json:"id"`
+	Amount   string    `json:"amount"`
+	PrepNote *string   `json:"prep_note"`
+}
+
+func NewRecipePatchDocument(r Recipe) RecipePatchDocument {
+	cuisines := make([]uuid.UUID, len(r.Cuisines))
+	for i, c := range r.Cuisines {
+		cuisines[i] = c.ID
+	}
+	ingredients := make(map[string]IngredientPatch, len(r.Ingredients))
+	for _, i := range r.Ingredients {
+		ingredients[strconv.Itoa(i.Index)] = IngredientPatch{ID: i.ID, Amount: i.Amount, PrepNote: i.PrepNote}
+	}
+	instructions := make(map[string]string, len(r.Instructions))
+	for _, i := range r.Instructions {
+		instructions[strconv.Itoa(i.StepNo)] = i.Instruction
+	}
+
+	return RecipePatchDocument{
+		Name:              r.Name,
+		ExternalURL:       r.ExternalURL,
+		Description:       r.Description,
+		Servings:          r.Servings,
+		Yield:             r.Yield,
+		CookTimeInMinutes: r.CookTimeInMinutes,
+		Notes:             r.Notes,
+		Cuisines:          cuisines,
+		Ingredients:       ingredients,
+		Instructions:      instructions,
+	}
+}
+
+// RecipeRequest turns the patched document back into a request, which is
+// then validated like any other.
+func (d RecipePatchDocument) RecipeRequest() (RecipeRequest, error) {
+	errs := validator.NewValidationErrors()
+
+	ingredients := make([]IngredientInRecipe, 0, len(d.Ingredients))
+	for k, i := range d.Ingredients {
+		index, err := strconv.Atoi(k)
+		if err != nil || index < 1 {
+			errs["ingredients"] = []string{"Must be keyed by index"}
+			continue
+		}
+		ingredients = append(ingredients, IngredientInRecipe{ID: i.ID, Amount: i.Amount, PrepNote: i.PrepNote, Index: index})
+	}
+	sort.Slice(ingredients, func(a, b int) bool { return ingredients[a].Index < ingredients[b].Index })
+
+	instructions := make([]InstructionInRecipe, 0, len(d.Instructions))
+	for k, text := range d.Instructions {
+		step, err := strconv.Atoi(k)
+		if err != nil || step < 1 {
+			errs["instructions"] = []string{"Must be keyed by step number"}
+			continue
+		}
+		instructions = append(instructions, InstructionInRecipe{StepNo: step, Instruction: text})
+	}
+	sort.Slice(instructions, func(a, b int) bool { return instructions[a].StepNo < instructions[b].StepNo })
+
+	if len(errs) > 0 {
+		return RecipeRequest{}, errs
+	}
+	return RecipeRequest{
+		Name:              d.Name,
+		ExternalURL:       d.ExternalURL,
+		Description:       d.Description,
+		Servings:          d.Servings,
+		Yield:             d.Yield,
+		CookTimeInMinutes: d.CookTimeInMinutes,
+		Notes:             d.Notes,
+		Cuisines:          d.Cuisines,
+		Ingredients:       ingredients,
+		Instructions:      instructions,
+	}, nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,7 +11,7 @@ import (
 
 type RecipeRequest struct {
 	Name              string                `json:"name" validate:"required"`
-	ExternalURL       *string               `json:"external_url" validate:"required"`
+	ExternalURL       *string               `json:"external_url"`
 	Description       *string               `json:"description"`
 	Servings          int                   `json:"servings" validate:"required"`
 	Yield             *string               `json:"yield"`
@@ -26,21 +27,31 @@ func (rr RecipeRequest) Validate(ctx context.Context) error {
 }
 
 type Recipe struct {
-	ID                uuid.UUID             `json:"id"`
-	CreatedAt         time.Time             `json:"created_at"`
-	UpdatedAt         time.Time             `json:"updated_at"`
-	Name              string                `json:"name"`
-	ExternalURL       *string               `json:"external_url"`
-	ExternalImageURL  *string               `json:"external_image_url"`
-	Description       *string               `json:"description"`
-	UserID            uuid.UUID             `json:"user_id"`
-	Servings          int                   `json:"servings"`
-	Yield             *string               `json:"yield"`
-	CookTimeInMinutes int                   `json:"cook_time_in_minutes"`
-	Notes             *string               `json:"notes"`
-	Cuisines          []CuisineInRecipe     `json:"cuisines"`
-	Ingredients       []IngredientInRecipe  `json:"ingredients"`
-	Instructions      []InstructionInRecipe `json:"instructions"`
+	ID                uuid.UUID `json:"id"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	Name              string    `json:"name"`
+	ExternalURL       *string   `json:"external_url"`
+	ExternalImageURL  *string   `json:"external_image_url"`
+	SiteName          *string   `json:"site_name"`
+	VideoURL          *string   `json:"video_url"`
+	Description       *string   `json:"description"`
+	UserID            uuid.UUID `json:"user_id"`
+	Servings          int       `json:"servings"`
+	Yield             *string   `json:"yield"`
+	CookTimeInMinutes int       `json:"cook_time_in_minutes"`
+	Notes             *string   `json:"notes"`
+	// Version changes with every update, it is sent back to update the
+	// recipe only if nobody else did in the meantime.
+	Version      string                `json:"version"`
+	Cuisines     []CuisineInRecipe     `json:"cuisines"`
+	Ingredients  []IngredientInRecipe  `json:"ingredients"`
+	Instructions []InstructionInRecipe `json:"instructions"`
+}
+
+// RecipeVersion is the version of a recipe last updated at updatedAt.
+func RecipeVersion(updatedAt time.Time) string {
+	return strconv.FormatInt(updatedAt.UnixMicro(), 36)
 }
 
 type CuisineInRecipe struct {
@@ -68,15 +79,58 @@ type RecipeInList struct {
 	Name              string    `json:"name"`
 	ExternalURL       *string   `json:"external_url"`
 	ExternalImageURL  *string   `json:"external_image_url"`
+	SiteName          *string   `json:"site_name"`
+	VideoURL          *string   `json:"video_url"`
 	Description       *string   `json:"description"`
 	Cuisines          string    `json:"cuisines"`
 	UserID            uuid.UUID `json:"user_id"`
 	Servings          int       `json:"servings"`
 	Yield             *string   `json:"yield"`
 	CookTimeInMinutes int       `json:"cook_time_in_minutes"`
+	// DeletedAt is set for recipes in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// LastCookedAt, TimesCooked and AverageRating sum up the cook log. The
+	// average only counts the times the recipe was rated.
+	LastCookedAt  *time.Time `json:"last_cooked_at"`
+	TimesCooked   int        `json:"times_cooked"`
+	AverageRating *float64   `json:"average_rating"`
+	Favorite      bool       `json:"favorite"`
+}
+
+// LinkMetadata holds the details read from a recipe's external URL.
+type LinkMetadata struct {
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	SiteName    string `json:"site_name"`
+	Author      string `json:"author"`
+	ImageURL    string `json:"image_url"`
+	VideoURL    string `json:"video_url"`
 }
 
 type RecipesPagination struct {
 	Limit  int32
 	Offset int32
+	// Sort is one of the RecipeSort values, recipes are sorted by name
+	// otherwise.
+	Sort string
+}
+
+// Orders of the recipes list, besides by name. Recipes not cooked or rated
+// yet come last.
+const (
+	RecipeSortName        = "name"
+	RecipeSortLastCooked  = "last_cooked"
+	RecipeSortTimesCooked = "times_cooked"
+	RecipeSortRating      = "rating"
+	RecipeSortFavorite    = "favorite"
+)
+
+// IsRecipeSort reports whether s is one of the orders of the recipes list.
+func IsRecipeSort(s string) bool {
+	switch s {
+	case RecipeSortName, RecipeSortLastCooked, RecipeSortTimesCooked, RecipeSortRating, RecipeSortFavorite:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecipeRevision is a snapshot of a recipe, saved each time it changes.
+type RecipeRevision struct {
+	Revision  int       `json:"revision"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	// RestoredFrom is the revision this one restored, if any.
+	RestoredFrom *int   `json:"restored_from"`
+	Recipe       Recipe `json:"recipe"`
+	// Changes are from the previous revision, when listing revisions.
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is a field that differs between two revisions. List items,
+// such as ingredients, are compared one by one as "ingredients[2]". An empty
+// From or To means that the item was added or removed.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type RecipeDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
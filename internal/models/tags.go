@@ -0,0 +1,37 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,max=64"`
+}
+
+func (tr TagRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(tr)
+}
+
+// RecipeTagsRequest replaces the tags of a recipe. Tags are matched by name
+// regardless of case, and created when the user has none by that name.
+type RecipeTagsRequest struct {
+	Tags []string `json:"tags" validate:"max=50,dive,max=64"`
+}
+
+func (tr RecipeTagsRequest) Validate(ctx context.Context) error {
+	return validator.ValidateStruct(tr)
+}
+
+// Tag labels the recipes of a user, such as "weeknight" or "kid-friendly".
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	// RecipeCount is only set when listing tags.
+	RecipeCount int `json:"recipe_count,omitempty"`
+}
@@ -41,6 +41,7 @@ type User struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	IsAdmin   bool      `json:"is_admin"`
 }
 
 type UserWithToken struct {
@@ -0,0 +1,228 @@
+// Package planner suggests a plan of dinners from the recipes of a user,
+// within constraints on cook time, variety and repeats, and favoring the
+// recipes that share ingredients so that less goes to waste. It knows
+// nothing of the database: the same recipes, constraints and seed always
+// give the same plan.
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrNoRecipes = errors.New("planner: no recipes to plan with")
+
+// PlanSuggester drafts a plan of one dinner a day.
+type PlanSuggester interface {
+	Suggest(recipes []Recipe, c Constraints, seed int64) (Plan, error)
+}
+
+// Recipe is what the planner knows of a recipe.
+type Recipe struct {
+	ID   uuid.UUID
+	Name string
+	// CookTime is in minutes, 0 when unknown.
+	CookTime      int
+	CuisineIDs    []uuid.UUID
+	IngredientIDs []uuid.UUID
+	Favorite      bool
+	// Rating is the average rating out of 5, 0 when never rated.
+	Rating float64
+	// LastCooked is the zero time when never cooked.
+	LastCooked time.Time
+}
+
+// Constraints shape the plan. The zero value of a limit means no limit.
+type Constraints struct {
+	// Start is the first day of the plan, Days how many it covers.
+	Start time.Time
+	Days  int
+	// MaxWeeknightCookTime caps the cook time of the dinners from Monday to
+	// Thursday, recipes without a cook time always fit.
+	MaxWeeknightCookTime int
+	// MaxPerCuisine caps how many dinners of the plan share a cuisine.
+	MaxPerCuisine int
+	// NoRepeatDays keeps a recipe from coming back within that many days,
+	// counting the last time it was cooked.
+	NoRepeatDays int
+	// FavoriteWeight is how many times likelier a favorite is picked.
+	FavoriteWeight float64
+	// OverlapWeight rewards the recipes using the ingredients of those
+	// already planned, at 1 a recipe sharing all of them is twice as likely.
+	OverlapWeight float64
+}
+
+// Plan is a draft of dinners, in order of day. The days no recipe fits are
+// left out, with a warning saying why.
+type Plan struct {
+	Meals    []Meal
+	Warnings []string
+	Seed     int64
+}
+
+type Meal struct {
+	Date     time.Time
+	RecipeID uuid.UUID
+	Name     string
+}
+
+// Suggester picks each day at random among the recipes that fit, weighted
+// by favorite, rating and ingredients shared with the days before. When
+// nothing fits it first gives up on cuisine variety, then on repeats, but
+// never on cook time.
+type Suggester struct{}
+
+var _ PlanSuggester = Suggester{}
+
+func (Suggester) Suggest(recipes []Recipe, c Constraints, seed int64) (Plan, error) {
+	plan := Plan{Seed: seed}
+	if len(recipes) == 0 {
+		return plan, ErrNoRecipes
+	}
+
+	// The order recipes come in must not change the plan.
+	recipes = append([]Recipe(nil), recipes...)
+	sort.Slice(recipes, func(i, j int) bool {
+		return recipes[i].ID.String() < recipes[j].ID.String()
+	})
+
+	p := planning{
+		c:           c,
+		rng:         rand.New(rand.NewSource(seed)),
+		lastUsed:    map[uuid.UUID]time.Time{},
+		cuisines:    map[uuid.UUID]int{},
+		ingredients: map[uuid.UUID]bool{},
+	}
+	for _, r := range recipes {
+		if !r.LastCooked.IsZero() {
+			p.lastUsed[r.ID] = truncateDay(r.LastCooked)
+		}
+	}
+
+	start := truncateDay(c.Start)
+	for d := 0; d < c.Days; d++ {
+		day := start.AddDate(0, 0, d)
+		r, warning, ok := p.pick(recipes, day)
+		if warning != "" {
+			plan.Warnings = append(plan.Warnings, warning)
+		}
+		if !ok {
+			continue
+		}
+		p.use(r, day)
+		plan.Meals = append(plan.Meals, Meal{Date: day, RecipeID: r.ID, Name: r.Name})
+	}
+	return plan, nil
+}
+
+type planning struct {
+	c           Constraints
+	rng         *rand.Rand
+	lastUsed    map[uuid.UUID]time.Time
+	cuisines    map[uuid.UUID]int
+	ingredients map[uuid.UUID]bool
+}
+
+// pick chooses the recipe of day, relaxing the constraints it can when
+// nothing fits.
+func (p *planning) pick(recipes []Recipe, day time.Time) (Recipe, string, bool) {
+	date := day.Format("Mon Jan 2")
+	for _, relax := range []struct {
+		variety, repeats bool
+		warning          string
+	}{
+		{},
+		{variety: true, warning: fmt.Sprintf("%s: repeats a cuisine past the limit", date)},
+		{variety: true, repeats: true, warning: fmt.Sprintf("%s: repeats a recipe within %d days", date, p.c.NoRepeatDays)},
+	} {
+		var fits []Recipe
+		for _, r := range recipes {
+			if p.fits(r, day, relax.variety, relax.repeats) {
+				fits = append(fits, r)
+			}
+		}
+		if len(fits) > 0 {
+			return p.draw(fits), relax.warning, true
+		}
+	}
+	return Recipe{}, fmt.Sprintf("%s: no recipe fits the weeknight cook time", date), false
+}
+
+func (p *planning) fits(r Recipe, day time.Time, ignoreVariety, ignoreRepeats bool) bool {
+	if p.c.MaxWeeknightCookTime > 0 && isWeeknight(day) && r.CookTime > p.c.MaxWeeknightCookTime {
+		return false
+	}
+	if !ignoreRepeats {
+		last, ok := p.lastUsed[r.ID]
+		if ok && p.c.NoRepeatDays > 0 && day.Sub(last) < time.Duration(p.c.NoRepeatDays)*24*time.Hour {
+			return false
+		}
+	}
+	if !ignoreVariety && p.c.MaxPerCuisine > 0 {
+		for _, id := range r.CuisineIDs {
+			if p.cuisines[id] >= p.c.MaxPerCuisine {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// draw picks one of recipes at random, in proportion to its weight.
+func (p *planning) draw(recipes []Recipe) Recipe {
+	weights := make([]float64, len(recipes))
+	var total float64
+	for i, r := range recipes {
+		weights[i] = p.weight(r)
+		total += weights[i]
+	}
+	x := p.rng.Float64() * total
+	for i, w := range weights {
+		if x < w {
+			return recipes[i]
+		}
+		x -= w
+	}
+	return recipes[len(recipes)-1]
+}
+
+func (p *planning) weight(r Recipe) float64 {
+	w := 1 + r.Rating/5
+	if r.Favorite && p.c.FavoriteWeight > 0 {
+		w *= p.c.FavoriteWeight
+	}
+	if p.c.OverlapWeight > 0 && len(r.IngredientIDs) > 0 {
+		var shared int
+		for _, id := range r.IngredientIDs {
+			if p.ingredients[id] {
+				shared++
+			}
+		}
+		w *= 1 + p.c.OverlapWeight*float64(shared)/float64(len(r.IngredientIDs))
+	}
+	return w
+}
+
+func (p *planning) use(r Recipe, day time.Time) {
+	p.lastUsed[r.ID] = day
+	for _, id := range r.CuisineIDs {
+		p.cuisines[id]++
+	}
+	for _, id := range r.IngredientIDs {
+		p.ingredients[id] = true
+	}
+}
+
+func isWeeknight(day time.Time) bool {
+	wd := day.Weekday()
+	return wd >= time.Monday && wd <= time.Thursday
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,196 @@
+package planner
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// monday is the start of the plans, Mar 4 2024 being a Monday.
+var monday = time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+var (
+	italian  = uuid.MustParse("11111111-1111-4111-8111-111111111111")
+	mexican  = uuid.MustParse("22222222-2222-4222-8222-222222222222")
+	tomato   = uuid.MustParse("33333333-3333-4333-8333-333333333333")
+	tortilla = uuid.MustParse("44444444-4444-4444-8444-444444444444")
+)
+
+func recipe(name string, cookTime int, cuisines ...uuid.UUID) Recipe {
+	return Recipe{
+		ID:         uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)),
+		Name:       name,
+		CookTime:   cookTime,
+		CuisineIDs: cuisines,
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name         string
+		recipes      []Recipe
+		c            Constraints
+		wantMeals    int
+		wantWarnings []string
+	}{
+		{
+			name:      "no constraints",
+			recipes:   []Recipe{recipe("Pasta", 20, italian), recipe("Tacos", 30, mexican)},
+			c:         Constraints{Start: monday, Days: 7},
+			wantMeals: 7,
+		},
+		{
+			name:    "cook time is never relaxed",
+			recipes: []Recipe{recipe("Lasagna", 90, italian), recipe("Roast", 120)},
+			c:       Constraints{Start: monday, Days: 7, MaxWeeknightCookTime: 45},
+			// Friday to Sunday only.
+			wantMeals: 3,
+			wantWarnings: []string{
+				"Mon Mar 4: no recipe fits the weeknight cook time",
+				"Tue Mar 5: no recipe fits the weeknight cook time",
+				"Wed Mar 6: no recipe fits the weeknight cook time",
+				"Thu Mar 7: no recipe fits the weeknight cook time",
+			},
+		},
+		{
+			name:      "unknown cook time fits",
+			recipes:   []Recipe{recipe("Leftovers", 0)},
+			c:         Constraints{Start: monday, Days: 2, MaxWeeknightCookTime: 15},
+			wantMeals: 2,
+		},
+		{
+			name:      "cuisine limit is relaxed",
+			recipes:   []Recipe{recipe("Pasta", 20, italian), recipe("Pizza", 30, italian)},
+			c:         Constraints{Start: monday, Days: 2, MaxPerCuisine: 1},
+			wantMeals: 2,
+			wantWarnings: []string{
+				"Tue Mar 5: repeats a cuisine past the limit",
+			},
+		},
+		{
+			name:      "repeats are relaxed",
+			recipes:   []Recipe{recipe("Pasta", 20, italian)},
+			c:         Constraints{Start: monday, Days: 2, NoRepeatDays: 7},
+			wantMeals: 2,
+			wantWarnings: []string{
+				"Tue Mar 5: repeats a recipe within 7 days",
+			},
+		},
+		{
+			name:      "cuisine limit is relaxed before repeats",
+			recipes:   []Recipe{recipe("Pasta", 20, italian), recipe("Pizza", 30, italian)},
+			c:         Constraints{Start: monday, Days: 3, MaxPerCuisine: 1, NoRepeatDays: 7},
+			wantMeals: 3,
+			wantWarnings: []string{
+				"Tue Mar 5: repeats a cuisine past the limit",
+				"Wed Mar 6: repeats a recipe within 7 days",
+			},
+		},
+		{
+			name: "last cooked counts as a repeat",
+			recipes: []Recipe{func() Recipe {
+				r := recipe("Pasta", 20, italian)
+				r.LastCooked = monday.AddDate(0, 0, -2).Add(19 * time.Hour)
+				return r
+			}()},
+			c:         Constraints{Start: monday, Days: 1, NoRepeatDays: 3},
+			wantMeals: 1,
+			wantWarnings: []string{
+				"Mon Mar 4: repeats a recipe within 3 days",
+			},
+		},
+		{
+			name:    "cook time holds while relaxing the rest",
+			recipes: []Recipe{recipe("Pasta", 20, italian), recipe("Lasagna", 90, italian)},
+			c:       Constraints{Start: monday, Days: 2, MaxWeeknightCookTime: 30, MaxPerCuisine: 1, NoRepeatDays: 7},
+			// Pasta both days, Lasagna never fits.
+			wantMeals: 2,
+			wantWarnings: []string{
+				"Tue Mar 5: repeats a recipe within 7 days",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := Suggester{}.Suggest(tt.recipes, tt.c, 42)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Meals) != tt.wantMeals {
+				t.Errorf("got %d meals, want %d: %+v", len(plan.Meals), tt.wantMeals, plan.Meals)
+			}
+			if !reflect.DeepEqual(plan.Warnings, tt.wantWarnings) {
+				t.Errorf("got warnings %q, want %q", plan.Warnings, tt.wantWarnings)
+			}
+
+			cookTimes := map[uuid.UUID]int{}
+			for _, r := range tt.recipes {
+				cookTimes[r.ID] = r.CookTime
+			}
+			for _, m := range plan.Meals {
+				if tt.c.MaxWeeknightCookTime > 0 && isWeeknight(m.Date) && cookTimes[m.RecipeID] > tt.c.MaxWeeknightCookTime {
+					t.Errorf("%s: %s takes longer than %d minutes", m.Date.Format("Mon Jan 2"), m.Name, tt.c.MaxWeeknightCookTime)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestNoRecipes(t *testing.T) {
+	_, err := Suggester{}.Suggest(nil, Constraints{Start: monday, Days: 7}, 1)
+	if !errors.Is(err, ErrNoRecipes) {
+		t.Errorf("got %v, want ErrNoRecipes", err)
+	}
+}
+
+func TestSuggestSameSeed(t *testing.T) {
+	recipes := []Recipe{
+		recipe("Pasta", 20, italian),
+		recipe("Pizza", 30, italian),
+		recipe("Tacos", 25, mexican),
+		recipe("Enchiladas", 50, mexican),
+		recipe("Soup", 40),
+		recipe("Salad", 10),
+	}
+	recipes[0].IngredientIDs = []uuid.UUID{tomato}
+	recipes[1].IngredientIDs = []uuid.UUID{tomato}
+	recipes[2].IngredientIDs = []uuid.UUID{tortilla, tomato}
+	recipes[3].IngredientIDs = []uuid.UUID{tortilla}
+	recipes[4].Favorite = true
+	recipes[5].Rating = 4.5
+	c := Constraints{
+		Start:                monday,
+		Days:                 14,
+		MaxWeeknightCookTime: 45,
+		MaxPerCuisine:        4,
+		NoRepeatDays:         3,
+		FavoriteWeight:       2,
+		OverlapWeight:        1,
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		want, err := Suggester{}.Suggest(recipes, c, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want.Seed != seed {
+			t.Errorf("got seed %d, want %d", want.Seed, seed)
+		}
+
+		shuffled := append([]Recipe(nil), recipes...)
+		rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+		got, err := Suggester{}.Suggest(shuffled, c, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("seed %d: the order of the recipes changed the plan:\n%+v\n%+v", seed, got, want)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/quangd42/meal-org/internal/auth"
 	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/metrics"
 	"github.com/quangd42/meal-org/internal/models"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -92,8 +93,15 @@ func (as Auth) RevokeRefreshToken(ctx context.Context, refreshToken string) erro
 	return nil
 }
 
-func (as Auth) Login(ctx context.Context, lr models.LoginRequest) (models.User, error) {
-	var u models.User
+func (as Auth) Login(ctx context.Context, lr models.LoginRequest) (u models.User, err error) {
+	defer func() {
+		if err != nil {
+			metrics.Logins.WithLabelValues(metrics.LoginFailure).Inc()
+			return
+		}
+		metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
+	}()
+
 	user, err := as.store.Q.GetUserByEmail(ctx, lr.Email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -2,12 +2,17 @@ package services
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/quangd42/meal-org/internal/auth"
 )
 
+var ErrAdminOnly = errors.New("only admins can do this")
+
 type contextKey int
 
 const (
@@ -43,6 +48,37 @@ func (as Auth) AuthVerifier() func(http.Handler) http.Handler {
 	}
 }
 
+// AdminVerifier lets through the users who are admins, it goes after
+// AuthVerifier.
+func (as Auth) AdminVerifier() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			userID, err := UserIDFromContext(r)
+			if err != nil {
+				http.Error(w, auth.ErrTokenNotFound.Error(), http.StatusUnauthorized)
+				return
+			}
+			user, err := as.store.Q.GetUserByID(r.Context(), userID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					http.Error(w, auth.ErrTokenNotFound.Error(), http.StatusUnauthorized)
+					return
+				}
+				slog.ErrorContext(r.Context(), "looking up admin rights", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if !user.IsAdmin {
+				http.Error(w, ErrAdminOnly.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(hfn)
+	}
+}
+
 func UserIDFromContext(r *http.Request) (uuid.UUID, error) {
 	userID, ok := r.Context().Value(userIDCtxKey).(uuid.UUID)
 	if !ok {
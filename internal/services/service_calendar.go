@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/auth"
+	"github.com/quangd42/meal-org/internal/calendar"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// calendarPastDays is how far back the feed keeps planned meals.
+const calendarPastDays = 30
+
+// GetCalendarFeed returns the feed of the user, whose URL starts with
+// baseURL.
+func (rs RecipeService) GetCalendarFeed(ctx context.Context, userID uuid.UUID, baseURL string) (models.CalendarFeed, error) {
+	ctx, span := startSpan(ctx, "RecipeService.GetCalendarFeed")
+	defer span.End()
+
+	token, err := rs.store.Q.GetCalendarTokenByUserID(ctx, userID)
+	if err != nil {
+		return models.CalendarFeed{}, checkErrNoRows(err)
+	}
+	return createCalendarFeedResponse(token, baseURL), nil
+}
+
+// ResetCalendarFeed gives the user a feed with a new secret URL, the old
+// one stops working.
+func (rs RecipeService) ResetCalendarFeed(ctx context.Context, userID uuid.UUID, baseURL string) (models.CalendarFeed, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ResetCalendarFeed")
+	defer span.End()
+
+	value, err := auth.GenerateURLToken()
+	if err != nil {
+		return models.CalendarFeed{}, err
+	}
+	token, err := rs.store.Q.SaveCalendarToken(ctx, database.SaveCalendarTokenParams{
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+		Value:     value,
+	})
+	if err != nil {
+		return models.CalendarFeed{}, checkErrDBConstraint(err)
+	}
+	return createCalendarFeedResponse(token, baseURL), nil
+}
+
+func (rs RecipeService) DeleteCalendarFeed(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteCalendarFeed")
+	defer span.End()
+
+	n, err := rs.store.Q.DeleteCalendarToken(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// MealPlanCalendar writes the meals planned by the owner of token as an
+// iCalendar feed, from a month back on. Recipes link to their page under
+// baseURL.
+func (rs RecipeService) MealPlanCalendar(ctx context.Context, token, baseURL string) ([]byte, error) {
+	ctx, span := startSpan(ctx, "RecipeService.MealPlanCalendar")
+	defer span.End()
+
+	t, err := rs.store.Q.GetCalendarTokenByValue(ctx, token)
+	if err != nil {
+		return nil, checkErrNoRows(err)
+	}
+
+	rows, err := rs.store.Q.ListCalendarMeals(ctx, database.ListCalendarMealsParams{
+		UserID: t.UserID,
+		Day:    models.NewDate(time.Now().UTC()).AddDate(0, 0, -calendarPastDays),
+	})
+	if err != nil {
+		return nil, err
+	}
+	meals := make([]calendar.Meal, 0, len(rows))
+	for _, r := range rows {
+		meal := calendar.Meal{
+			ID:          r.ID,
+			Date:        r.Day,
+			Name:        r.RecipeName,
+			URL:         baseURL + "/recipes/" + r.RecipeID.String(),
+			CookTime:    int(r.CookTimeInMinutes),
+			Ingredients: r.Ingredients,
+		}
+		if r.ServeTime != nil {
+			meal.Time = *r.ServeTime
+		}
+		if r.Notes != nil {
+			meal.Notes = *r.Notes
+		}
+		meals = append(meals, meal)
+	}
+
+	var buf bytes.Buffer
+	if err := calendar.Write(&buf, "Meal plan", meals, time.Now()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func createCalendarFeedResponse(t database.CalendarToken, baseURL string) models.CalendarFeed {
+	return models.CalendarFeed{
+		URL:       baseURL + "/calendar/" + t.Value + ".ics",
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// ErrSmartCollection is returned when putting recipes in a smart collection
+// by hand, its recipes are those matching its filter.
+var ErrSmartCollection = errors.New("the recipes of a smart collection are chosen by its filter")
+
+func (rs RecipeService) CreateCollection(ctx context.Context, userID uuid.UUID, arg models.CollectionRequest) (models.Collection, error) {
+	ctx, span := startSpan(ctx, "RecipeService.CreateCollection")
+	defer span.End()
+
+	filter, err := marshalCollectionFilter(arg.Filter)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	collection, err := rs.store.Q.CreateCollection(ctx, database.CreateCollectionParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+		UserID:      userID,
+		Name:        strings.TrimSpace(arg.Name),
+		Description: arg.Description,
+		Filter:      filter,
+	})
+	if err != nil {
+		return models.Collection{}, checkErrDBConstraint(err)
+	}
+	return createCollectionResponse(collection)
+}
+
+func (rs RecipeService) ListCollections(ctx context.Context, userID uuid.UUID) ([]models.Collection, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListCollections")
+	defer span.End()
+
+	collections := []models.Collection{}
+	dbCollections, err := rs.store.Q.ListCollectionsByUserID(ctx, userID)
+	if err != nil {
+		return collections, err
+	}
+	for _, c := range dbCollections {
+		collection, err := createCollectionResponse(c)
+		if err != nil {
+			return collections, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, nil
+}
+
+// GetCollection reads a collection with its recipes. Those of a smart
+// collection are found again at every read, so new recipes show up in it.
+func (rs RecipeService) GetCollection(ctx context.Context, userID, collectionID uuid.UUID) (models.Collection, error) {
+	ctx, span := startSpan(ctx, "RecipeService.GetCollection")
+	defer span.End()
+
+	dbCollection, err := rs.store.Q.GetCollectionByID(ctx, database.GetCollectionByIDParams{
+		ID:     collectionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return models.Collection{}, checkErrNoRows(err)
+	}
+	collection, err := createCollectionResponse(dbCollection)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	collection.Recipes = []models.RecipeInList{}
+	if collection.Smart {
+		recipes, err := rs.listRecipesByFilter(ctx, userID, *collection.Filter)
+		if err != nil {
+			return models.Collection{}, err
+		}
+		collection.Recipes = recipes
+		return collection, nil
+	}
+
+	rows, err := rs.store.Q.ListCollectionRecipes(ctx, collectionID)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	for _, r := range rows {
+		collection.Recipes = append(collection.Recipes, recipeWithCuisinesResponse(database.ListRecipesWithCuisinesByUserIDRow(r)))
+	}
+	return collection, nil
+}
+
+// UpdateCollection changes the name, description and filter of a
+// collection. A collection that becomes smart loses its recipes picked by
+// hand.
+func (rs RecipeService) UpdateCollection(ctx context.Context, userID, collectionID uuid.UUID, arg models.CollectionRequest) (models.Collection, error) {
+	ctx, span := startSpan(ctx, "RecipeService.UpdateCollection")
+	defer span.End()
+
+	filter, err := marshalCollectionFilter(arg.Filter)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	tx, err := rs.store.DB.Begin(ctx)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := rs.store.Q.WithTx(tx)
+
+	collection, err := qtx.UpdateCollectionByID(ctx, database.UpdateCollectionByIDParams{
+		ID:          collectionID,
+		UserID:      userID,
+		Name:        strings.TrimSpace(arg.Name),
+		Description: arg.Description,
+		Filter:      filter,
+		UpdatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return models.Collection{}, customDBErr(err)
+	}
+	if filter != nil {
+		if err := qtx.DeleteCollectionRecipes(ctx, collectionID); err != nil {
+			return models.Collection{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Collection{}, err
+	}
+	return createCollectionResponse(collection)
+}
+
+func (rs RecipeService) DeleteCollection(ctx context.Context, userID, collectionID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteCollection")
+	defer span.End()
+
+	n, err := rs.store.Q.DeleteCollectionByID(ctx, database.DeleteCollectionByIDParams{
+		ID:     collectionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// SetCollectionRecipes replaces the recipes of a manual collection, which
+// keeps them in the order given.
+func (rs RecipeService) SetCollectionRecipes(ctx context.Context, userID, collectionID uuid.UUID, recipeIDs []uuid.UUID) (models.Collection, error) {
+	ctx, span := startSpan(ctx, "RecipeService.SetCollectionRecipes")
+	defer span.End()
+
+	tx, err := rs.store.DB.Begin(ctx)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := rs.store.Q.WithTx(tx)
+
+	if err := checkManualCollection(ctx, qtx, userID, collectionID); err != nil {
+		return models.Collection{}, err
+	}
+	if err := qtx.DeleteCollectionRecipes(ctx, collectionID); err != nil {
+		return models.Collection{}, err
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, recipeID := range recipeIDs {
+		if seen[recipeID] {
+			continue
+		}
+		seen[recipeID] = true
+		if err := checkRecipeOwner(ctx, qtx, userID, recipeID); err != nil {
+			return models.Collection{}, err
+		}
+		err = qtx.InsertCollectionRecipe(ctx, database.InsertCollectionRecipeParams{
+			CreatedAt:    time.Now().UTC(),
+			CollectionID: collectionID,
+			RecipeID:     recipeID,
+			Position:     int32(len(seen)),
+		})
+		if err != nil {
+			return models.Collection{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Collection{}, err
+	}
+	return rs.GetCollection(ctx, userID, collectionID)
+}
+
+// AddRecipeToCollection puts a recipe at the end of a manual collection. A
+// recipe already in it stays where it is.
+func (rs RecipeService) AddRecipeToCollection(ctx context.Context, userID, collectionID, recipeID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.AddRecipeToCollection")
+	defer span.End()
+
+	if err := checkManualCollection(ctx, rs.store.Q, userID, collectionID); err != nil {
+		return err
+	}
+	if err := checkRecipeOwner(ctx, rs.store.Q, userID, recipeID); err != nil {
+		return err
+	}
+	return rs.store.Q.AddRecipeToCollection(ctx, database.AddRecipeToCollectionParams{
+		CreatedAt:    time.Now().UTC(),
+		CollectionID: collectionID,
+		RecipeID:     recipeID,
+	})
+}
+
+func (rs RecipeService) RemoveRecipeFromCollection(ctx context.Context, userID, collectionID, recipeID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.RemoveRecipeFromCollection")
+	defer span.End()
+
+	if err := checkManualCollection(ctx, rs.store.Q, userID, collectionID); err != nil {
+		return err
+	}
+	n, err := rs.store.Q.RemoveRecipeFromCollection(ctx, database.RemoveRecipeFromCollectionParams{
+		CollectionID: collectionID,
+		RecipeID:     recipeID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// CollectionCookbook lays out the recipes of a collection as a book in
+// format, titled with the name of the collection, which it also returns.
+func (rs RecipeService) CollectionCookbook(ctx context.Context, userID, collectionID uuid.UUID, format string) (string, []byte, error) {
+	ctx, span := startSpan(ctx, "RecipeService.CollectionCookbook")
+	defer span.End()
+
+	collection, err := rs.GetCollection(ctx, userID, collectionID)
+	if err != nil {
+		return "", nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(collection.Recipes))
+	for _, r := range collection.Recipes {
+		ids = append(ids, r.ID)
+	}
+	recipes, err := rs.getUserRecipes(ctx, userID, ids)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := rs.writeCookbook(ctx, format, collection.Name, recipes)
+	return collection.Name, data, err
+}
+
+func (rs RecipeService) listRecipesByFilter(ctx context.Context, userID uuid.UUID, filter models.CollectionFilter) ([]models.RecipeInList, error) {
+	cuisineIDs := []uuid.UUID{}
+	if len(filter.CuisineIDs) > 0 {
+		cuisines, err := rs.ListCuisines(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cuisineIDs = withSubCuisines(cuisines, filter.CuisineIDs)
+	}
+
+	tags := []string{}
+	for _, t := range filter.Tags {
+		if t = normalizeTagName(t); t != "" {
+			tags = append(tags, strings.ToLower(t))
+		}
+	}
+	ingredientIDs := filter.IngredientIDs
+	if ingredientIDs == nil {
+		ingredientIDs = []uuid.UUID{}
+	}
+
+	rows, err := rs.store.Q.ListRecipesByFilter(ctx, database.ListRecipesByFilterParams{
+		UserID:        userID,
+		MaxCookTime:   int32(filter.MaxCookTime),
+		CuisineIds:    cuisineIDs,
+		Tags:          tags,
+		IngredientIds: ingredientIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	recipes := make([]models.RecipeInList, 0, len(rows))
+	for _, r := range rows {
+		recipes = append(recipes, recipeWithCuisinesResponse(database.ListRecipesWithCuisinesByUserIDRow(r)))
+	}
+	return recipes, nil
+}
+
+// withSubCuisines adds to ids the cuisines under them, so that a filter on
+// "Asian" also finds the "Thai" recipes.
+func withSubCuisines(cuisines []models.Cuisine, ids []uuid.UUID) []uuid.UUID {
+	children := map[uuid.UUID][]uuid.UUID{}
+	for _, c := range cuisines {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	seen := map[uuid.UUID]bool{}
+	res := []uuid.UUID{}
+	queue := append([]uuid.UUID{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		res = append(res, id)
+		queue = append(queue, children[id]...)
+	}
+	return res
+}
+
+func checkManualCollection(ctx context.Context, q *database.Queries, userID, collectionID uuid.UUID) error {
+	collection, err := q.GetCollectionByID(ctx, database.GetCollectionByIDParams{
+		ID:     collectionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return checkErrNoRows(err)
+	}
+	if collection.Filter != nil {
+		return ErrSmartCollection
+	}
+	return nil
+}
+
+func marshalCollectionFilter(filter *models.CollectionFilter) ([]byte, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	return json.Marshal(filter)
+}
+
+func createCollectionResponse(c database.Collection) (models.Collection, error) {
+	collection := models.Collection{
+		ID:          c.ID,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+		UserID:      c.UserID,
+		Name:        c.Name,
+		Description: c.Description,
+	}
+	if c.Filter != nil {
+		var filter models.CollectionFilter
+		if err := json.Unmarshal(c.Filter, &filter); err != nil {
+			return models.Collection{}, err
+		}
+		collection.Smart = true
+		collection.Filter = &filter
+	}
+	return collection, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/cookbook"
+	"github.com/quangd42/meal-org/internal/images"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const DefaultCookbookTitle = "Our Cookbook"
+
+// RecipeCookbook lays out the recipes selected by arg as a book in format,
+// PDF or EPUB. The pictures are those already cached, the book doesn't wait
+// for the others.
+func (rs RecipeService) RecipeCookbook(ctx context.Context, userID uuid.UUID, format string, arg models.CookbookRequest) ([]byte, error) {
+	ctx, span := startSpan(ctx, "RecipeService.RecipeCookbook")
+	defer span.End()
+
+	title := strings.TrimSpace(arg.Title)
+	if title == "" {
+		title = DefaultCookbookTitle
+	}
+
+	if len(arg.RecipeIDs) == 0 {
+		recipes, err := rs.ExportRecipesByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return rs.writeCookbook(ctx, format, title, recipes)
+	}
+	recipes, err := rs.getUserRecipes(ctx, userID, arg.RecipeIDs)
+	if err != nil {
+		return nil, err
+	}
+	return rs.writeCookbook(ctx, format, title, recipes)
+}
+
+// getUserRecipes reads the recipes with the given IDs, in their order and
+// once each. All must belong to the user.
+func (rs RecipeService) getUserRecipes(ctx context.Context, userID uuid.UUID, recipeIDs []uuid.UUID) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	seen := map[uuid.UUID]bool{}
+	for _, id := range recipeIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		recipe, err := rs.GetRecipeByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if recipe.UserID != userID {
+			return nil, ErrResourceNotFound
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes, nil
+}
+
+func (rs RecipeService) writeCookbook(ctx context.Context, format, title string, recipes []models.Recipe) ([]byte, error) {
+	book := cookbook.Book{
+		Title:   title,
+		Recipes: recipes,
+		Images:  map[uuid.UUID][]byte{},
+	}
+
+	cuisines, err := rs.ListCuisines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	book.Cuisines = cuisines
+
+	for _, r := range book.Recipes {
+		img, err := rs.RecipeImage(ctx, r.UserID, r.ID, images.VariantDetail)
+		if err == nil {
+			book.Images[r.ID] = img.Data
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := cookbook.Write(&buf, format, book); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// ListCookEvents returns the cook log of a recipe, most recent first.
+func (rs RecipeService) ListCookEvents(ctx context.Context, userID, recipeID uuid.UUID) ([]models.CookEvent, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListCookEvents")
+	defer span.End()
+
+	if err := checkRecipeOwner(ctx, rs.store.Q, userID, recipeID); err != nil {
+		return nil, err
+	}
+
+	events := []models.CookEvent{}
+	dbEvents, err := rs.store.Q.ListCookEventsByRecipeID(ctx, recipeID)
+	if err != nil {
+		return events, err
+	}
+	for _, e := range dbEvents {
+		events = append(events, createCookEventResponse(e))
+	}
+	return events, nil
+}
+
+func (rs RecipeService) CreateCookEvent(ctx context.Context, userID, recipeID uuid.UUID, arg models.CookEventRequest) (models.CookEvent, error) {
+	ctx, span := startSpan(ctx, "RecipeService.CreateCookEvent")
+	defer span.End()
+
+	if err := checkRecipeOwner(ctx, rs.store.Q, userID, recipeID); err != nil {
+		return models.CookEvent{}, err
+	}
+
+	event, err := rs.store.Q.CreateCookEvent(ctx, database.CreateCookEventParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		RecipeID:  recipeID,
+		UserID:    userID,
+		CookedAt:  cookedAt(arg.CookedAt),
+		CookedBy:  arg.CookedBy,
+		Rating:    ratingParam(arg.Rating),
+		Notes:     arg.Notes,
+		PhotoUrl:  arg.PhotoURL,
+	})
+	if err != nil {
+		return models.CookEvent{}, checkErrDBConstraint(err)
+	}
+	return createCookEventResponse(event), nil
+}
+
+func (rs RecipeService) UpdateCookEvent(ctx context.Context, userID, recipeID, eventID uuid.UUID, arg models.CookEventRequest) (models.CookEvent, error) {
+	ctx, span := startSpan(ctx, "RecipeService.UpdateCookEvent")
+	defer span.End()
+
+	if err := checkRecipeOwner(ctx, rs.store.Q, userID, recipeID); err != nil {
+		return models.CookEvent{}, err
+	}
+
+	event, err := rs.store.Q.UpdateCookEventByID(ctx, database.UpdateCookEventByIDParams{
+		ID:        eventID,
+		RecipeID:  recipeID,
+		CookedAt:  cookedAt(arg.CookedAt),
+		CookedBy:  arg.CookedBy,
+		Rating:    ratingParam(arg.Rating),
+		Notes:     arg.Notes,
+		PhotoUrl:  arg.PhotoURL,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return models.CookEvent{}, customDBErr(err)
+	}
+	return createCookEventResponse(event), nil
+}
+
+func (rs RecipeService) DeleteCookEvent(ctx context.Context, userID, recipeID, eventID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteCookEvent")
+	defer span.End()
+
+	if err := checkRecipeOwner(ctx, rs.store.Q, userID, recipeID); err != nil {
+		return err
+	}
+
+	n, err := rs.store.Q.DeleteCookEventByID(ctx, database.DeleteCookEventByIDParams{
+		ID:       eventID,
+		RecipeID: recipeID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// SetRecipeFavorite marks the recipe as a favorite of the user, or not.
+func (rs RecipeService) SetRecipeFavorite(ctx context.Context, userID, recipeID uuid.UUID, favorite bool) error {
+	ctx, span := startSpan(ctx, "RecipeService.SetRecipeFavorite")
+	defer span.End()
+
+	if err := checkRecipeOwner(ctx, rs.store.Q, userID, recipeID); err != nil {
+		return err
+	}
+
+	if !favorite {
+		return rs.store.Q.DeleteRecipeFavorite(ctx, database.DeleteRecipeFavoriteParams{
+			UserID:   userID,
+			RecipeID: recipeID,
+		})
+	}
+	return rs.store.Q.AddRecipeFavorite(ctx, database.AddRecipeFavoriteParams{
+		CreatedAt: time.Now().UTC(),
+		UserID:    userID,
+		RecipeID:  recipeID,
+	})
+}
+
+func cookedAt(t *time.Time) time.Time {
+	if t == nil || t.IsZero() {
+		return time.Now().UTC()
+	}
+	return t.UTC()
+}
+
+func ratingParam(rating *int) *int16 {
+	if rating == nil {
+		return nil
+	}
+	r := int16(*rating)
+	return &r
+}
+
+func createCookEventResponse(e database.CookEvent) models.CookEvent {
+	event := models.CookEvent{
+		ID:        e.ID,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+		RecipeID:  e.RecipeID,
+		UserID:    e.UserID,
+		CookedAt:  e.CookedAt,
+		CookedBy:  e.CookedBy,
+		Notes:     e.Notes,
+		PhotoURL:  e.PhotoUrl,
+	}
+	if e.Rating != nil {
+		rating := int(*e.Rating)
+		event.Rating = &rating
+	}
+	return event
+}
@@ -2,14 +2,19 @@ package services
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/quangd42/meal-org/internal/database"
 	"github.com/quangd42/meal-org/internal/models"
 )
 
 func (rs RecipeService) CreateCuisine(ctx context.Context, cr models.CuisineRequest) (models.Cuisine, error) {
+	ctx, span := startSpan(ctx, "RecipeService.CreateCuisine")
+	defer span.End()
+
 	var c models.Cuisine
 	if cr.ParentID != nil {
 		_, err := rs.store.Q.GetCuisineByID(ctx, *cr.ParentID)
@@ -33,6 +38,9 @@ func (rs RecipeService) CreateCuisine(ctx context.Context, cr models.CuisineRequ
 }
 
 func (rs RecipeService) UpdateCuisineByID(ctx context.Context, cuisineID uuid.UUID, cr models.CuisineRequest) (models.Cuisine, error) {
+	ctx, span := startSpan(ctx, "RecipeService.UpdateCuisineByID")
+	defer span.End()
+
 	var c models.Cuisine
 	if cr.ParentID != nil {
 		_, err := rs.store.Q.GetCuisineByID(ctx, *cr.ParentID)
@@ -54,7 +62,37 @@ func (rs RecipeService) UpdateCuisineByID(ctx context.Context, cuisineID uuid.UU
 	return createCuisineResponse(cuisine), nil
 }
 
+func (rs RecipeService) GetCuisineByName(ctx context.Context, name string) (models.Cuisine, error) {
+	ctx, span := startSpan(ctx, "RecipeService.GetCuisineByName")
+	defer span.End()
+
+	cuisine, err := rs.store.Q.GetCuisineByName(ctx, name)
+	if err != nil {
+		return models.Cuisine{}, checkErrNoRows(err)
+	}
+	return createCuisineResponse(cuisine), nil
+}
+
+// GetOrCreateCuisineByName finds a cuisine by its exact name, creating it at
+// the top of the tree if there is none yet.
+func (rs RecipeService) GetOrCreateCuisineByName(ctx context.Context, name string) (models.Cuisine, error) {
+	ctx, span := startSpan(ctx, "RecipeService.GetOrCreateCuisineByName")
+	defer span.End()
+
+	cuisine, err := rs.store.Q.GetCuisineByName(ctx, name)
+	if err == nil {
+		return createCuisineResponse(cuisine), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return models.Cuisine{}, err
+	}
+	return rs.CreateCuisine(ctx, models.CuisineRequest{Name: name})
+}
+
 func (rs RecipeService) ListCuisines(ctx context.Context) ([]models.Cuisine, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListCuisines")
+	defer span.End()
+
 	var cs []models.Cuisine
 	cuisines, err := rs.store.Q.ListCuisines(ctx)
 	if err != nil {
@@ -67,6 +105,9 @@ func (rs RecipeService) ListCuisines(ctx context.Context) ([]models.Cuisine, err
 }
 
 func (rs RecipeService) DeleteCuisine(ctx context.Context, cuisineID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteCuisine")
+	defer span.End()
+
 	err := rs.store.Q.DeleteCuisine(ctx, cuisineID)
 	if err != nil {
 		return checkErrDBConstraint(err)
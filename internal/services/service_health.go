@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+var (
+	errShuttingDown      = errors.New("server is shutting down")
+	errMigrationsPending = errors.New("database schema is not up to date")
+)
+
+type HealthService struct {
+	store        *database.Store
+	ms           MigrationService
+	sessions     scs.Store
+	shuttingDown *atomic.Bool
+}
+
+func NewHealthService(store *database.Store, ms MigrationService, sessions scs.Store) HealthService {
+	return HealthService{
+		store:        store,
+		ms:           ms,
+		sessions:     sessions,
+		shuttingDown: &atomic.Bool{},
+	}
+}
+
+// SetShuttingDown makes every following readiness check fail, so that load
+// balancers stop sending traffic while in-flight requests drain.
+func (hs HealthService) SetShuttingDown() {
+	hs.shuttingDown.Store(true)
+}
+
+// Ready runs all checks concurrently and reports each of them.
+func (hs HealthService) Ready(ctx context.Context) models.Readiness {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	checks := map[string]func(context.Context) models.HealthCheck{
+		"server":     hs.checkServer,
+		"database":   hs.checkDatabase,
+		"migrations": hs.checkMigrations,
+		"sessions":   hs.checkSessions,
+	}
+
+	res := models.Readiness{
+		Status: models.HealthStatusOK,
+		Checks: make(map[string]models.HealthCheck, len(checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := check(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			res.Checks[name] = c
+			if c.Status != models.HealthStatusOK {
+				res.Status = models.HealthStatusFail
+			}
+		}()
+	}
+	wg.Wait()
+
+	return res
+}
+
+func (hs HealthService) checkServer(ctx context.Context) models.HealthCheck {
+	if hs.shuttingDown.Load() {
+		return failedCheck(errShuttingDown)
+	}
+	return models.HealthCheck{Status: models.HealthStatusOK}
+}
+
+func (hs HealthService) checkDatabase(ctx context.Context) models.HealthCheck {
+	if err := hs.store.DB.Ping(ctx); err != nil {
+		return failedCheck(err)
+	}
+	stat := hs.store.DB.Stat()
+	return models.HealthCheck{
+		Status: models.HealthStatusOK,
+		Details: map[string]any{
+			"total_conns":    stat.TotalConns(),
+			"idle_conns":     stat.IdleConns(),
+			"acquired_conns": stat.AcquiredConns(),
+		},
+	}
+}
+
+func (hs HealthService) checkMigrations(ctx context.Context) models.HealthCheck {
+	current, latest, err := hs.ms.SchemaVersion(ctx)
+	if err != nil {
+		return failedCheck(err)
+	}
+	c := models.HealthCheck{
+		Status: models.HealthStatusOK,
+		Details: map[string]any{
+			"schema_version":        current,
+			"latest_schema_version": latest,
+		},
+	}
+	if current < latest {
+		c.Status = models.HealthStatusFail
+		c.Error = errMigrationsPending.Error()
+	}
+	return c
+}
+
+// checkSessions looks up a token that never exists, which is enough to know
+// that the session store is reachable and its table is in place.
+func (hs HealthService) checkSessions(ctx context.Context) models.HealthCheck {
+	ch := make(chan error, 1)
+	go func() {
+		_, _, err := hs.sessions.Find("readiness-probe")
+		ch <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return failedCheck(ctx.Err())
+	case err := <-ch:
+		if err != nil {
+			return failedCheck(err)
+		}
+		return models.HealthCheck{Status: models.HealthStatusOK}
+	}
+}
+
+func failedCheck(err error) models.HealthCheck {
+	return models.HealthCheck{
+		Status: models.HealthStatusFail,
+		Error:  err.Error(),
+	}
+}
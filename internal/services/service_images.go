@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/quangd42/meal-org/internal/blob"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/images"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const JobCacheRecipeImage = "cache_recipe_image"
+
+const (
+	recipeImageOK     = "ok"
+	recipeImageFailed = "failed"
+
+	// Cached images are downloaded again after imageMaxAge, in case they
+	// changed or disappeared. Failed downloads are tried again sooner.
+	imageMaxAge       = 30 * 24 * time.Hour
+	imageRetryAfter   = 24 * time.Hour
+	imageRefreshBatch = 100
+)
+
+// ErrImageUnavailable means the image isn't cached yet, or can't be.
+var ErrImageUnavailable = errors.New("image unavailable")
+
+type cacheRecipeImagePayload struct {
+	RecipeID uuid.UUID `json:"recipe_id"`
+}
+
+func recipeImageKey(recipeID uuid.UUID, variant string) string {
+	return "recipes/" + recipeID.String() + "/" + variant + ".jpg"
+}
+
+func enqueueCacheRecipeImage(ctx context.Context, q *database.Queries, recipeID uuid.UUID) error {
+	return enqueueJob(ctx, q, JobCacheRecipeImage, JobCacheRecipeImage+":"+recipeID.String(),
+		&recipeID, cacheRecipeImagePayload{RecipeID: recipeID})
+}
+
+// RecipeImage returns a variant of the recipe's preview image from the
+// cache. When it isn't cached, or is outdated, ErrImageUnavailable is
+// returned and caching is queued if userID owns the recipe. Anyone may get
+// what is cached, userID is uuid.Nil for anonymous requests.
+func (rs RecipeService) RecipeImage(ctx context.Context, userID, recipeID uuid.UUID, variant string) (models.Image, error) {
+	ctx, span := startSpan(ctx, "RecipeService.RecipeImage")
+	defer span.End()
+
+	var img models.Image
+	if !images.IsVariant(variant) {
+		return img, fmt.Errorf("%w: unknown variant %q", ErrImageUnavailable, variant)
+	}
+
+	recipe, err := rs.store.Q.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return img, checkErrNoRows(err)
+	}
+	if recipe.ExternalImageUrl == nil || *recipe.ExternalImageUrl == "" {
+		return img, ErrImageUnavailable
+	}
+
+	cached, err := rs.store.Q.GetRecipeImageByRecipeID(ctx, recipeID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return img, err
+	}
+	// Only the owner may have the image fetched, so that anonymous requests
+	// can't make us hit external sites
+	isOwner := userID != uuid.Nil && recipe.UserID == userID
+
+	if errors.Is(err, pgx.ErrNoRows) || cached.SourceUrl != *recipe.ExternalImageUrl {
+		return img, rs.queueImageCaching(ctx, recipeID, isOwner)
+	}
+	if cached.Status != recipeImageOK {
+		return img, ErrImageUnavailable
+	}
+
+	data, err := rs.blobs.Get(ctx, recipeImageKey(recipeID, variant))
+	if errors.Is(err, blob.ErrNotFound) {
+		return img, rs.queueImageCaching(ctx, recipeID, isOwner)
+	}
+	if err != nil {
+		return img, err
+	}
+
+	sum := sha256.Sum256([]byte(cached.SourceUrl + cached.FetchedAt.String()))
+	return models.Image{
+		ContentType: images.ContentType,
+		Data:        data,
+		ETag:        `"` + variant + "-" + hex.EncodeToString(sum[:8]) + `"`,
+		ModTime:     cached.FetchedAt,
+	}, nil
+}
+
+func (rs RecipeService) queueImageCaching(ctx context.Context, recipeID uuid.UUID, queue bool) error {
+	if !queue {
+		return ErrImageUnavailable
+	}
+	if err := enqueueCacheRecipeImage(ctx, rs.store.Q, recipeID); err != nil {
+		return err
+	}
+	return ErrImageUnavailable
+}
+
+// CacheRecipeImage is the JobHandler that downloads the preview image of a
+// recipe and stores its variants.
+func (rs RecipeService) CacheRecipeImage(ctx context.Context, job database.Job) error {
+	var p cacheRecipeImagePayload
+	if err := json.Unmarshal(job.Payload, &p); err != nil {
+		return permanentJobError{err}
+	}
+
+	recipe, err := rs.store.Q.GetRecipeByID(ctx, p.RecipeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if recipe.ExternalImageUrl == nil || *recipe.ExternalImageUrl == "" {
+		return rs.blobs.Delete(ctx, "recipes/"+recipe.ID.String())
+	}
+	sourceURL := *recipe.ExternalImageUrl
+
+	err = rs.downloadRecipeImage(ctx, recipe.ID, sourceURL)
+	if err != nil && !isPermanentFetchError(err) && !errors.Is(err, images.ErrUnsupported) {
+		// Keep serving what is cached until the retries are exhausted
+		if job.Attempts < job.MaxAttempts {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	status, lastError := recipeImageOK, (*string)(nil)
+	if err != nil {
+		status = recipeImageFailed
+		msg := err.Error()
+		lastError = &msg
+		slog.WarnContext(ctx, "failed to cache recipe image", "recipe_id", recipe.ID, "url", sourceURL, "error", err)
+	}
+	saveErr := rs.store.Q.SaveRecipeImage(ctx, database.SaveRecipeImageParams{
+		RecipeID:  recipe.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		SourceUrl: sourceURL,
+		Status:    status,
+		LastError: lastError,
+		FetchedAt: now,
+	})
+	if saveErr != nil {
+		return saveErr
+	}
+	if err != nil {
+		return permanentJobError{err}
+	}
+	return nil
+}
+
+func (rs RecipeService) downloadRecipeImage(ctx context.Context, recipeID uuid.UUID, sourceURL string) error {
+	resp, err := rs.fetcher.Get(ctx, sourceURL)
+	if err != nil {
+		return err
+	}
+
+	variants, err := images.Variants(resp.Body)
+	if err != nil {
+		return err
+	}
+	for name, data := range variants {
+		if err := rs.blobs.Put(ctx, recipeImageKey(recipeID, name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshStaleImages queues the caching of images that are old, or that
+// failed a while ago, so that changed or dead images are noticed.
+func (rs RecipeService) RefreshStaleImages(ctx context.Context) error {
+	now := time.Now().UTC()
+	recipeIDs, err := rs.store.Q.ListStaleRecipeImages(ctx, database.ListStaleRecipeImagesParams{
+		FetchedAt:   now.Add(-imageMaxAge),
+		FetchedAt_2: now.Add(-imageRetryAfter),
+		Limit:       imageRefreshBatch,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range recipeIDs {
+		if err := enqueueCacheRecipeImage(ctx, rs.store.Q, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/importer"
+	"github.com/quangd42/meal-org/internal/metrics"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/models/validator"
+)
+
+// fallbackCuisine files the imported recipes that have no category, as
+// recipes need at least one cuisine.
+const fallbackCuisine = "Uncategorized"
+
+var ErrInvalidImport = errors.New("invalid import")
+
+// ImportRecipes creates the recipes of an upload exported by another recipe
+// manager. The format is detected when empty. Recipes imported before from
+// the same source are skipped, so that an upload can be sent again after
+// fixing what failed.
+func (rs RecipeService) ImportRecipes(ctx context.Context, userID uuid.UUID, filename, format string, data []byte) (models.ImportReport, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ImportRecipes")
+	defer span.End()
+
+	report := models.ImportReport{Results: []models.ImportResult{}}
+
+	var err error
+	if format == "" {
+		format, err = importer.Detect(filename, data)
+		if err != nil {
+			return report, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+		}
+	}
+	report.Format = format
+
+	err = importer.Read(format, filename, data, func(e importer.Entry) {
+		res := rs.importRecipe(ctx, userID, format, e)
+		switch res.Status {
+		case models.ImportStatusImported:
+			report.Imported++
+		case models.ImportStatusSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		report.Results = append(report.Results, res)
+	})
+	if err != nil {
+		return report, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+
+	return report, nil
+}
+
+func (rs RecipeService) importRecipe(ctx context.Context, userID uuid.UUID, format string, e importer.Entry) models.ImportResult {
+	res := models.ImportResult{
+		File:     e.File,
+		SourceID: e.Recipe.SourceID,
+		Name:     e.Recipe.Name,
+		Status:   models.ImportStatusFailed,
+		Warnings: e.Recipe.Warnings,
+	}
+	if e.Err != nil {
+		res.Error = e.Err.Error()
+		return res
+	}
+
+	sourceID := e.Recipe.SourceID
+	if sourceID == "" {
+		sourceID = e.File + "|" + e.Recipe.Name
+	}
+
+	existing, err := rs.store.Q.GetRecipeImport(ctx, database.GetRecipeImportParams{
+		UserID:   userID,
+		Source:   format,
+		SourceID: sourceID,
+	})
+	if err == nil {
+		res.Status = models.ImportStatusSkipped
+		res.RecipeID = &existing.RecipeID
+		res.Warnings = append(res.Warnings, "already imported")
+		return res
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		res.Error = err.Error()
+		return res
+	}
+
+	rr, warnings, err := rs.importedRecipeRequest(ctx, e.Recipe)
+	res.Warnings = append(res.Warnings, warnings...)
+	if err == nil {
+		err = rr.Validate(ctx)
+	}
+	if err != nil {
+		res.Error = importErrorMessage(err)
+		return res
+	}
+
+	tx, err := rs.store.DB.Begin(ctx)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer tx.Rollback(ctx)
+	qtx := rs.store.Q.WithTx(tx)
+
+	recipe, err := createRecipe(ctx, qtx, userID, rr)
+	if err == nil {
+		err = qtx.CreateRecipeImport(ctx, database.CreateRecipeImportParams{
+			UserID:    userID,
+			Source:    format,
+			SourceID:  sourceID,
+			RecipeID:  recipe.ID,
+			CreatedAt: time.Now().UTC(),
+		})
+	}
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		res.Error = importErrorMessage(checkErrDBConstraint(err))
+		return res
+	}
+	metrics.RecipesCreated.Inc()
+
+	res.Status = models.ImportStatusImported
+	res.RecipeID = &recipe.ID
+	return res
+}
+
+// importedRecipeRequest matches the ingredients and cuisines of an imported
+// recipe, creating those we don't have yet. What the recipe lacks is filled
+// in and reported as warnings.
+func (rs RecipeService) importedRecipeRequest(ctx context.Context, r importer.Recipe) (models.RecipeRequest, []string, error) {
+	var warnings []string
+	rr := models.RecipeRequest{
+		Name:              strings.TrimSpace(r.Name),
+		ExternalURL:       nilIfEmpty(r.URL),
+		Description:       nilIfEmpty(r.Description),
+		Servings:          r.Servings,
+		Yield:             nilIfEmpty(r.Yield),
+		CookTimeInMinutes: r.CookTimeInMinutes,
+		Notes:             nilIfEmpty(r.Notes),
+	}
+	if rr.Servings <= 0 {
+		rr.Servings = 1
+		warnings = append(warnings, "servings not found, set to 1")
+	}
+	if rr.CookTimeInMinutes <= 0 {
+		rr.CookTimeInMinutes = 1
+		warnings = append(warnings, "cook time not found, set to 1 minute")
+	}
+
+	seen := map[string]bool{}
+	for _, name := range r.Cuisines {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		cuisine, err := rs.GetOrCreateCuisineByName(ctx, name)
+		if err != nil {
+			return rr, warnings, fmt.Errorf("cuisine %q: %w", name, err)
+		}
+		rr.Cuisines = append(rr.Cuisines, cuisine.ID)
+	}
+	if len(rr.Cuisines) == 0 {
+		cuisine, err := rs.GetOrCreateCuisineByName(ctx, fallbackCuisine)
+		if err != nil {
+			return rr, warnings, fmt.Errorf("cuisine %q: %w", fallbackCuisine, err)
+		}
+		rr.Cuisines = append(rr.Cuisines, cuisine.ID)
+		warnings = append(warnings, fmt.Sprintf("no cuisine found, filed under %s", fallbackCuisine))
+	}
+
+	for _, i := range r.Ingredients {
+		name := strings.TrimSpace(i.Name)
+		if name == "" {
+			continue
+		}
+		ingredient, err := rs.GetOrCreateIngredientByName(ctx, name)
+		if err != nil {
+			return rr, warnings, fmt.Errorf("ingredient %q: %w", name, err)
+		}
+		rr.Ingredients = append(rr.Ingredients, models.IngredientInRecipe{
+			ID:       ingredient.ID,
+			Amount:   strings.TrimSpace(i.Amount),
+			PrepNote: nilIfEmpty(strings.TrimSpace(i.PrepNote)),
+			Name:     ingredient.Name,
+			Index:    len(rr.Ingredients) + 1,
+		})
+	}
+
+	for _, step := range r.Instructions {
+		rr.Instructions = append(rr.Instructions, models.InstructionInRecipe{
+			StepNo:      len(rr.Instructions) + 1,
+			Instruction: step,
+		})
+	}
+
+	return rr, warnings, nil
+}
+
+// importErrorMessage tells which fields are missing rather than how they
+// failed to validate.
+func importErrorMessage(err error) string {
+	var valErrs validator.ValidationErrors
+	if !errors.As(err, &valErrs) {
+		return err.Error()
+	}
+	fields := make([]string, 0, len(valErrs))
+	for f := range valErrs {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return "missing " + strings.Join(fields, ", ")
+}
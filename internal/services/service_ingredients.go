@@ -2,14 +2,19 @@ package services
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/quangd42/meal-org/internal/database"
 	"github.com/quangd42/meal-org/internal/models"
 )
 
 func (rs RecipeService) CreateIngredient(ctx context.Context, arg models.IngredientRequest) (models.Ingredient, error) {
+	ctx, span := startSpan(ctx, "RecipeService.CreateIngredient")
+	defer span.End()
+
 	var ing models.Ingredient
 
 	ingredient, err := rs.store.Q.CreateIngredient(ctx, database.CreateIngredientParams{
@@ -27,6 +32,9 @@ func (rs RecipeService) CreateIngredient(ctx context.Context, arg models.Ingredi
 }
 
 func (rs RecipeService) UpdateIngredientByID(ctx context.Context, ingredientID uuid.UUID, arg models.IngredientRequest) (models.Ingredient, error) {
+	ctx, span := startSpan(ctx, "RecipeService.UpdateIngredientByID")
+	defer span.End()
+
 	var ing models.Ingredient
 
 	ingredient, err := rs.store.Q.UpdateIngredientByID(ctx, database.UpdateIngredientByIDParams{
@@ -42,7 +50,26 @@ func (rs RecipeService) UpdateIngredientByID(ctx context.Context, ingredientID u
 	return ing, nil
 }
 
+// GetOrCreateIngredientByName finds an ingredient by its exact name, creating
+// it if there is none yet.
+func (rs RecipeService) GetOrCreateIngredientByName(ctx context.Context, name string) (models.Ingredient, error) {
+	ctx, span := startSpan(ctx, "RecipeService.GetOrCreateIngredientByName")
+	defer span.End()
+
+	ingredient, err := rs.store.Q.GetIngredientByName(ctx, name)
+	if err == nil {
+		return createIngredientResponse(ingredient), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return models.Ingredient{}, err
+	}
+	return rs.CreateIngredient(ctx, models.IngredientRequest{Name: name})
+}
+
 func (rs RecipeService) ListIngredients(ctx context.Context) ([]models.Ingredient, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListIngredients")
+	defer span.End()
+
 	var ings []models.Ingredient
 	ingredients, err := rs.store.Q.ListIngredients(ctx)
 	if err != nil {
@@ -56,6 +83,9 @@ func (rs RecipeService) ListIngredients(ctx context.Context) ([]models.Ingredien
 }
 
 func (rs RecipeService) DeleteIngredient(ctx context.Context, ingredientID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteIngredient")
+	defer span.End()
+
 	err := rs.store.Q.DeleteIngredient(ctx, ingredientID)
 	if err != nil {
 		return checkErrDBConstraint(err)
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/logging"
+	"github.com/quangd42/meal-org/internal/metrics"
+	"github.com/quangd42/meal-org/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const JobFetchExternalImage = "fetch_external_image"
+
+// DefaultJobRetention is how long finished jobs are kept, to look into what
+// happened to them, before they are deleted.
+const DefaultJobRetention = 7 * 24 * time.Hour
+
+const (
+	jobMaxAttempts  = 5
+	jobTimeout      = 30 * time.Second
+	jobPollInterval = time.Second
+	// A running job whose lock is older than this is assumed to have lost
+	// its worker, for example to a restart.
+	jobStaleAfter  = 2 * jobTimeout
+	jobBackoffBase = 10 * time.Second
+	jobBackoffMax  = time.Hour
+	requestIDKey   = "request_id"
+)
+
+// JobHandler runs a job. Returning an error retries the job, unless it is
+// wrapped with permanentJobError.
+type JobHandler func(ctx context.Context, job database.Job) error
+
+type permanentJobError struct {
+	err error
+}
+
+func (e permanentJobError) Error() string { return e.err.Error() }
+func (e permanentJobError) Unwrap() error { return e.err }
+
+// JobService runs the jobs queued in the jobs table. Workers claim jobs with
+// SELECT ... FOR UPDATE SKIP LOCKED, so any number of them can run across
+// processes.
+type JobService struct {
+	store     *database.Store
+	handlers  map[string]JobHandler
+	schedules map[string]schedule
+	wg        *sync.WaitGroup
+}
+
+type schedule struct {
+	every time.Duration
+	fn    func(ctx context.Context) error
+}
+
+func NewJobService(store *database.Store) JobService {
+	return JobService{
+		store:     store,
+		handlers:  map[string]JobHandler{},
+		schedules: map[string]schedule{},
+		wg:        &sync.WaitGroup{},
+	}
+}
+
+// Handle registers the handler of a kind of job. It must be called before
+// Start.
+func (js JobService) Handle(kind string, h JobHandler) {
+	js.handlers[kind] = h
+}
+
+// Schedule runs fn every interval while the service is started, typically to
+// queue periodic jobs. It must be called before Start.
+func (js JobService) Schedule(name string, every time.Duration, fn func(ctx context.Context) error) {
+	js.schedules[name] = schedule{every: every, fn: fn}
+}
+
+// Start launches the workers, the schedules and the reaper of stale jobs. They stop picking
+// up new jobs when ctx is done, use Wait to let the running ones finish.
+func (js JobService) Start(ctx context.Context, workers int) {
+	for range workers {
+		js.wg.Add(1)
+		go func() {
+			defer js.wg.Done()
+			js.work(ctx)
+		}()
+	}
+
+	for name, s := range js.schedules {
+		js.wg.Add(1)
+		go func() {
+			defer js.wg.Done()
+			js.runSchedule(ctx, name, s)
+		}()
+	}
+
+	js.wg.Add(1)
+	go func() {
+		defer js.wg.Done()
+		js.reap(ctx)
+	}()
+}
+
+func (js JobService) runSchedule(ctx context.Context, name string, s schedule) {
+	ticker := time.NewTicker(s.every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.fn(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "scheduled task failed", "schedule", name, "error", err)
+		}
+	}
+}
+
+// Wait blocks until the workers are stopped, or until ctx expires.
+func (js JobService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		js.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (js JobService) work(ctx context.Context) {
+	for {
+		now := time.Now().UTC()
+		job, err := js.store.Q.ClaimJob(ctx, &now)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to claim job", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(jobPollInterval):
+			}
+			continue
+		}
+
+		// A job that has started is allowed to finish during shutdown
+		js.run(context.WithoutCancel(ctx), job)
+	}
+}
+
+func (js JobService) run(ctx context.Context, job database.Job) {
+	ctx, span := startJobSpan(ctx, job)
+	defer span.End()
+
+	var err error
+	h, ok := js.handlers[job.Kind]
+	if !ok {
+		err = permanentJobError{fmt.Errorf("no handler for job kind %q", job.Kind)}
+	} else {
+		hctx, cancel := context.WithTimeout(ctx, jobTimeout)
+		err = h(hctx, job)
+		cancel()
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case err == nil:
+		metrics.JobsProcessed.WithLabelValues(job.Kind, metrics.JobSucceeded).Inc()
+		var n int64
+		n, err = js.store.Q.CompleteJob(ctx, database.CompleteJobParams{
+			ID:        job.ID,
+			UpdatedAt: now,
+		})
+		err = jobUpdated(ctx, job, n, err)
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.WarnContext(ctx, "job failed", "job_id", job.ID, "kind", job.Kind, "attempt", job.Attempts, "error", err)
+		err = js.fail(ctx, job, err, now)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update job", "job_id", job.ID, "error", err)
+	}
+}
+
+// fail schedules a retry of the job with an exponential backoff, or moves it
+// to the dead state once it is out of attempts.
+func (js JobService) fail(ctx context.Context, job database.Job, jobErr error, now time.Time) error {
+	msg := jobErr.Error()
+	var permanent permanentJobError
+	if errors.As(jobErr, &permanent) || job.Attempts >= job.MaxAttempts {
+		metrics.JobsProcessed.WithLabelValues(job.Kind, metrics.JobDead).Inc()
+		n, err := js.store.Q.KillJob(ctx, database.KillJobParams{
+			ID:        job.ID,
+			UpdatedAt: now,
+			LastError: &msg,
+		})
+		return jobUpdated(ctx, job, n, err)
+	}
+
+	metrics.JobsProcessed.WithLabelValues(job.Kind, metrics.JobRetried).Inc()
+	return js.store.Q.RetryJob(ctx, database.RetryJobParams{
+		ID:        job.ID,
+		UpdatedAt: now,
+		LastError: &msg,
+		RunAt:     now.Add(jobBackoff(int(job.Attempts))),
+	})
+}
+
+// jobUpdated logs when the job was no longer running by the time its worker
+// was done with it, such as when the reaper took it back after its lock went
+// stale. The job is left as the other party put it.
+func jobUpdated(ctx context.Context, job database.Job, n int64, err error) error {
+	if err == nil && n == 0 {
+		slog.WarnContext(ctx, "job was no longer running", "job_id", job.ID, "kind", job.Kind)
+	}
+	return err
+}
+
+func jobBackoff(attempts int) time.Duration {
+	d := jobBackoffBase
+	for i := 1; i < attempts && d < jobBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, jobBackoffMax)
+}
+
+// reap puts the jobs of lost workers back in the queue.
+func (js JobService) reap(ctx context.Context) {
+	ticker := time.NewTicker(jobStaleAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now().UTC()
+		staleBefore := now.Add(-jobStaleAfter)
+		jobs, err := js.store.Q.ListStaleJobs(ctx, &staleBefore)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to list stale jobs", "error", err)
+		}
+		for _, job := range jobs {
+			slog.WarnContext(ctx, "requeueing stale job", "job_id", job.ID, "kind", job.Kind)
+			if err := js.fail(ctx, job, errors.New("worker lost"), now); err != nil {
+				slog.ErrorContext(ctx, "failed to requeue stale job", "job_id", job.ID, "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeleteFinishedJobs removes the jobs that succeeded, were cancelled or gave
+// up longer than retention ago.
+func (js JobService) DeleteFinishedJobs(ctx context.Context, retention time.Duration) error {
+	n, err := js.store.Q.DeleteFinishedJobs(ctx, time.Now().UTC().Add(-retention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "deleted finished jobs", "count", n)
+	}
+	return nil
+}
+
+// enqueueJob adds a job to the queue with q, so that it can be part of the
+// transaction of the change that needs it. A job that is already pending with
+// the same unique key is not queued twice.
+func enqueueJob(ctx context.Context, q *database.Queries, kind, uniqueKey string, recipeID *uuid.UUID, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// Carry the trace and the request ID of the caller over to the worker
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if requestID := logging.RequestIDFromContext(ctx); requestID != "" {
+		carrier[requestIDKey] = requestID
+	}
+	traceContext, err := json.Marshal(carrier)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	return q.EnqueueJob(ctx, database.EnqueueJobParams{
+		ID:           uuid.New(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Kind:         kind,
+		UniqueKey:    uniqueKey,
+		Payload:      data,
+		TraceContext: traceContext,
+		MaxAttempts:  jobMaxAttempts,
+		RunAt:        now,
+		RecipeID:     recipeID,
+	})
+}
+
+// startJobSpan starts a new trace for the job, linked to the one that queued
+// it.
+func startJobSpan(ctx context.Context, job database.Job) (context.Context, trace.Span) {
+	carrier := propagation.MapCarrier{}
+	if err := json.Unmarshal(job.TraceContext, &carrier); err != nil {
+		slog.WarnContext(ctx, "invalid job trace context", "job_id", job.ID, "error", err)
+	}
+	if requestID := carrier[requestIDKey]; requestID != "" {
+		ctx = logging.ContextWithRequestID(ctx, requestID)
+	}
+	parent := otel.GetTextMapPropagator().Extract(ctx, carrier)
+
+	return startSpan(ctx, "job "+job.Kind,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(trace.LinkFromContext(parent)),
+		trace.WithAttributes(
+			attribute.String("job.id", job.ID.String()),
+			attribute.String("job.kind", job.Kind),
+			attribute.Int("job.attempt", int(job.Attempts)),
+		),
+	)
+}
+
+// ListRecipeJobs returns the latest jobs of the recipe, so that clients can
+// tell whether work such as fetching its preview image is still underway.
+func (rs RecipeService) ListRecipeJobs(ctx context.Context, userID, recipeID uuid.UUID) ([]models.Job, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListRecipeJobs")
+	defer span.End()
+
+	recipe, err := rs.store.Q.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return nil, checkErrNoRows(err)
+	}
+	if recipe.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	dbJobs, err := rs.store.Q.ListJobsByRecipeID(ctx, database.ListJobsByRecipeIDParams{
+		RecipeID: &recipeID,
+		Limit:    10,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := []models.Job{}
+	for _, j := range dbJobs {
+		jobs = append(jobs, models.Job{
+			ID:          j.ID,
+			CreatedAt:   j.CreatedAt,
+			UpdatedAt:   j.UpdatedAt,
+			Kind:        j.Kind,
+			Status:      j.Status,
+			Attempts:    int(j.Attempts),
+			MaxAttempts: int(j.MaxAttempts),
+			LastError:   j.LastError,
+			RunAt:       j.RunAt,
+		})
+	}
+	return jobs, nil
+}
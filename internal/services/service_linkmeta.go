@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/quangd42/meal-org/internal/linkmeta"
+	"github.com/quangd42/meal-org/internal/models"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FetchLinkMetadata reads the details of the page at pageURL, to prefill a
+// recipe. The page is fetched now, whatever the recipe will store.
+func (rs RecipeService) FetchLinkMetadata(ctx context.Context, pageURL string) (models.LinkMetadata, error) {
+	ctx, span := startSpan(ctx, "RecipeService.FetchLinkMetadata")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ogImageFetchTimeout)
+	defer cancel()
+
+	meta, err := rs.fetchLinkMetadata(ctx, pageURL)
+	if err != nil {
+		return models.LinkMetadata{}, err
+	}
+	return models.LinkMetadata{
+		URL:         normalizeURL(pageURL),
+		Name:        meta.Title,
+		Description: meta.Description,
+		SiteName:    meta.SiteName,
+		Author:      meta.Author,
+		ImageURL:    meta.ImageURL,
+		VideoURL:    meta.VideoURL,
+	}, nil
+}
+
+// normalizeURL adds the scheme that users often leave out.
+func normalizeURL(pageURL string) string {
+	pageURL = strings.TrimSpace(pageURL)
+	if !strings.Contains(pageURL, "://") {
+		pageURL = "https://" + pageURL
+	}
+	return pageURL
+}
+
+// fetchLinkMetadata reads the metadata of the page, completed by oEmbed. Known
+// providers are recognized from the URL alone, so that their details are
+// still known when the page can't be read, as Instagram's often can't.
+func (rs RecipeService) fetchLinkMetadata(ctx context.Context, pageURL string) (linkmeta.Metadata, error) {
+	ctx, span := startSpan(ctx, "RecipeService.fetchLinkMetadata", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	pageURL = normalizeURL(pageURL)
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return linkmeta.Metadata{}, fmt.Errorf("invalid URL %q: %w", pageURL, err)
+	}
+	provider, known := linkmeta.Recognize(u)
+
+	var meta linkmeta.Metadata
+	resp, err := rs.fetcher.Get(ctx, pageURL)
+	if err == nil {
+		var text io.Reader
+		text, err = resp.Text()
+		if err == nil {
+			meta, err = linkmeta.Parse(text, resp.URL)
+		}
+	}
+	if err != nil {
+		if !known || ctx.Err() != nil {
+			return linkmeta.Metadata{}, fmt.Errorf("cannot fetch %s: %w", pageURL, err)
+		}
+		slog.DebugContext(ctx, "cannot read page of known provider", "url", pageURL, "error", err)
+	}
+
+	if known {
+		if meta.SiteName == "" {
+			meta.SiteName = provider.Name
+		}
+		if provider.EmbedURL != "" {
+			meta.VideoURL = provider.EmbedURL
+		}
+		if provider.OEmbedURL != "" {
+			meta.OEmbedURL = provider.OEmbedURL
+		}
+	}
+
+	if meta.OEmbedURL != "" {
+		// oEmbed only completes the page, it is fine to go without
+		if resp, err := rs.fetcher.Get(ctx, meta.OEmbedURL); err != nil {
+			slog.DebugContext(ctx, "cannot fetch oEmbed", "url", meta.OEmbedURL, "error", err)
+		} else if o, err := linkmeta.ParseOEmbed(resp.Body); err != nil {
+			slog.DebugContext(ctx, "invalid oEmbed", "url", meta.OEmbedURL, "error", err)
+		} else {
+			meta.MergeOEmbed(o)
+		}
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/planner"
+)
+
+const (
+	defaultFavoriteWeight = 2
+	defaultOverlapWeight  = 1
+)
+
+// SuggestMealPlan drafts a plan of dinners from the recipes of the user.
+// The draft is not saved, the user accepts it by creating a plan from it.
+// Without a seed a new one is drawn, it is sent back with the draft so the
+// same plan can be asked for again.
+func (rs RecipeService) SuggestMealPlan(ctx context.Context, userID uuid.UUID, arg models.MealPlanSuggestRequest) (models.MealPlanDraft, error) {
+	ctx, span := startSpan(ctx, "RecipeService.SuggestMealPlan")
+	defer span.End()
+
+	start := models.NewDate(time.Now().UTC())
+	if arg.StartDate != nil {
+		start = *arg.StartDate
+	}
+	c := planner.Constraints{
+		Start:                start.Time,
+		Days:                 arg.Days,
+		MaxWeeknightCookTime: arg.MaxWeeknightCookTime,
+		MaxPerCuisine:        arg.MaxPerCuisine,
+		NoRepeatDays:         arg.NoRepeatDays,
+		FavoriteWeight:       defaultFavoriteWeight,
+		OverlapWeight:        defaultOverlapWeight,
+	}
+	if c.Days == 0 {
+		c.Days = models.DefaultMealPlanDays
+	}
+	if arg.FavoriteWeight != nil {
+		c.FavoriteWeight = *arg.FavoriteWeight
+	}
+	if arg.OverlapWeight != nil {
+		c.OverlapWeight = *arg.OverlapWeight
+	}
+	seed := time.Now().UnixNano()
+	if arg.Seed != nil {
+		seed = *arg.Seed
+	}
+
+	rows, err := rs.store.Q.ListPlanCandidates(ctx, userID)
+	if err != nil {
+		return models.MealPlanDraft{}, err
+	}
+	recipes := make([]planner.Recipe, 0, len(rows))
+	for _, r := range rows {
+		recipe := planner.Recipe{
+			ID:            r.ID,
+			Name:          r.Name,
+			CookTime:      int(r.CookTimeInMinutes),
+			CuisineIDs:    r.CuisineIds,
+			IngredientIDs: r.IngredientIds,
+			Favorite:      r.Favorite,
+		}
+		if r.AverageRating != nil {
+			recipe.Rating = *r.AverageRating
+		}
+		if r.LastCookedAt != nil {
+			recipe.LastCooked = *r.LastCookedAt
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	plan, err := rs.planner.Suggest(recipes, c, seed)
+	if err != nil {
+		return models.MealPlanDraft{}, err
+	}
+
+	draft := models.MealPlanDraft{
+		StartDate: start,
+		Meals:     []models.PlannedMeal{},
+		Warnings:  []string{},
+		Seed:      plan.Seed,
+	}
+	for _, m := range plan.Meals {
+		draft.Meals = append(draft.Meals, models.PlannedMeal{
+			Date:       models.NewDate(m.Date),
+			RecipeID:   m.RecipeID,
+			RecipeName: m.Name,
+		})
+	}
+	draft.Warnings = append(draft.Warnings, plan.Warnings...)
+	return draft, nil
+}
+
+func (rs RecipeService) CreateMealPlan(ctx context.Context, userID uuid.UUID, arg models.MealPlanRequest) (models.MealPlan, error) {
+	ctx, span := startSpan(ctx, "RecipeService.CreateMealPlan")
+	defer span.End()
+
+	tx, err := rs.store.DB.Begin(ctx)
+	if err != nil {
+		return models.MealPlan{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := rs.store.Q.WithTx(tx)
+
+	plan, err := qtx.CreateMealPlan(ctx, database.CreateMealPlanParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    userID,
+		Name:      mealPlanName(arg),
+		StartDate: arg.StartDate.Time,
+	})
+	if err != nil {
+		return models.MealPlan{}, checkErrDBConstraint(err)
+	}
+	if err := createPlannedMeals(ctx, qtx, userID, plan.ID, arg.Meals); err != nil {
+		return models.MealPlan{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.MealPlan{}, err
+	}
+	return rs.GetMealPlan(ctx, userID, plan.ID)
+}
+
+// ListMealPlans returns the plans of the user, the latest first, without
+// their meals.
+func (rs RecipeService) ListMealPlans(ctx context.Context, userID uuid.UUID) ([]models.MealPlan, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListMealPlans")
+	defer span.End()
+
+	plans := []models.MealPlan{}
+	dbPlans, err := rs.store.Q.ListMealPlansByUserID(ctx, userID)
+	if err != nil {
+		return plans, err
+	}
+	for _, p := range dbPlans {
+		plans = append(plans, createMealPlanResponse(p))
+	}
+	return plans, nil
+}
+
+func (rs RecipeService) GetMealPlan(ctx context.Context, userID, planID uuid.UUID) (models.MealPlan, error) {
+	ctx, span := startSpan(ctx, "RecipeService.GetMealPlan")
+	defer span.End()
+
+	dbPlan, err := rs.store.Q.GetMealPlanByID(ctx, database.GetMealPlanByIDParams{
+		ID:     planID,
+		UserID: userID,
+	})
+	if err != nil {
+		return models.MealPlan{}, checkErrNoRows(err)
+	}
+	plan := createMealPlanResponse(dbPlan)
+
+	meals, err := rs.store.Q.ListPlannedMealsByPlanID(ctx, planID)
+	if err != nil {
+		return models.MealPlan{}, err
+	}
+	plan.Meals = []models.PlannedMeal{}
+	for _, m := range meals {
+		plan.Meals = append(plan.Meals, models.PlannedMeal{
+			ID:         &m.ID,
+			Date:       models.NewDate(m.Day),
+			Time:       m.ServeTime,
+			RecipeID:   m.RecipeID,
+			RecipeName: m.RecipeName,
+		})
+	}
+	return plan, nil
+}
+
+// UpdateMealPlan renames and moves a plan, and replaces its meals with
+// those of arg.
+func (rs RecipeService) UpdateMealPlan(ctx context.Context, userID, planID uuid.UUID, arg models.MealPlanRequest) (models.MealPlan, error) {
+	ctx, span := startSpan(ctx, "RecipeService.UpdateMealPlan")
+	defer span.End()
+
+	tx, err := rs.store.DB.Begin(ctx)
+	if err != nil {
+		return models.MealPlan{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := rs.store.Q.WithTx(tx)
+
+	_, err = qtx.UpdateMealPlanByID(ctx, database.UpdateMealPlanByIDParams{
+		ID:        planID,
+		UserID:    userID,
+		Name:      mealPlanName(arg),
+		StartDate: arg.StartDate.Time,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return models.MealPlan{}, customDBErr(err)
+	}
+	if err := qtx.DeletePlannedMealsByPlanID(ctx, planID); err != nil {
+		return models.MealPlan{}, err
+	}
+	if err := createPlannedMeals(ctx, qtx, userID, planID, arg.Meals); err != nil {
+		return models.MealPlan{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.MealPlan{}, err
+	}
+	return rs.GetMealPlan(ctx, userID, planID)
+}
+
+func (rs RecipeService) DeleteMealPlan(ctx context.Context, userID, planID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteMealPlan")
+	defer span.End()
+
+	n, err := rs.store.Q.DeleteMealPlanByID(ctx, database.DeleteMealPlanByIDParams{
+		ID:     planID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// createPlannedMeals saves the meals of a plan. Their recipes must belong
+// to the user.
+func createPlannedMeals(ctx context.Context, q *database.Queries, userID, planID uuid.UUID, meals []models.PlannedMealRequest) error {
+	for _, m := range meals {
+		if err := checkRecipeOwner(ctx, q, userID, m.RecipeID); err != nil {
+			return err
+		}
+		err := q.CreatePlannedMeal(ctx, database.CreatePlannedMealParams{
+			ID:         uuid.New(),
+			CreatedAt:  time.Now().UTC(),
+			MealPlanID: planID,
+			RecipeID:   m.RecipeID,
+			Day:        m.Date.Time,
+			ServeTime:  m.Time,
+		})
+		if err != nil {
+			return checkErrDBConstraint(err)
+		}
+	}
+	return nil
+}
+
+// mealPlanName defaults the name of a plan to the week it starts.
+func mealPlanName(arg models.MealPlanRequest) string {
+	name := strings.TrimSpace(arg.Name)
+	if name == "" {
+		name = "Week of " + arg.StartDate.Format("Jan 2")
+	}
+	return name
+}
+
+func createMealPlanResponse(p database.MealPlan) models.MealPlan {
+	return models.MealPlan{
+		ID:        p.ID,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+		UserID:    p.UserID,
+		Name:      p.Name,
+		StartDate: models.NewDate(p.StartDate),
+	}
+}
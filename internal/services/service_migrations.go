@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/sql/schema"
+)
+
+type MigrationService struct {
+	provider *goose.Provider
+}
+
+// NewMigrationService uses the migrations embedded in the binary. Up and Down
+// hold a Postgres advisory lock while running, so replicas starting at the
+// same time apply each migration only once.
+func NewMigrationService(store *database.Store) (MigrationService, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return MigrationService{}, err
+	}
+
+	db := stdlib.OpenDBFromPool(store.DB)
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, schema.FS, goose.WithSessionLocker(locker))
+	if err != nil {
+		return MigrationService{}, fmt.Errorf("cannot load migrations: %w", err)
+	}
+
+	return MigrationService{provider: provider}, nil
+}
+
+func (ms MigrationService) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return ms.provider.Up(ctx)
+}
+
+func (ms MigrationService) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return ms.provider.Down(ctx)
+}
+
+func (ms MigrationService) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return ms.provider.Status(ctx)
+}
+
+// SchemaVersion returns the version the database is at and the latest version
+// known to this binary. It does not wait for the migration lock.
+func (ms MigrationService) SchemaVersion(ctx context.Context) (current, latest int64, err error) {
+	return ms.provider.GetVersions(ctx)
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// ListPantryItems returns what the user has in stock, what expires first
+// first.
+func (rs RecipeService) ListPantryItems(ctx context.Context, userID uuid.UUID) ([]models.PantryItem, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListPantryItems")
+	defer span.End()
+
+	items := []models.PantryItem{}
+	rows, err := rs.store.Q.ListPantryItemsByUserID(ctx, userID)
+	if err != nil {
+		return items, err
+	}
+	for _, r := range rows {
+		items = append(items, createPantryItemResponse(database.GetPantryItemByIDRow(r)))
+	}
+	return items, nil
+}
+
+func (rs RecipeService) CreatePantryItem(ctx context.Context, userID uuid.UUID, arg models.PantryItemRequest) (models.PantryItem, error) {
+	ctx, span := startSpan(ctx, "RecipeService.CreatePantryItem")
+	defer span.End()
+
+	item, err := createPantryItem(ctx, rs.store.Q, userID, arg)
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+	return rs.getPantryItem(ctx, userID, item.ID)
+}
+
+func (rs RecipeService) UpdatePantryItem(ctx context.Context, userID, itemID uuid.UUID, arg models.PantryItemRequest) (models.PantryItem, error) {
+	ctx, span := startSpan(ctx, "RecipeService.UpdatePantryItem")
+	defer span.End()
+
+	_, err := rs.store.Q.UpdatePantryItemByID(ctx, database.UpdatePantryItemByIDParams{
+		ID:           itemID,
+		UserID:       userID,
+		IngredientID: arg.IngredientID,
+		Quantity:     arg.Quantity,
+		Unit:         arg.Unit,
+		ExpiresAt:    dateParam(arg.ExpiresOn),
+		UpdatedAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		return models.PantryItem{}, customDBErr(err)
+	}
+	return rs.getPantryItem(ctx, userID, itemID)
+}
+
+func (rs RecipeService) DeletePantryItem(ctx context.Context, userID, itemID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeletePantryItem")
+	defer span.End()
+
+	n, err := rs.store.Q.DeletePantryItemByID(ctx, database.DeletePantryItemByIDParams{
+		ID:     itemID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// ListCookableRecipes ranks the recipes of the user by what of them the
+// pantry has. Those using an item expiring within expiringWithin days come
+// first, so that it gets used.
+func (rs RecipeService) ListCookableRecipes(ctx context.Context, userID uuid.UUID, expiringWithin int, limit int32) ([]models.CookableRecipe, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListCookableRecipes")
+	defer span.End()
+
+	today := models.NewDate(time.Now().UTC()).Time
+	expiringBefore := today.AddDate(0, 0, expiringWithin+1)
+
+	recipes := []models.CookableRecipe{}
+	rows, err := rs.store.Q.ListCookableRecipes(ctx, database.ListCookableRecipesParams{
+		UserID:         userID,
+		Today:          today,
+		ExpiringBefore: expiringBefore,
+		Limit:          limit,
+	})
+	if err != nil {
+		return recipes, err
+	}
+	for _, r := range rows {
+		recipe := models.CookableRecipe{
+			ID:                r.ID,
+			Name:              r.Name,
+			CookTimeInMinutes: int(r.CookTimeInMinutes),
+			Covered:           int(r.Covered),
+			Total:             int(r.Total),
+			Missing:           r.Missing,
+		}
+		if r.Total > 0 {
+			recipe.Score = float64(r.Covered) / float64(r.Total)
+		}
+		if recipe.Missing == nil {
+			recipe.Missing = []string{}
+		}
+		if r.ExpiresAt != nil {
+			expiresOn := models.NewDate(*r.ExpiresAt)
+			recipe.ExpiresOn = &expiresOn
+			recipe.Expiring = r.ExpiresAt.Before(expiringBefore)
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes, nil
+}
+
+func (rs RecipeService) getPantryItem(ctx context.Context, userID, itemID uuid.UUID) (models.PantryItem, error) {
+	item, err := rs.store.Q.GetPantryItemByID(ctx, database.GetPantryItemByIDParams{
+		ID:     itemID,
+		UserID: userID,
+	})
+	if err != nil {
+		return models.PantryItem{}, checkErrNoRows(err)
+	}
+	return createPantryItemResponse(item), nil
+}
+
+func createPantryItem(ctx context.Context, q *database.Queries, userID uuid.UUID, arg models.PantryItemRequest) (database.PantryItem, error) {
+	item, err := q.CreatePantryItem(ctx, database.CreatePantryItemParams{
+		ID:           uuid.New(),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+		UserID:       userID,
+		IngredientID: arg.IngredientID,
+		Quantity:     arg.Quantity,
+		Unit:         arg.Unit,
+		ExpiresAt:    dateParam(arg.ExpiresOn),
+	})
+	if err != nil {
+		return database.PantryItem{}, checkErrDBConstraint(err)
+	}
+	return item, nil
+}
+
+func dateParam(d *models.Date) *time.Time {
+	if d == nil {
+		return nil
+	}
+	return &d.Time
+}
+
+func createPantryItemResponse(i database.GetPantryItemByIDRow) models.PantryItem {
+	item := models.PantryItem{
+		ID:             i.ID,
+		CreatedAt:      i.CreatedAt,
+		UpdatedAt:      i.UpdatedAt,
+		IngredientID:   i.IngredientID,
+		IngredientName: i.IngredientName,
+		Quantity:       i.Quantity,
+		Unit:           i.Unit,
+	}
+	if i.ExpiresAt != nil {
+		expiresOn := models.NewDate(*i.ExpiresAt)
+		item.ExpiresOn = &expiresOn
+	}
+	return item
+}
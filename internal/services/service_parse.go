@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/importer"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// ParseRecipeText reads a recipe pasted as plain text into a draft. The
+// ingredients are matched to those we have, but nothing is created: the
+// draft is for the user to complete and save.
+func (rs RecipeService) ParseRecipeText(ctx context.Context, text string) (models.RecipeDraft, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ParseRecipeText")
+	defer span.End()
+
+	r := importer.ParseText(text)
+	draft := models.RecipeDraft{
+		Recipe: models.RecipeRequest{
+			Name:              r.Name,
+			Description:       nilIfEmpty(r.Description),
+			Servings:          r.Servings,
+			Yield:             nilIfEmpty(r.Yield),
+			CookTimeInMinutes: r.CookTimeInMinutes,
+			Notes:             nilIfEmpty(r.Notes),
+			Cuisines:          []uuid.UUID{},
+			Ingredients:       []models.IngredientInRecipe{},
+			Instructions:      []models.InstructionInRecipe{},
+		},
+		Unmatched: []string{},
+		Warnings:  r.Warnings,
+	}
+	if draft.Warnings == nil {
+		draft.Warnings = []string{}
+	}
+
+	ingredients, err := rs.ListIngredients(ctx)
+	if err != nil {
+		return draft, err
+	}
+	catalog := newIngredientMatcher(ingredients)
+
+	for _, i := range r.Ingredients {
+		ir := models.IngredientInRecipe{
+			Amount:   i.Amount,
+			PrepNote: nilIfEmpty(i.PrepNote),
+			Name:     i.Name,
+			Index:    len(draft.Recipe.Ingredients) + 1,
+		}
+		if match, ok := catalog.match(i.Name); ok {
+			ir.ID, ir.Name = match.ID, match.Name
+			if !strings.EqualFold(match.Name, i.Name) {
+				draft.Warnings = append(draft.Warnings, fmt.Sprintf("%q matched to %s", i.Name, match.Name))
+			}
+		} else {
+			draft.Unmatched = append(draft.Unmatched, i.Name)
+		}
+		draft.Recipe.Ingredients = append(draft.Recipe.Ingredients, ir)
+	}
+
+	for _, step := range r.Instructions {
+		draft.Recipe.Instructions = append(draft.Recipe.Instructions, models.InstructionInRecipe{
+			StepNo:      len(draft.Recipe.Instructions) + 1,
+			Instruction: step,
+		})
+	}
+
+	return draft, nil
+}
+
+// ingredientMatcher finds the ingredient meant by a name written in a
+// recipe, which may be plural or carry words such as "fresh" or "large".
+type ingredientMatcher struct {
+	byName map[string]models.Ingredient
+}
+
+func newIngredientMatcher(ingredients []models.Ingredient) ingredientMatcher {
+	m := ingredientMatcher{byName: make(map[string]models.Ingredient, len(ingredients))}
+	for _, i := range ingredients {
+		m.byName[strings.ToLower(strings.TrimSpace(i.Name))] = i
+	}
+	return m
+}
+
+func (m ingredientMatcher) match(name string) (models.Ingredient, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return models.Ingredient{}, false
+	}
+	if i, ok := m.lookup(name); ok {
+		return i, true
+	}
+
+	// Try the longest run of words first, so that "ground beef" is
+	// preferred over "beef" in "lean ground beef".
+	words := strings.Fields(name)
+	for n := len(words) - 1; n > 0; n-- {
+		for start := 0; start+n <= len(words); start++ {
+			if i, ok := m.lookup(strings.Join(words[start:start+n], " ")); ok {
+				return i, true
+			}
+		}
+	}
+	return models.Ingredient{}, false
+}
+
+// lookup finds a name as written, or its singular or plural.
+func (m ingredientMatcher) lookup(name string) (models.Ingredient, bool) {
+	candidates := []string{name, name + "s", name + "es"}
+	if strings.HasSuffix(name, "ies") {
+		candidates = append(candidates, strings.TrimSuffix(name, "ies")+"y")
+	}
+	if strings.HasSuffix(name, "y") {
+		candidates = append(candidates, strings.TrimSuffix(name, "y")+"ies")
+	}
+	if strings.HasSuffix(name, "es") {
+		candidates = append(candidates, strings.TrimSuffix(name, "es"))
+	}
+	if strings.HasSuffix(name, "s") {
+		candidates = append(candidates, strings.TrimSuffix(name, "s"))
+	}
+	for _, c := range candidates {
+		if i, ok := m.byName[c]; ok {
+			return i, true
+		}
+	}
+	return models.Ingredient{}, false
+}
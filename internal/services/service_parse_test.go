@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+func TestIngredientMatcher(t *testing.T) {
+	var catalog []models.Ingredient
+	for _, name := range []string{"ground beef", "beef", "tomato", "cherry", "olive oil", "egg", "Green Onion", "fries"} {
+		catalog = append(catalog, models.Ingredient{ID: uuid.New(), Name: name})
+	}
+	m := newIngredientMatcher(catalog)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "beef", want: "beef"},
+		{name: "ground beef", want: "ground beef"},
+		{name: "lean ground beef", want: "ground beef"},
+		{name: "ground beef chuck", want: "ground beef"},
+		{name: "tomatoes", want: "tomato"},
+		{name: "ripe tomatoes", want: "tomato"},
+		{name: "cherries", want: "cherry"},
+		{name: "eggs", want: "egg"},
+		{name: "large eggs", want: "egg"},
+		{name: "extra virgin olive oil", want: "olive oil"},
+		{name: "  GREEN ONIONS ", want: "Green Onion"},
+		{name: "fry", want: "fries"},
+		{name: "chicken"},
+		{name: "oil"},
+		{name: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.match(tt.name)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("matched %q, want no match", got.Name)
+				}
+				return
+			}
+			if !ok || got.Name != tt.want {
+				t.Errorf("got %q (%v), want %q", got.Name, ok, tt.want)
+			}
+		})
+	}
+}
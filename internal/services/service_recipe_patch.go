@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+var (
+	ErrInvalidPatch     = errors.New("patch cannot be applied")
+	ErrUnsupportedPatch = errors.New("unsupported patch format")
+)
+
+// PatchRecipeByID applies a JSON Merge Patch or a JSON Patch, as told by
+// contentType, to the recipe's models.RecipePatchDocument. The result is
+// validated as a whole, then saved like an update.
+func (rs RecipeService) PatchRecipeByID(ctx context.Context, userID, recipeID uuid.UUID, contentType string, patch []byte, version string) (models.Recipe, error) {
+	ctx, span := startSpan(ctx, "RecipeService.PatchRecipeByID")
+	defer span.End()
+
+	current, err := rs.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	if current.UserID != userID {
+		return models.Recipe{}, ErrUnauthorized
+	}
+	// The patch applies to what was read here, so the update must not
+	// overwrite anything newer
+	if version != "" && version != current.Version {
+		return models.Recipe{}, ErrVersionConflict
+	}
+
+	doc, err := json.Marshal(models.NewRecipePatchDocument(current))
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	switch contentType {
+	case models.MergePatchContentType:
+		doc, err = jsonpatch.MergePatch(doc, patch)
+	case models.JSONPatchContentType:
+		var p jsonpatch.Patch
+		p, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			doc, err = p.Apply(doc)
+		}
+	default:
+		return models.Recipe{}, fmt.Errorf("%w: %s", ErrUnsupportedPatch, contentType)
+	}
+	if err != nil {
+		return models.Recipe{}, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	var patched models.RecipePatchDocument
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patched); err != nil {
+		return models.Recipe{}, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	rr, err := patched.RecipeRequest()
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	if err := rr.Validate(ctx); err != nil {
+		return models.Recipe{}, err
+	}
+
+	return rs.updateRecipe(ctx, userID, recipeID, rr, current.Version, nil)
+}
@@ -1,40 +1,68 @@
 package services
 
 import (
-	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
-	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
-	"github.com/dyatlov/go-opengraph/opengraph"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/quangd42/meal-org/internal/blob"
 	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/fetcher"
+	"github.com/quangd42/meal-org/internal/metrics"
 	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/planner"
 )
 
-var ErrUnauthorized = errors.New("unauthorized")
+var (
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrVersionConflict = errors.New("recipe was updated since it was read")
+)
+
+const ogImageFetchTimeout = 10 * time.Second
 
 type RecipeService struct {
-	store *database.Store
+	store   *database.Store
+	fetcher *fetcher.Fetcher
+	blobs   blob.Store
+	planner planner.PlanSuggester
 }
 
-func NewRecipeService(store *database.Store) RecipeService {
-	return RecipeService{store: store}
+func NewRecipeService(store *database.Store, f *fetcher.Fetcher, blobs blob.Store, p planner.PlanSuggester) RecipeService {
+	return RecipeService{store: store, fetcher: f, blobs: blobs, planner: p}
 }
 
 func (rs RecipeService) CreateRecipe(ctx context.Context, userID uuid.UUID, arg models.RecipeRequest) (models.Recipe, error) {
-	var r models.Recipe
+	ctx, span := startSpan(ctx, "RecipeService.CreateRecipe")
+	defer span.End()
 
 	tx, err := rs.store.DB.Begin(ctx)
 	if err != nil {
-		return r, err
+		return models.Recipe{}, err
 	}
 	defer tx.Rollback(ctx)
 
-	qtx := rs.store.Q.WithTx(tx)
+	r, err := createRecipe(ctx, rs.store.Q.WithTx(tx), userID, arg)
+	if err != nil {
+		return r, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return r, err
+	}
+	metrics.RecipesCreated.Inc()
+
+	return r, nil
+}
+
+// createRecipe runs in the transaction of its caller, which commits it.
+func createRecipe(ctx context.Context, qtx *database.Queries, userID uuid.UUID, arg models.RecipeRequest) (models.Recipe, error) {
+	var r models.Recipe
 
 	// Create host Recipe
 	dbRecipe, err := qtx.CreateRecipe(ctx, database.CreateRecipeParams{
@@ -118,17 +146,27 @@ func (rs RecipeService) CreateRecipe(ctx context.Context, userID uuid.UUID, arg
 
 	r = assembleWholeRecipe(dbRecipe, dbCuisines, dbIngredients, dbInstructions)
 
-	err = tx.Commit(ctx)
+	err = saveRecipeRevision(ctx, qtx, userID, r, nil)
 	if err != nil {
 		return r, err
 	}
 
-	go rs.saveExternalImage(dbRecipe.ID, dbRecipe.ExternalUrl)
+	if dbRecipe.ExternalUrl != nil && *dbRecipe.ExternalUrl != "" {
+		err = enqueueFetchExternalImage(ctx, qtx, dbRecipe.ID)
+		if err != nil {
+			return r, err
+		}
+	}
 
 	return r, nil
 }
 
-func (rs RecipeService) UpdateRecipeByID(ctx context.Context, userID, recipeID uuid.UUID, arg models.RecipeRequest) (models.Recipe, error) {
+// UpdateRecipeByID fails with ErrVersionConflict if the recipe is no longer
+// at version. An empty version updates whatever the current one.
+func (rs RecipeService) UpdateRecipeByID(ctx context.Context, userID, recipeID uuid.UUID, arg models.RecipeRequest, version string) (models.Recipe, error) {
+	ctx, span := startSpan(ctx, "RecipeService.UpdateRecipeByID")
+	defer span.End()
+
 	var r models.Recipe
 
 	// Check if the recipe belongs to the user
@@ -141,6 +179,14 @@ func (rs RecipeService) UpdateRecipeByID(ctx context.Context, userID, recipeID u
 		return r, ErrUnauthorized
 	}
 
+	return rs.updateRecipe(ctx, userID, recipeID, arg, version, nil)
+}
+
+// updateRecipe saves arg as the new state of the recipe, and as a revision
+// that restored restoredFrom if it is set.
+func (rs RecipeService) updateRecipe(ctx context.Context, userID, recipeID uuid.UUID, arg models.RecipeRequest, version string, restoredFrom *int) (models.Recipe, error) {
+	var r models.Recipe
+
 	tx, err := rs.store.DB.Begin(ctx)
 	if err != nil {
 		return r, err
@@ -149,10 +195,19 @@ func (rs RecipeService) UpdateRecipeByID(ctx context.Context, userID, recipeID u
 
 	qtx := rs.store.Q.WithTx(tx)
 
-	// Find current External URL
-	currentRecipe, err := qtx.GetRecipeByID(ctx, recipeID)
+	// Find current External URL, and lock the recipe until the version is
+	// bumped
+	currentRecipe, err := qtx.GetRecipeByIDForUpdate(ctx, recipeID)
 	if err != nil {
-		return models.Recipe{}, err
+		return r, checkErrNoRows(err)
+	}
+	if version != "" && models.RecipeVersion(currentRecipe.UpdatedAt) != version {
+		return r, ErrVersionConflict
+	}
+
+	err = ensureFirstRevision(ctx, qtx, currentRecipe)
+	if err != nil {
+		return r, err
 	}
 
 	// Update host Recipe
@@ -189,27 +244,52 @@ func (rs RecipeService) UpdateRecipeByID(ctx context.Context, userID, recipeID u
 		return r, checkErrDBConstraint(err)
 	}
 
+	// Only fetch external image if the external URL has changed
+	// Read is cheap, write is expensive
+	if stringValue(arg.ExternalURL) != stringValue(currentRecipe.ExternalUrl) {
+		// The current metadata belongs to the old URL
+		err = qtx.SaveExternalMetadata(ctx, database.SaveExternalMetadataParams{
+			ID: dbRecipe.ID,
+		})
+		if err != nil {
+			return r, err
+		}
+		dbRecipe.ExternalImageUrl = nil
+		dbRecipe.SiteName = nil
+		dbRecipe.VideoUrl = nil
+
+		if stringValue(arg.ExternalURL) != "" {
+			err = enqueueFetchExternalImage(ctx, qtx, dbRecipe.ID)
+			if err != nil {
+				return r, err
+			}
+		}
+	}
+
 	// Assemble all updated data
 	r = assembleWholeRecipe(dbRecipe, dbCuisines, dbIngredients, dbInstructions)
 
-	err = tx.Commit(ctx)
+	err = saveRecipeRevision(ctx, qtx, userID, r, restoredFrom)
 	if err != nil {
 		return r, err
 	}
 
-	// Only fetch external image if the external URL has changed
-	// Read is cheap, write is expensive
-	if arg.ExternalURL != nil && currentRecipe.ExternalUrl != nil && *arg.ExternalURL != *currentRecipe.ExternalUrl {
-		go rs.saveExternalImage(dbRecipe.ID, dbRecipe.ExternalUrl)
+	err = tx.Commit(ctx)
+	if err != nil {
+		return r, err
 	}
 
 	return r, nil
 }
 
 func (rs RecipeService) ListRecipesByUserID(ctx context.Context, userID uuid.UUID, pgn models.RecipesPagination) ([]models.RecipeInList, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListRecipesByUserID")
+	defer span.End()
+
 	var recipes []models.RecipeInList
 	dbRecipes, err := rs.store.Q.ListRecipesByUserID(ctx, database.ListRecipesByUserIDParams{
 		UserID: userID,
+		Sort:   pgn.Sort,
 		Limit:  pgn.Limit,
 		Offset: pgn.Offset,
 	})
@@ -225,11 +305,17 @@ func (rs RecipeService) ListRecipesByUserID(ctx context.Context, userID uuid.UUI
 			Name:              r.Name,
 			ExternalURL:       r.ExternalUrl,
 			ExternalImageURL:  r.ExternalImageUrl,
+			SiteName:          r.SiteName,
+			VideoURL:          r.VideoUrl,
 			Description:       r.Description,
 			UserID:            r.UserID,
 			Servings:          int(r.Servings),
 			Yield:             r.Yield,
 			CookTimeInMinutes: int(r.CookTimeInMinutes),
+			LastCookedAt:      r.LastCookedAt,
+			TimesCooked:       int(r.TimesCooked),
+			AverageRating:     r.AverageRating,
+			Favorite:          r.Favorite,
 		})
 	}
 
@@ -237,9 +323,13 @@ func (rs RecipeService) ListRecipesByUserID(ctx context.Context, userID uuid.UUI
 }
 
 func (rs RecipeService) ListRecipesWithCuisinesByUserID(ctx context.Context, userID uuid.UUID, pgn models.RecipesPagination) ([]models.RecipeInList, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListRecipesWithCuisinesByUserID")
+	defer span.End()
+
 	var recipes []models.RecipeInList
 	dbRecipes, err := rs.store.Q.ListRecipesWithCuisinesByUserID(ctx, database.ListRecipesWithCuisinesByUserIDParams{
 		UserID: userID,
+		Sort:   pgn.Sort,
 		Limit:  pgn.Limit,
 		Offset: pgn.Offset,
 	})
@@ -248,26 +338,41 @@ func (rs RecipeService) ListRecipesWithCuisinesByUserID(ctx context.Context, use
 	}
 
 	for _, r := range dbRecipes {
-		recipes = append(recipes, models.RecipeInList{
-			ID:                r.ID,
-			CreatedAt:         r.CreatedAt,
-			UpdatedAt:         r.UpdatedAt,
-			Name:              r.Name,
-			ExternalURL:       r.ExternalUrl,
-			ExternalImageURL:  r.ExternalImageUrl,
-			Description:       r.Description,
-			UserID:            r.UserID,
-			Servings:          int(r.Servings),
-			Yield:             r.Yield,
-			CookTimeInMinutes: int(r.CookTimeInMinutes),
-			Cuisines:          string(r.Cuisines),
-		})
+		recipes = append(recipes, recipeWithCuisinesResponse(r))
 	}
 
 	return recipes, nil
 }
 
+// recipeWithCuisinesResponse also takes the rows of the other queries that
+// list recipes with their cuisines, which convert to this one.
+func recipeWithCuisinesResponse(r database.ListRecipesWithCuisinesByUserIDRow) models.RecipeInList {
+	return models.RecipeInList{
+		ID:                r.ID,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+		Name:              r.Name,
+		ExternalURL:       r.ExternalUrl,
+		ExternalImageURL:  r.ExternalImageUrl,
+		SiteName:          r.SiteName,
+		VideoURL:          r.VideoUrl,
+		Description:       r.Description,
+		UserID:            r.UserID,
+		Servings:          int(r.Servings),
+		Yield:             r.Yield,
+		CookTimeInMinutes: int(r.CookTimeInMinutes),
+		Cuisines:          string(r.Cuisines),
+		LastCookedAt:      r.LastCookedAt,
+		TimesCooked:       int(r.TimesCooked),
+		AverageRating:     r.AverageRating,
+		Favorite:          r.Favorite,
+	}
+}
+
 func (rs RecipeService) GetRecipeByID(ctx context.Context, recipeID uuid.UUID) (models.Recipe, error) {
+	ctx, span := startSpan(ctx, "RecipeService.GetRecipeByID")
+	defer span.End()
+
 	var r models.Recipe
 
 	tx, err := rs.store.DB.Begin(ctx)
@@ -301,11 +406,24 @@ func (rs RecipeService) GetRecipeByID(ctx context.Context, recipeID uuid.UUID) (
 	return r, tx.Commit(ctx)
 }
 
-func (rs RecipeService) DeleteRecipeByID(ctx context.Context, recipeID uuid.UUID) error {
-	err := rs.store.Q.DeleteRecipe(ctx, recipeID)
+// DeleteRecipeByID moves the recipe of the user to the trash. It can be
+// restored until it is deleted forever or purged.
+func (rs RecipeService) DeleteRecipeByID(ctx context.Context, userID, recipeID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteRecipeByID")
+	defer span.End()
+
+	now := time.Now().UTC()
+	n, err := rs.store.Q.SoftDeleteRecipe(ctx, database.SoftDeleteRecipeParams{
+		ID:        recipeID,
+		DeletedAt: &now,
+		UserID:    userID,
+	})
 	if err != nil {
 		return err
 	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
 	return nil
 }
 
@@ -343,12 +461,15 @@ func assembleWholeRecipe(dr database.Recipe, dbCuisines []database.ListCuisinesB
 		UpdatedAt:         dr.UpdatedAt,
 		Name:              dr.Name,
 		ExternalURL:       dr.ExternalUrl,
+		SiteName:          dr.SiteName,
+		VideoURL:          dr.VideoUrl,
 		Description:       dr.Description,
 		UserID:            dr.UserID,
 		Servings:          int(dr.Servings),
 		Yield:             dr.Yield,
 		CookTimeInMinutes: int(dr.CookTimeInMinutes),
 		Notes:             dr.Notes,
+		Version:           models.RecipeVersion(dr.UpdatedAt),
 		Cuisines:          cuisines,
 		Ingredients:       ingredients,
 		Instructions:      instructions,
@@ -518,90 +639,82 @@ func updateInstructionsInRecipe(ctx context.Context, qtx *database.Queries, para
 	return dbInstructions, nil
 }
 
-func (rs RecipeService) saveExternalImage(recipeID uuid.UUID, recipeURL *string) {
-	if recipeURL == nil || *recipeURL == "" {
-		return
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-	ch := make(chan error)
-	defer close(ch)
-	go func() {
-		imageURL, err := fetchOGImage(*recipeURL)
-		if err != nil {
-
-			// Attempt to "delete" current external image, but ignore error
-			_ = rs.store.Q.SaveExternalImageURL(ctx, database.SaveExternalImageURLParams{
-				ID:               recipeID,
-				ExternalImageUrl: nil,
-			})
-
-			ch <- err
-			return
-		}
-		err = rs.store.Q.SaveExternalImageURL(ctx, database.SaveExternalImageURLParams{
-			ID:               recipeID,
-			ExternalImageUrl: &imageURL,
-		})
-		ch <- err
-	}()
-	select {
-	case <-ctx.Done():
-		log.Printf("failed to save external image, %s: timed out\n", *recipeURL)
-	case err := <-ch:
-		if err != nil {
-			log.Printf("failed to save external image %s: %v\n", *recipeURL, err)
-		}
-	}
+type fetchExternalImagePayload struct {
+	RecipeID uuid.UUID `json:"recipe_id"`
 }
 
-func fetchOGImage(url string) (string, error) {
-	og := opengraph.NewOpenGraph()
+func enqueueFetchExternalImage(ctx context.Context, q *database.Queries, recipeID uuid.UUID) error {
+	return enqueueJob(ctx, q, JobFetchExternalImage, JobFetchExternalImage+":"+recipeID.String(),
+		&recipeID, fetchExternalImagePayload{RecipeID: recipeID})
+}
 
-	agent := "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:103.0) Gecko/20100101 Firefox/103.0"
-	client := &http.Client{}
+// FetchExternalImage is the JobHandler that saves the OG image, site name and
+// video of a recipe's external URL. It reads the URL when it runs, so a job queued before the
+// URL changed still fetches the right image.
+func (rs RecipeService) FetchExternalImage(ctx context.Context, job database.Job) error {
+	var p fetchExternalImagePayload
+	if err := json.Unmarshal(job.Payload, &p); err != nil {
+		return permanentJobError{err}
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	recipe, err := rs.store.Q.GetRecipeByID(ctx, p.RecipeID)
 	if err != nil {
-		log.Println(err)
-		return "", fmt.Errorf("cannot create request to %s", url)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The recipe is gone, there is nothing left to do
+			return nil
+		}
+		return err
 	}
-
-	// req.Header.Set("User-Agent", agent)
-	// req.Header.Set("Accept-Encoding", "gzip, deflate")
-	// req.Header.Set("Accept", "*/*")
-	// req.Header.Set("Connection", "keep-alive")
-	req.Header = http.Header{
-		"User-Agent":      {agent},
-		"Accept-Encoding": {"gzip, deflate"},
-		"Accept":          {"*/*"},
-		"Connection":      {"keep-alive"},
+	if recipe.ExternalUrl == nil || *recipe.ExternalUrl == "" {
+		return nil
 	}
 
-	// make the http request
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println(err, url)
-		return "", fmt.Errorf("cannot fetch %s", url)
+	fetchCtx, cancel := context.WithTimeout(ctx, ogImageFetchTimeout)
+	defer cancel()
+	meta, err := rs.fetchLinkMetadata(fetchCtx, *recipe.ExternalUrl)
+	switch {
+	case err == nil && meta.ImageURL == "":
+		metrics.ImageFetches.WithLabelValues(metrics.ImageFetchNoOGImage).Inc()
+		slog.DebugContext(ctx, "no OG image found", "recipe_id", recipe.ID, "url", *recipe.ExternalUrl)
+	case err == nil:
+		metrics.ImageFetches.WithLabelValues(metrics.ImageFetchSuccess).Inc()
+	case errors.Is(err, context.DeadlineExceeded):
+		metrics.ImageFetches.WithLabelValues(metrics.ImageFetchTimeout).Inc()
+		return err
+	default:
+		metrics.ImageFetches.WithLabelValues(metrics.ImageFetchError).Inc()
+		if isPermanentFetchError(err) {
+			return permanentJobError{err}
+		}
+		return err
 	}
-	defer resp.Body.Close()
 
-	// decompress the response
-	reader, err := gzip.NewReader(resp.Body)
+	err = rs.store.Q.SaveExternalMetadata(ctx, database.SaveExternalMetadataParams{
+		ID:               recipe.ID,
+		ExternalImageUrl: nilIfEmpty(meta.ImageURL),
+		SiteName:         nilIfEmpty(meta.SiteName),
+		VideoUrl:         nilIfEmpty(meta.VideoURL),
+	})
 	if err != nil {
-		log.Println(err, url)
-		return "", fmt.Errorf("cannot decompress response from %s", url)
+		return err
 	}
-	defer reader.Close()
-
-	err = og.ProcessHTML(reader)
-	if err != nil {
-		return "", errors.New("html cannot be processed")
+	if meta.ImageURL == "" {
+		return nil
 	}
 
-	if len(og.Images) == 0 {
-		return "", errors.New("no OG image")
-	}
+	// Have the image ready before it is first shown
+	return enqueueCacheRecipeImage(ctx, rs.store.Q, recipe.ID)
+}
 
-	return og.Images[0].URL, nil
+// isPermanentFetchError reports whether fetching again can't succeed.
+func isPermanentFetchError(err error) bool {
+	var statusErr *fetcher.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode < 500 && statusErr.StatusCode != http.StatusTooManyRequests
+	}
+	return errors.Is(err, fetcher.ErrUnsupportedURL) ||
+		errors.Is(err, fetcher.ErrBlockedAddress) ||
+		errors.Is(err, fetcher.ErrTooLarge) ||
+		errors.Is(err, fetcher.ErrTooManyRedirects) ||
+		errors.Is(err, fetcher.ErrUnsupportedEncoding)
 }
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const exportPageSize = 100
+
+// ExportRecipesByUserID returns every recipe of the user in full.
+func (rs RecipeService) ExportRecipesByUserID(ctx context.Context, userID uuid.UUID) ([]models.Recipe, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ExportRecipesByUserID")
+	defer span.End()
+
+	recipes := []models.Recipe{}
+	pgn := models.RecipesPagination{Limit: exportPageSize}
+	for {
+		page, err := rs.ListRecipesByUserID(ctx, userID, pgn)
+		if err != nil {
+			return recipes, err
+		}
+		for _, r := range page {
+			recipe, err := rs.GetRecipeByID(ctx, r.ID)
+			if err != nil {
+				return recipes, err
+			}
+			recipes = append(recipes, recipe)
+		}
+		if len(page) < int(pgn.Limit) {
+			return recipes, nil
+		}
+		pgn.Offset += pgn.Limit
+	}
+}
+
+// RecipeRequestFromRecipe turns a recipe exported from any instance into a
+// request for this one. Ingredients are matched by name and created when
+// missing, cuisines are matched by name and must already exist.
+func (rs RecipeService) RecipeRequestFromRecipe(ctx context.Context, r models.Recipe) (models.RecipeRequest, error) {
+	ctx, span := startSpan(ctx, "RecipeService.RecipeRequestFromRecipe")
+	defer span.End()
+
+	rr := models.RecipeRequest{
+		Name:              r.Name,
+		ExternalURL:       r.ExternalURL,
+		Description:       r.Description,
+		Servings:          r.Servings,
+		Yield:             r.Yield,
+		CookTimeInMinutes: r.CookTimeInMinutes,
+		Notes:             r.Notes,
+		Instructions:      r.Instructions,
+	}
+
+	for _, c := range r.Cuisines {
+		cuisine, err := rs.GetCuisineByName(ctx, c.Name)
+		if err != nil {
+			return rr, fmt.Errorf("cuisine %q: %w", c.Name, err)
+		}
+		rr.Cuisines = append(rr.Cuisines, cuisine.ID)
+	}
+
+	for _, i := range r.Ingredients {
+		ingredient, err := rs.GetOrCreateIngredientByName(ctx, i.Name)
+		if err != nil {
+			return rr, fmt.Errorf("ingredient %q: %w", i.Name, err)
+		}
+		i.ID = ingredient.ID
+		rr.Ingredients = append(rr.Ingredients, i)
+	}
+
+	return rr, nil
+}
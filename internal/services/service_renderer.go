@@ -26,6 +26,12 @@ var privateNavItems = []models.NavItem{
 			URL:  "/recipes/add",
 		},
 	},
+	{
+		Link: models.Link{
+			Name: "Collections",
+			URL:  "/collections",
+		},
+	},
 	{
 		Link: models.Link{
 			Name: "Logout",
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// saveRecipeRevision stores r as the latest revision of the recipe. It runs in
+// the transaction that changes the recipe, so that no change goes unrecorded.
+func saveRecipeRevision(ctx context.Context, q *database.Queries, userID uuid.UUID, r models.Recipe, restoredFrom *int) error {
+	snapshot, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	var from *int32
+	if restoredFrom != nil {
+		v := int32(*restoredFrom)
+		from = &v
+	}
+
+	_, err = q.CreateRecipeRevision(ctx, database.CreateRecipeRevisionParams{
+		ID:           uuid.New(),
+		CreatedAt:    time.Now().UTC(),
+		RecipeID:     r.ID,
+		UserID:       userID,
+		Recipe:       snapshot,
+		RestoredFrom: from,
+	})
+	return err
+}
+
+// ensureFirstRevision saves the current state of recipes created before
+// revisions were kept, so that their first update can still be undone.
+func ensureFirstRevision(ctx context.Context, q *database.Queries, dbRecipe database.Recipe) error {
+	count, err := q.CountRecipeRevisions(ctx, dbRecipe.ID)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	dbCuisines, err := q.ListCuisinesByRecipeID(ctx, dbRecipe.ID)
+	if err != nil {
+		return err
+	}
+	dbIngredients, err := q.ListIngredientsByRecipeID(ctx, dbRecipe.ID)
+	if err != nil {
+		return err
+	}
+	dbInstructions, err := q.ListInstructionsByRecipeID(ctx, dbRecipe.ID)
+	if err != nil {
+		return err
+	}
+
+	r := assembleWholeRecipe(dbRecipe, dbCuisines, dbIngredients, dbInstructions)
+	return saveRecipeRevision(ctx, q, dbRecipe.UserID, r, nil)
+}
+
+func (rs RecipeService) checkRecipeOwner(ctx context.Context, userID, recipeID uuid.UUID) error {
+	recipe, err := rs.store.Q.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return checkErrNoRows(err)
+	}
+	if recipe.UserID != userID {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// ListRecipeRevisions returns the revisions of the recipe, latest first, each
+// with its changes from the one before.
+func (rs RecipeService) ListRecipeRevisions(ctx context.Context, userID, recipeID uuid.UUID) ([]models.RecipeRevision, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListRecipeRevisions")
+	defer span.End()
+
+	if err := rs.checkRecipeOwner(ctx, userID, recipeID); err != nil {
+		return nil, err
+	}
+
+	dbRevisions, err := rs.store.Q.ListRecipeRevisions(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []models.RecipeRevision{}
+	for _, dr := range dbRevisions {
+		rev, err := toRecipeRevision(dr)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	for i := range len(revisions) - 1 {
+		revisions[i].Changes = diffRecipes(revisions[i+1].Recipe, revisions[i].Recipe)
+	}
+	return revisions, nil
+}
+
+func (rs RecipeService) GetRecipeRevision(ctx context.Context, userID, recipeID uuid.UUID, revision int) (models.RecipeRevision, error) {
+	ctx, span := startSpan(ctx, "RecipeService.GetRecipeRevision")
+	defer span.End()
+
+	if err := rs.checkRecipeOwner(ctx, userID, recipeID); err != nil {
+		return models.RecipeRevision{}, err
+	}
+	return rs.getRecipeRevision(ctx, recipeID, revision)
+}
+
+func (rs RecipeService) getRecipeRevision(ctx context.Context, recipeID uuid.UUID, revision int) (models.RecipeRevision, error) {
+	dr, err := rs.store.Q.GetRecipeRevision(ctx, database.GetRecipeRevisionParams{
+		RecipeID: recipeID,
+		Revision: int32(revision),
+	})
+	if err != nil {
+		return models.RecipeRevision{}, checkErrNoRows(err)
+	}
+	return toRecipeRevision(dr)
+}
+
+// DiffRecipeRevisions lists the fields that changed from one revision to
+// the other.
+func (rs RecipeService) DiffRecipeRevisions(ctx context.Context, userID, recipeID uuid.UUID, from, to int) (models.RecipeDiff, error) {
+	ctx, span := startSpan(ctx, "RecipeService.DiffRecipeRevisions")
+	defer span.End()
+
+	if err := rs.checkRecipeOwner(ctx, userID, recipeID); err != nil {
+		return models.RecipeDiff{}, err
+	}
+
+	fromRev, err := rs.getRecipeRevision(ctx, recipeID, from)
+	if err != nil {
+		return models.RecipeDiff{}, err
+	}
+	toRev, err := rs.getRecipeRevision(ctx, recipeID, to)
+	if err != nil {
+		return models.RecipeDiff{}, err
+	}
+
+	return models.RecipeDiff{
+		From:    from,
+		To:      to,
+		Changes: diffRecipes(fromRev.Recipe, toRev.Recipe),
+	}, nil
+}
+
+// RestoreRecipeRevision brings the recipe back to the given revision. The
+// restore is itself saved as a new revision, so that it can be undone too.
+func (rs RecipeService) RestoreRecipeRevision(ctx context.Context, userID, recipeID uuid.UUID, revision int) (models.Recipe, error) {
+	ctx, span := startSpan(ctx, "RecipeService.RestoreRecipeRevision")
+	defer span.End()
+
+	if err := rs.checkRecipeOwner(ctx, userID, recipeID); err != nil {
+		return models.Recipe{}, err
+	}
+
+	rev, err := rs.getRecipeRevision(ctx, recipeID, revision)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	snapshot := rev.Recipe
+	cuisines := make([]uuid.UUID, len(snapshot.Cuisines))
+	for i, c := range snapshot.Cuisines {
+		cuisines[i] = c.ID
+	}
+	arg := models.RecipeRequest{
+		Name:              snapshot.Name,
+		ExternalURL:       snapshot.ExternalURL,
+		Description:       snapshot.Description,
+		Servings:          snapshot.Servings,
+		Yield:             snapshot.Yield,
+		CookTimeInMinutes: snapshot.CookTimeInMinutes,
+		Notes:             snapshot.Notes,
+		Cuisines:          cuisines,
+		Ingredients:       snapshot.Ingredients,
+		Instructions:      snapshot.Instructions,
+	}
+
+	return rs.updateRecipe(ctx, userID, recipeID, arg, "", &revision)
+}
+
+func toRecipeRevision(dr database.RecipeRevision) (models.RecipeRevision, error) {
+	rev := models.RecipeRevision{
+		Revision:  int(dr.Revision),
+		CreatedAt: dr.CreatedAt,
+		UserID:    dr.UserID,
+	}
+	if dr.RestoredFrom != nil {
+		from := int(*dr.RestoredFrom)
+		rev.RestoredFrom = &from
+	}
+	if err := json.Unmarshal(dr.Recipe, &rev.Recipe); err != nil {
+		return rev, fmt.Errorf("invalid snapshot of revision %d: %w", dr.Revision, err)
+	}
+	return rev, nil
+}
+
+// diffRecipes compares two states of a recipe field by field.
+func diffRecipes(from, to models.Recipe) []models.FieldChange {
+	changes := []models.FieldChange{}
+	add := func(field, a, b string) {
+		if a != b {
+			changes = append(changes, models.FieldChange{Field: field, From: a, To: b})
+		}
+	}
+
+	add("name", from.Name, to.Name)
+	add("external_url", stringValue(from.ExternalURL), stringValue(to.ExternalURL))
+	add("description", stringValue(from.Description), stringValue(to.Description))
+	add("servings", strconv.Itoa(from.Servings), strconv.Itoa(to.Servings))
+	add("yield", stringValue(from.Yield), stringValue(to.Yield))
+	add("cook_time_in_minutes", strconv.Itoa(from.CookTimeInMinutes), strconv.Itoa(to.CookTimeInMinutes))
+	add("notes", stringValue(from.Notes), stringValue(to.Notes))
+	add("cuisines", cuisineNames(from.Cuisines), cuisineNames(to.Cuisines))
+
+	fromIngredients, toIngredients := ingredientsByIndex(from.Ingredients), ingredientsByIndex(to.Ingredients)
+	for _, i := range unionKeys(fromIngredients, toIngredients) {
+		add(fmt.Sprintf("ingredients[%d]", i), fromIngredients[i], toIngredients[i])
+	}
+
+	fromSteps, toSteps := instructionsByStep(from.Instructions), instructionsByStep(to.Instructions)
+	for _, i := range unionKeys(fromSteps, toSteps) {
+		add(fmt.Sprintf("instructions[%d]", i), fromSteps[i], toSteps[i])
+	}
+
+	return changes
+}
+
+func cuisineNames(cuisines []models.CuisineInRecipe) string {
+	names := make([]string, len(cuisines))
+	for i, c := range cuisines {
+		names[i] = c.Name
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func ingredientsByIndex(ingredients []models.IngredientInRecipe) map[int]string {
+	m := make(map[int]string, len(ingredients))
+	for _, i := range ingredients {
+		s := strings.TrimSpace(i.Amount + " " + i.Name)
+		if note := stringValue(i.PrepNote); note != "" {
+			s += ", " + note
+		}
+		m[i.Index] = s
+	}
+	return m
+}
+
+func instructionsByStep(instructions []models.InstructionInRecipe) map[int]string {
+	m := make(map[int]string, len(instructions))
+	for _, i := range instructions {
+		m[i.StepNo] = i.Instruction
+	}
+	return m
+}
+
+func unionKeys(a, b map[int]string) []int {
+	keys := make([]int, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+const (
+	// tagSuggestions is how many tags are suggested for what the user
+	// started typing.
+	tagSuggestions = 10
+	maxTags        = 1000
+)
+
+// ListTags lists the tags of the user with how many recipes have them. With
+// a prefix, it suggests the few tags starting with it.
+func (rs RecipeService) ListTags(ctx context.Context, userID uuid.UUID, prefix string) ([]models.Tag, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListTags")
+	defer span.End()
+
+	limit := maxTags
+	prefix = normalizeTagName(prefix)
+	if prefix != "" {
+		limit = tagSuggestions
+	}
+
+	tags := []models.Tag{}
+	dbTags, err := rs.store.Q.ListTagsByUserID(ctx, database.ListTagsByUserIDParams{
+		UserID:  userID,
+		Prefix:  escapeLike(prefix),
+		MaxTags: int32(limit),
+	})
+	if err != nil {
+		return tags, err
+	}
+
+	for _, t := range dbTags {
+		tag := createTagResponse(database.Tag{
+			ID:        t.ID,
+			CreatedAt: t.CreatedAt,
+			UpdatedAt: t.UpdatedAt,
+			UserID:    t.UserID,
+			Name:      t.Name,
+		})
+		tag.RecipeCount = int(t.RecipeCount)
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (rs RecipeService) CreateTag(ctx context.Context, userID uuid.UUID, arg models.TagRequest) (models.Tag, error) {
+	ctx, span := startSpan(ctx, "RecipeService.CreateTag")
+	defer span.End()
+
+	tag, err := rs.store.Q.CreateTag(ctx, database.CreateTagParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    userID,
+		Name:      normalizeTagName(arg.Name),
+	})
+	if err != nil {
+		return models.Tag{}, checkErrDBConstraint(err)
+	}
+	return createTagResponse(tag), nil
+}
+
+// UpdateTagByID renames a tag, on every recipe that has it.
+func (rs RecipeService) UpdateTagByID(ctx context.Context, userID, tagID uuid.UUID, arg models.TagRequest) (models.Tag, error) {
+	ctx, span := startSpan(ctx, "RecipeService.UpdateTagByID")
+	defer span.End()
+
+	tag, err := rs.store.Q.UpdateTagByID(ctx, database.UpdateTagByIDParams{
+		ID:        tagID,
+		UserID:    userID,
+		Name:      normalizeTagName(arg.Name),
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return models.Tag{}, customDBErr(err)
+	}
+	return createTagResponse(tag), nil
+}
+
+// DeleteTagByID deletes a tag and takes it off the recipes that have it.
+func (rs RecipeService) DeleteTagByID(ctx context.Context, userID, tagID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteTagByID")
+	defer span.End()
+
+	n, err := rs.store.Q.DeleteTagByID(ctx, database.DeleteTagByIDParams{
+		ID:     tagID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+func (rs RecipeService) ListRecipeTags(ctx context.Context, userID, recipeID uuid.UUID) ([]models.Tag, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListRecipeTags")
+	defer span.End()
+
+	if err := checkRecipeOwner(ctx, rs.store.Q, userID, recipeID); err != nil {
+		return nil, err
+	}
+
+	dbTags, err := rs.store.Q.ListTagsByRecipeID(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]models.Tag, 0, len(dbTags))
+	for _, t := range dbTags {
+		tags = append(tags, createTagResponse(t))
+	}
+	return tags, nil
+}
+
+// SetRecipeTags replaces the tags of a recipe, creating the tags the user
+// doesn't have yet.
+func (rs RecipeService) SetRecipeTags(ctx context.Context, userID, recipeID uuid.UUID, names []string) ([]models.Tag, error) {
+	ctx, span := startSpan(ctx, "RecipeService.SetRecipeTags")
+	defer span.End()
+
+	tx, err := rs.store.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := rs.store.Q.WithTx(tx)
+
+	if err := checkRecipeOwner(ctx, qtx, userID, recipeID); err != nil {
+		return nil, err
+	}
+	if err := qtx.DeleteTagsByRecipeID(ctx, recipeID); err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		name = normalizeTagName(name)
+		if name == "" {
+			continue
+		}
+		tag, err := getOrCreateTag(ctx, qtx, userID, name)
+		if err != nil {
+			return nil, err
+		}
+		err = qtx.AddTagToRecipe(ctx, database.AddTagToRecipeParams{
+			CreatedAt: time.Now().UTC(),
+			TagID:     tag.ID,
+			RecipeID:  recipeID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dbTags, err := qtx.ListTagsByRecipeID(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	tags := make([]models.Tag, 0, len(dbTags))
+	for _, t := range dbTags {
+		tags = append(tags, createTagResponse(t))
+	}
+	return tags, nil
+}
+
+func getOrCreateTag(ctx context.Context, q *database.Queries, userID uuid.UUID, name string) (database.Tag, error) {
+	tag, err := q.GetTagByName(ctx, database.GetTagByNameParams{UserID: userID, Lower: name})
+	if err == nil || !errors.Is(err, pgx.ErrNoRows) {
+		return tag, err
+	}
+	return q.CreateTag(ctx, database.CreateTagParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    userID,
+		Name:      name,
+	})
+}
+
+// checkRecipeOwner returns ErrResourceNotFound unless the recipe is one of
+// the user's, and not in the trash.
+func checkRecipeOwner(ctx context.Context, q *database.Queries, userID, recipeID uuid.UUID) error {
+	recipe, err := q.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return checkErrNoRows(err)
+	}
+	if recipe.UserID != userID {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// normalizeTagName trims a tag name and collapses its spaces, so that
+// "week  night " and "week night" are the same tag.
+func normalizeTagName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func createTagResponse(t database.Tag) models.Tag {
+	return models.Tag{
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+		Name:      t.Name,
+	}
+}
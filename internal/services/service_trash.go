@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/models"
+)
+
+// DefaultTrashRetention is how long deleted recipes stay in the trash before
+// they are purged.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// ListDeletedRecipes returns the recipes of the user that are in the trash,
+// most recently deleted first.
+func (rs RecipeService) ListDeletedRecipes(ctx context.Context, userID uuid.UUID) ([]models.RecipeInList, error) {
+	ctx, span := startSpan(ctx, "RecipeService.ListDeletedRecipes")
+	defer span.End()
+
+	recipes := []models.RecipeInList{}
+	dbRecipes, err := rs.store.Q.ListDeletedRecipesByUserID(ctx, userID)
+	if err != nil {
+		return recipes, err
+	}
+
+	for _, r := range dbRecipes {
+		recipes = append(recipes, models.RecipeInList{
+			ID:                r.ID,
+			CreatedAt:         r.CreatedAt,
+			UpdatedAt:         r.UpdatedAt,
+			Name:              r.Name,
+			ExternalURL:       r.ExternalUrl,
+			ExternalImageURL:  r.ExternalImageUrl,
+			SiteName:          r.SiteName,
+			VideoURL:          r.VideoUrl,
+			Description:       r.Description,
+			UserID:            r.UserID,
+			Servings:          int(r.Servings),
+			Yield:             r.Yield,
+			CookTimeInMinutes: int(r.CookTimeInMinutes),
+			DeletedAt:         r.DeletedAt,
+		})
+	}
+
+	return recipes, nil
+}
+
+// RestoreDeletedRecipe takes the recipe out of the trash.
+func (rs RecipeService) RestoreDeletedRecipe(ctx context.Context, userID, recipeID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.RestoreDeletedRecipe")
+	defer span.End()
+
+	n, err := rs.store.Q.RestoreDeletedRecipe(ctx, database.RestoreDeletedRecipeParams{
+		ID:     recipeID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// DeleteRecipeForever removes a recipe in the trash for good.
+func (rs RecipeService) DeleteRecipeForever(ctx context.Context, userID, recipeID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RecipeService.DeleteRecipeForever")
+	defer span.End()
+
+	n, err := rs.store.Q.DeleteDeletedRecipe(ctx, database.DeleteDeletedRecipeParams{
+		ID:     recipeID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrResourceNotFound
+	}
+
+	rs.deleteRecipeImages(ctx, recipeID)
+	return nil
+}
+
+// PurgeDeletedRecipes removes the recipes that have been in the trash for
+// longer than retention.
+func (rs RecipeService) PurgeDeletedRecipes(ctx context.Context, retention time.Duration) error {
+	ctx, span := startSpan(ctx, "RecipeService.PurgeDeletedRecipes")
+	defer span.End()
+
+	before := time.Now().UTC().Add(-retention)
+	ids, err := rs.store.Q.PurgeDeletedRecipes(ctx, &before)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		rs.deleteRecipeImages(ctx, id)
+	}
+	if len(ids) > 0 {
+		slog.InfoContext(ctx, "purged deleted recipes", "count", len(ids))
+	}
+	return nil
+}
+
+func (rs RecipeService) deleteRecipeImages(ctx context.Context, recipeID uuid.UUID) {
+	// Leftover images are only wasted space, don't fail the delete for them
+	if err := rs.blobs.Delete(ctx, "recipes/"+recipeID.String()); err != nil {
+		slog.WarnContext(ctx, "failed to delete recipe images", "recipe_id", recipeID, "error", err)
+	}
+}
@@ -3,7 +3,7 @@ package services
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,7 +26,7 @@ func (us UserService) CreateUser(ctx context.Context, ur models.CreateUserReques
 	var u models.User
 	hash, err := auth.HashPassword([]byte(ur.Password))
 	if err != nil {
-		log.Printf("error hashing password: %s\n", err)
+		slog.ErrorContext(ctx, "error hashing password", "error", err)
 		return u, ErrHashPassword
 	}
 
@@ -38,7 +38,7 @@ func (us UserService) CreateUser(ctx context.Context, ur models.CreateUserReques
 		Hash:      string(hash),
 	})
 	if err != nil {
-		log.Printf("error creating new user: %s\n", err)
+		slog.ErrorContext(ctx, "error creating new user", "error", err)
 		return u, ErrDBConstraint
 	}
 
@@ -52,7 +52,7 @@ func (us UserService) UpdateUserByID(ctx context.Context, userID uuid.UUID, ur m
 
 	hash, err := auth.HashPassword([]byte(ur.Password))
 	if err != nil {
-		log.Printf("error hashing password: %s\n", err)
+		slog.ErrorContext(ctx, "error hashing password", "error", err)
 		return u, ErrHashPassword
 	}
 
@@ -70,6 +70,22 @@ func (us UserService) UpdateUserByID(ctx context.Context, userID uuid.UUID, ur m
 	return u, nil
 }
 
+func (us UserService) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	user, err := us.store.Q.GetUserByEmail(ctx, email)
+	if err != nil {
+		return models.User{}, checkErrNoRows(err)
+	}
+	return genUserResponse(user), nil
+}
+
+func (us UserService) UpdateUserAdminByID(ctx context.Context, userID uuid.UUID, isAdmin bool) error {
+	return us.store.Q.UpdateUserAdminByID(ctx, database.UpdateUserAdminByIDParams{
+		ID:        userID,
+		IsAdmin:   isAdmin,
+		UpdatedAt: time.Now().UTC(),
+	})
+}
+
 func (us UserService) DeleteUserByID(ctx context.Context, userID uuid.UUID) error {
 	err := us.store.Q.DeleteUser(ctx, userID)
 	if err != nil {
@@ -85,5 +101,6 @@ func genUserResponse(u database.User) models.User {
 		UpdatedAt: u.UpdatedAt,
 		Name:      u.Name,
 		Email:     u.Email,
+		IsAdmin:   u.IsAdmin,
 	}
 }
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"context"
 	"errors"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/quangd42/meal-org/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -38,3 +41,22 @@ func customDBErr(err error) error {
 	}
 	return err
 }
+
+// startSpan starts the span of a service method, the caller ends it.
+func startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name, opts...)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,210 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quangd42/meal-org/internal/blob"
+	"github.com/quangd42/meal-org/internal/database"
+	"github.com/quangd42/meal-org/internal/fetcher"
+	"github.com/quangd42/meal-org/internal/handlers"
+	"github.com/quangd42/meal-org/internal/models"
+	"github.com/quangd42/meal-org/internal/planner"
+	"github.com/quangd42/meal-org/internal/services"
+	"github.com/quangd42/meal-org/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testStore connects to the database at $TEST_DATABASE_URL, migrated, with
+// its queries traced. The test is skipped without one.
+func testStore(t *testing.T) *database.Store {
+	t.Helper()
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	cfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.Tracer = tracing.QueryTracer{}
+	db, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	store := database.NewStore(db)
+
+	ms, err := services.NewMigrationService(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ms.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestCreateRecipeSpans(t *testing.T) {
+	store := testStore(t)
+	ctx := context.Background()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { tp.Shutdown(ctx) })
+
+	blobs, err := blob.NewDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := services.NewRecipeService(store, fetcher.New(), blobs, planner.Suggester{})
+	us := services.NewUserService(store)
+	as := services.NewAuthService(store, "test-secret")
+	ms, err := services.NewMigrationService(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm := services.NewSessionManager(store)
+	r := chi.NewRouter()
+	handlers.AddRoutes(r, sm, services.NewRendererService(), us, as, rs, services.NewHealthService(store, ms, sm.Store))
+
+	user, err := us.CreateUser(ctx, models.CreateUserRequest{Email: uuid.NewString() + "@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := as.GenerateAccessToken(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cuisine, err := rs.CreateCuisine(ctx, models.CuisineRequest{Name: "Tracing " + uuid.NewString()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ingredient, err := rs.CreateIngredient(ctx, models.IngredientRequest{Name: "Tracing " + uuid.NewString()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		us.DeleteUserByID(ctx, user.ID)
+		rs.DeleteCuisine(ctx, cuisine.ID)
+		rs.DeleteIngredient(ctx, ingredient.ID)
+	})
+
+	url := "https://example.com/soup"
+	body, _ := json.Marshal(models.RecipeRequest{
+		Name:              "Soup",
+		ExternalURL:       &url,
+		Servings:          2,
+		CookTimeInMinutes: 20,
+		Cuisines:          []uuid.UUID{cuisine.ID},
+		Ingredients:       []models.IngredientInRecipe{{ID: ingredient.ID, Amount: "1"}},
+		Instructions:      []models.InstructionInRecipe{{StepNo: 1, Instruction: "Boil"}},
+	})
+	exporter.Reset()
+	req := httptest.NewRequest(http.MethodPost, "/v1/recipes", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /v1/recipes: got %d, %s", w.Code, w.Body)
+	}
+
+	spans := exporter.GetSpans()
+	route := findSpan(t, spans, "POST /v1/recipes/")
+	if route.SpanKind != trace.SpanKindServer {
+		t.Errorf("route span kind: got %v, want server", route.SpanKind)
+	}
+	if !hasAttribute(route, "http.route", "/v1/recipes/") {
+		t.Errorf("route span has no http.route attribute: %v", route.Attributes)
+	}
+	service := findSpan(t, spans, "RecipeService.CreateRecipe")
+	if service.Parent.SpanID() != route.SpanContext.SpanID() {
+		t.Errorf("service span is not a child of the route span")
+	}
+	for _, name := range []string{"db CreateRecipe", "db AddCuisinesToRecipe", "db EnqueueJob"} {
+		q := findSpan(t, spans, name)
+		if q.Parent.SpanID() != service.SpanContext.SpanID() {
+			t.Errorf("%s is not a child of the service span", name)
+		}
+		if q.SpanKind != trace.SpanKindClient {
+			t.Errorf("%s span kind: got %v, want client", name, q.SpanKind)
+		}
+	}
+
+	// The image is fetched by a job, in a trace of its own linked to the
+	// request.
+	js := services.NewJobService(store)
+	js.Handle(services.JobFetchExternalImage, func(ctx context.Context, job database.Job) error {
+		return nil
+	})
+	jobCtx, cancel := context.WithCancel(ctx)
+	js.Start(jobCtx, 1)
+	defer func() {
+		cancel()
+		js.Wait(ctx)
+	}()
+
+	deadline := time.After(10 * time.Second)
+	for {
+		for _, s := range exporter.GetSpans() {
+			if s.Name != "job "+services.JobFetchExternalImage || len(s.Links) == 0 {
+				continue
+			}
+			if s.Links[0].SpanContext.TraceID() != route.SpanContext.TraceID() {
+				continue
+			}
+			if s.Parent.IsValid() {
+				t.Errorf("job span has a parent, want a new root")
+			}
+			if s.SpanContext.TraceID() == route.SpanContext.TraceID() {
+				t.Errorf("job span is in the trace of the request")
+			}
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatal("no job span linked to the request")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	var names []string
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+		names = append(names, s.Name)
+	}
+	t.Fatalf("no span %q in %s", name, strings.Join(names, ", "))
+	return tracetest.SpanStub{}
+}
+
+func hasAttribute(s tracetest.SpanStub, key, value string) bool {
+	for _, kv := range s.Attributes {
+		if string(kv.Key) == key && kv.Value.AsString() == value {
+			return true
+		}
+	}
+	return false
+}
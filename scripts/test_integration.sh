@@ -32,31 +32,25 @@ trap cleanup EXIT
 echo "Creating test database..."
 psql -h "$DB_HOST" -d postgres -c "CREATE DATABASE $DB_NAME;"
 
+# Build the test binary
+echo "Building the test binary..."
+go build -o bin/planner_server_test ./cmd/mealorg
+
 # Run migrations
 echo "Running migrations..."
-(
-  cd sql/schema || exit
-  goose postgres "$DATABASE_URL" up
-)
+DATABASE_URL="$DATABASE_URL" bin/planner_server_test migrate up
 
-# Build the Go script for populating the database
-echo "Building the Go script..."
-(
-  cd scripts/populate_cuisines/
-  go build -o bin/populate_cuisines populate_cuisines.go
+# Populate the database with cuisine data
+echo "Populating database with cuisine data..."
+DATABASE_URL="$DATABASE_URL" bin/planner_server_test catalog seed
 
-  # Populate the database with cuisine data using the Go script
-  echo "Populating database with cuisine data..."
-  ./bin/populate_cuisines
-)
-
-# Build the test binary
-echo "Building the test binary..."
-go build -o bin/planner_server_test
+# Create the admin user that the tests edit the cuisines with
+echo "Creating admin user..."
+DATABASE_URL="$DATABASE_URL" bin/planner_server_test user create -email admin@mealorg.test -password verySafePassword1 -admin
 
 # Run the application in the background
 echo "Starting the application..."
-bin/planner_server_test &
+DATABASE_URL="$DATABASE_URL" PORT="$PORT" bin/planner_server_test serve &
 SERVER_PID=$!
 
 # Give the server some time to start
@@ -64,4 +58,4 @@ sleep 1
 
 # Run integration tests
 echo "Running integration tests..."
-hurl --test --jobs 1 --variable host=http://localhost:"$PORT" --variable email=testuser@testorg.com --variable password=verySafePassword1 --glob "tests/integration/**/*.hurl"
+hurl --test --jobs 1 --variable host=http://localhost:"$PORT" --variable email=testuser@testorg.com --variable password=verySafePassword1 --variable admin_email=admin@mealorg.test --variable admin_password=verySafePassword1 --glob "tests/integration/**/*.hurl"
@@ -0,0 +1,20 @@
+-- name: SaveCalendarToken :one
+INSERT INTO calendar_tokens (user_id, created_at, value)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET
+  created_at = EXCLUDED.created_at,
+  value = EXCLUDED.value
+RETURNING *;
+
+-- name: GetCalendarTokenByUserID :one
+SELECT * FROM calendar_tokens
+WHERE user_id = $1;
+
+-- name: GetCalendarTokenByValue :one
+SELECT * FROM calendar_tokens
+WHERE value = $1;
+
+-- name: DeleteCalendarToken :execrows
+DELETE FROM calendar_tokens
+WHERE user_id = $1;
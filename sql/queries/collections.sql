@@ -0,0 +1,116 @@
+-- name: CreateCollection :one
+INSERT INTO collections (id, created_at, updated_at, user_id, name, description, filter)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetCollectionByID :one
+SELECT * FROM collections
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListCollectionsByUserID :many
+SELECT * FROM collections
+WHERE user_id = $1
+ORDER BY lower(name);
+
+-- name: UpdateCollectionByID :one
+UPDATE collections
+SET
+  name = $3,
+  description = $4,
+  filter = $5,
+  updated_at = $6
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteCollectionByID :execrows
+DELETE FROM collections
+WHERE id = $1 AND user_id = $2;
+
+-- name: AddRecipeToCollection :exec
+INSERT INTO collection_recipe (created_at, collection_id, recipe_id, position)
+VALUES (
+  $1, $2, $3,
+  (SELECT coalesce(max(position), 0) + 1 FROM collection_recipe WHERE collection_id = $2)
+)
+ON CONFLICT DO NOTHING;
+
+-- name: InsertCollectionRecipe :exec
+INSERT INTO collection_recipe (created_at, collection_id, recipe_id, position)
+VALUES ($1, $2, $3, $4);
+
+-- name: RemoveRecipeFromCollection :execrows
+DELETE FROM collection_recipe
+WHERE collection_id = $1 AND recipe_id = $2;
+
+-- name: DeleteCollectionRecipes :exec
+DELETE FROM collection_recipe
+WHERE collection_id = $1;
+
+-- name: ListCollectionRecipes :many
+SELECT
+  r.*,
+  string_agg(c.name, ', ') AS cuisines,
+  s.last_cooked_at,
+  coalesce(s.times_cooked, 0)::BIGINT AS times_cooked,
+  s.average_rating,
+  (f.recipe_id IS NOT NULL)::BOOLEAN AS favorite
+FROM
+  collection_recipe cr
+JOIN
+  recipes r ON cr.recipe_id = r.id
+LEFT JOIN
+  recipe_cuisine rc ON r.id = rc.recipe_id
+LEFT JOIN
+  cuisines c ON rc.cuisine_id = c.id
+LEFT JOIN
+  recipe_cook_stats s ON r.id = s.recipe_id
+LEFT JOIN
+  recipe_favorites f ON r.id = f.recipe_id AND r.user_id = f.user_id
+WHERE
+  cr.collection_id = $1 AND r.deleted_at IS NULL
+GROUP BY
+  r.id, cr.position, s.last_cooked_at, s.times_cooked, s.average_rating, f.recipe_id
+ORDER BY
+  cr.position;
+
+-- name: ListRecipesByFilter :many
+SELECT
+  r.*,
+  string_agg(c.name, ', ') AS cuisines,
+  s.last_cooked_at,
+  coalesce(s.times_cooked, 0)::BIGINT AS times_cooked,
+  s.average_rating,
+  (f.recipe_id IS NOT NULL)::BOOLEAN AS favorite
+FROM
+  recipes r
+LEFT JOIN
+  recipe_cuisine rc ON r.id = rc.recipe_id
+LEFT JOIN
+  cuisines c ON rc.cuisine_id = c.id
+LEFT JOIN
+  recipe_cook_stats s ON r.id = s.recipe_id
+LEFT JOIN
+  recipe_favorites f ON r.id = f.recipe_id AND r.user_id = f.user_id
+WHERE
+  r.user_id = @user_id AND r.deleted_at IS NULL
+  AND (@max_cook_time::INT = 0 OR r.cook_time_in_minutes <= @max_cook_time::INT)
+  AND (
+    coalesce(cardinality(@cuisine_ids::UUID[]), 0) = 0
+    OR EXISTS (
+      SELECT 1 FROM recipe_cuisine fc
+      WHERE fc.recipe_id = r.id AND fc.cuisine_id = ANY(@cuisine_ids::UUID[])
+    )
+  )
+  AND (
+    SELECT count(DISTINCT lower(t.name)) FROM recipe_tag rt
+    JOIN tags t ON rt.tag_id = t.id
+    WHERE rt.recipe_id = r.id AND lower(t.name) = ANY(@tags::TEXT[])
+  ) = coalesce(cardinality(@tags::TEXT[]), 0)
+  AND (
+    SELECT count(DISTINCT ri.ingredient_id) FROM recipe_ingredient ri
+    WHERE ri.recipe_id = r.id AND ri.ingredient_id = ANY(@ingredient_ids::UUID[])
+  ) = coalesce(cardinality(@ingredient_ids::UUID[]), 0)
+GROUP BY
+  r.id, s.last_cooked_at, s.times_cooked, s.average_rating, f.recipe_id
+ORDER BY
+  lower(r.name);
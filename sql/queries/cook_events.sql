@@ -0,0 +1,25 @@
+-- name: CreateCookEvent :one
+INSERT INTO cook_events (id, created_at, updated_at, recipe_id, user_id, cooked_at, cooked_by, rating, notes, photo_url)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: UpdateCookEventByID :one
+UPDATE cook_events
+SET
+  cooked_at = $3,
+  cooked_by = $4,
+  rating = $5,
+  notes = $6,
+  photo_url = $7,
+  updated_at = $8
+WHERE id = $1 AND recipe_id = $2
+RETURNING *;
+
+-- name: DeleteCookEventByID :execrows
+DELETE FROM cook_events
+WHERE id = $1 AND recipe_id = $2;
+
+-- name: ListCookEventsByRecipeID :many
+SELECT * FROM cook_events
+WHERE recipe_id = $1
+ORDER BY cooked_at DESC;
@@ -8,6 +8,12 @@ SELECT *
 FROM cuisines
 WHERE id = $1;
 
+-- name: GetCuisineByName :one
+SELECT *
+FROM cuisines
+WHERE name = $1
+LIMIT 1;
+
 -- name: UpdateCuisineByID :one
 UPDATE cuisines
 SET
@@ -8,6 +8,11 @@ SELECT *
 FROM ingredients
 WHERE id = $1;
 
+-- name: GetIngredientByName :one
+SELECT *
+FROM ingredients
+WHERE name = $1;
+
 -- name: UpdateIngredientByID :one
 UPDATE ingredients
 SET
@@ -0,0 +1,57 @@
+-- name: EnqueueJob :exec
+INSERT INTO jobs (
+  id, created_at, updated_at, kind, unique_key, payload, trace_context, max_attempts, run_at, recipe_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (unique_key) WHERE status = 'pending' DO NOTHING;
+
+-- name: ClaimJob :one
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, locked_at = $1, updated_at = $1
+WHERE id = (
+  SELECT id
+  FROM jobs
+  WHERE status = 'pending' AND run_at <= $1
+  ORDER BY run_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteJob :execrows
+UPDATE jobs
+SET status = 'succeeded', last_error = NULL, locked_at = NULL, updated_at = $2
+WHERE id = $1 AND status = 'running';
+
+-- name: KillJob :execrows
+UPDATE jobs
+SET status = 'dead', last_error = $3, locked_at = NULL, updated_at = $2
+WHERE id = $1 AND status = 'running';
+
+-- name: RetryJob :exec
+-- RetryJob cancels the job instead when a newer one with the same unique key
+-- is pending, to keep a single pending job per unique key.
+UPDATE jobs
+SET status = CASE
+    WHEN EXISTS (
+      SELECT 1 FROM jobs j WHERE j.unique_key = jobs.unique_key AND j.status = 'pending'
+    ) THEN 'cancelled'
+    ELSE 'pending'
+  END,
+  last_error = $3, run_at = $4, locked_at = NULL, updated_at = $2
+WHERE id = $1 AND status = 'running';
+
+-- name: DeleteFinishedJobs :execrows
+DELETE FROM jobs
+WHERE status IN ('succeeded', 'cancelled', 'dead') AND updated_at < $1;
+
+-- name: ListStaleJobs :many
+SELECT *
+FROM jobs
+WHERE status = 'running' AND locked_at < $1;
+
+-- name: ListJobsByRecipeID :many
+SELECT *
+FROM jobs
+WHERE recipe_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
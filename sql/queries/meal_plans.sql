@@ -0,0 +1,92 @@
+-- name: CreateMealPlan :one
+INSERT INTO meal_plans (id, created_at, updated_at, user_id, name, start_date)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetMealPlanByID :one
+SELECT * FROM meal_plans
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListMealPlansByUserID :many
+SELECT * FROM meal_plans
+WHERE user_id = $1
+ORDER BY start_date DESC;
+
+-- name: UpdateMealPlanByID :one
+UPDATE meal_plans
+SET
+  name = $3,
+  start_date = $4,
+  updated_at = $5
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteMealPlanByID :execrows
+DELETE FROM meal_plans
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreatePlannedMeal :exec
+INSERT INTO planned_meals (id, created_at, meal_plan_id, recipe_id, day, serve_time)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: DeletePlannedMealsByPlanID :exec
+DELETE FROM planned_meals
+WHERE meal_plan_id = $1;
+
+-- name: ListPlannedMealsByPlanID :many
+SELECT
+  pm.*,
+  r.name AS recipe_name
+FROM
+  planned_meals pm
+JOIN
+  recipes r ON pm.recipe_id = r.id
+WHERE
+  pm.meal_plan_id = $1 AND r.deleted_at IS NULL
+ORDER BY
+  pm.day, pm.serve_time NULLS FIRST;
+
+-- name: ListPlanCandidates :many
+SELECT
+  r.id,
+  r.name,
+  r.cook_time_in_minutes,
+  ARRAY(SELECT rc.cuisine_id FROM recipe_cuisine rc WHERE rc.recipe_id = r.id)::UUID[] AS cuisine_ids,
+  ARRAY(SELECT DISTINCT ri.ingredient_id FROM recipe_ingredient ri WHERE ri.recipe_id = r.id)::UUID[] AS ingredient_ids,
+  s.last_cooked_at,
+  s.average_rating,
+  (f.recipe_id IS NOT NULL)::BOOLEAN AS favorite
+FROM
+  recipes r
+LEFT JOIN
+  recipe_cook_stats s ON r.id = s.recipe_id
+LEFT JOIN
+  recipe_favorites f ON r.id = f.recipe_id AND r.user_id = f.user_id
+WHERE
+  r.user_id = $1 AND r.deleted_at IS NULL;
+
+-- name: ListCalendarMeals :many
+SELECT
+  pm.id,
+  pm.day,
+  pm.serve_time,
+  r.id AS recipe_id,
+  r.name AS recipe_name,
+  r.cook_time_in_minutes,
+  r.notes,
+  coalesce((
+    SELECT string_agg(i.name, ', ' ORDER BY ri.index)
+    FROM recipe_ingredient ri
+    JOIN ingredients i ON ri.ingredient_id = i.id
+    WHERE ri.recipe_id = r.id
+  ), '')::TEXT AS ingredients
+FROM
+  planned_meals pm
+JOIN
+  meal_plans mp ON pm.meal_plan_id = mp.id
+JOIN
+  recipes r ON pm.recipe_id = r.id
+WHERE
+  mp.user_id = $1 AND pm.day >= $2 AND r.deleted_at IS NULL
+ORDER BY
+  pm.day, pm.serve_time NULLS FIRST;
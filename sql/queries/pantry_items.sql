@@ -0,0 +1,82 @@
+-- name: CreatePantryItem :one
+INSERT INTO pantry_items (id, created_at, updated_at, user_id, ingredient_id, quantity, unit, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetPantryItemByID :one
+SELECT
+  p.*,
+  i.name AS ingredient_name
+FROM
+  pantry_items p
+JOIN
+  ingredients i ON p.ingredient_id = i.id
+WHERE
+  p.id = $1 AND p.user_id = $2;
+
+-- name: ListPantryItemsByUserID :many
+SELECT
+  p.*,
+  i.name AS ingredient_name
+FROM
+  pantry_items p
+JOIN
+  ingredients i ON p.ingredient_id = i.id
+WHERE
+  p.user_id = $1
+ORDER BY
+  p.expires_at NULLS LAST, lower(i.name);
+
+-- name: UpdatePantryItemByID :one
+UPDATE pantry_items
+SET
+  ingredient_id = $3,
+  quantity = $4,
+  unit = $5,
+  expires_at = $6,
+  updated_at = $7
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeletePantryItemByID :execrows
+DELETE FROM pantry_items
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListCookableRecipes :many
+-- The pantry covers an ingredient when it has some of it, not expired.
+-- Recipes using an item expiring before expiring_before come first, then
+-- those the pantry covers the most of.
+SELECT
+  r.id,
+  r.name,
+  r.cook_time_in_minutes,
+  count(*) AS total,
+  count(p.ingredient_id) AS covered,
+  coalesce(array_agg(i.name ORDER BY ri.index) FILTER (WHERE p.ingredient_id IS NULL), '{}')::TEXT[] AS missing,
+  min(p.expires_at) AS expires_at
+FROM
+  recipes r
+JOIN
+  recipe_ingredient ri ON r.id = ri.recipe_id
+JOIN
+  ingredients i ON ri.ingredient_id = i.id
+LEFT JOIN (
+  SELECT ingredient_id, min(expires_at) AS expires_at
+  FROM pantry_items
+  WHERE
+    user_id = @user_id
+    AND (quantity IS NULL OR quantity > 0)
+    AND (expires_at IS NULL OR expires_at >= @today)
+  GROUP BY ingredient_id
+) p ON ri.ingredient_id = p.ingredient_id
+WHERE
+  r.user_id = @user_id AND r.deleted_at IS NULL
+GROUP BY
+  r.id
+ORDER BY
+  coalesce(min(p.expires_at) < @expiring_before, false) DESC,
+  count(p.ingredient_id)::FLOAT8 / count(*) DESC,
+  min(p.expires_at) NULLS LAST,
+  r.name
+LIMIT
+  sqlc.arg('limit');
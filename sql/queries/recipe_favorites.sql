@@ -0,0 +1,8 @@
+-- name: AddRecipeFavorite :exec
+INSERT INTO recipe_favorites (created_at, user_id, recipe_id)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteRecipeFavorite :exec
+DELETE FROM recipe_favorites
+WHERE user_id = $1 AND recipe_id = $2;
@@ -0,0 +1,25 @@
+-- name: GetRecipeImageByRecipeID :one
+SELECT *
+FROM recipe_images
+WHERE recipe_id = $1;
+
+-- name: SaveRecipeImage :exec
+INSERT INTO recipe_images (
+  recipe_id, created_at, updated_at, source_url, status, last_error, fetched_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (recipe_id) DO UPDATE
+SET
+  updated_at = EXCLUDED.updated_at,
+  source_url = EXCLUDED.source_url,
+  status = EXCLUDED.status,
+  last_error = EXCLUDED.last_error,
+  fetched_at = EXCLUDED.fetched_at;
+
+-- name: ListStaleRecipeImages :many
+SELECT recipe_id
+FROM recipe_images
+WHERE ((status = 'ok' AND fetched_at < $1)
+  OR (status = 'failed' AND fetched_at < $2))
+  AND recipe_id IN (SELECT id FROM recipes WHERE deleted_at IS NULL)
+ORDER BY fetched_at
+LIMIT $3;
@@ -0,0 +1,7 @@
+-- name: CreateRecipeImport :exec
+INSERT INTO recipe_imports (user_id, source, source_id, recipe_id, created_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetRecipeImport :one
+SELECT * FROM recipe_imports
+WHERE user_id = $1 AND source = $2 AND source_id = $3;
@@ -0,0 +1,22 @@
+-- name: CreateRecipeRevision :one
+INSERT INTO recipe_revisions (
+  id, created_at, recipe_id, revision, user_id, recipe, restored_from
+) VALUES (
+  $1, $2, $3,
+  (SELECT COALESCE(MAX(revision), 0) + 1 FROM recipe_revisions WHERE recipe_id = $3),
+  $4, $5, $6
+)
+RETURNING *;
+
+-- name: CountRecipeRevisions :one
+SELECT count(*) FROM recipe_revisions
+WHERE recipe_id = $1;
+
+-- name: GetRecipeRevision :one
+SELECT * FROM recipe_revisions
+WHERE recipe_id = $1 AND revision = $2;
+
+-- name: ListRecipeRevisions :many
+SELECT * FROM recipe_revisions
+WHERE recipe_id = $1
+ORDER BY revision DESC;
@@ -0,0 +1,14 @@
+-- name: AddTagToRecipe :exec
+INSERT INTO recipe_tag (created_at, tag_id, recipe_id)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: ListTagsByRecipeID :many
+SELECT t.* FROM tags t
+JOIN recipe_tag rt ON t.id = rt.tag_id
+WHERE rt.recipe_id = $1
+ORDER BY lower(t.name);
+
+-- name: DeleteTagsByRecipeID :exec
+DELETE FROM recipe_tag
+WHERE recipe_id = $1;
@@ -17,7 +17,12 @@ RETURNING *;
 
 -- name: GetRecipeByID :one
 SELECT * FROM recipes
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetRecipeByIDForUpdate :one
+SELECT * FROM recipes
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
 
 -- name: UpdateRecipeByID :one
 UPDATE recipes
@@ -30,41 +35,99 @@ SET
   yield = $6,
   cook_time_in_minutes = $7,
   notes = $8
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: ListRecipesByUserID :many
-SELECT *
-FROM recipes
-WHERE user_id = $1
-ORDER BY name
+SELECT
+  r.*,
+  s.last_cooked_at,
+  coalesce(s.times_cooked, 0)::BIGINT AS times_cooked,
+  s.average_rating,
+  (f.recipe_id IS NOT NULL)::BOOLEAN AS favorite
+FROM
+  recipes r
+LEFT JOIN
+  recipe_cook_stats s ON r.id = s.recipe_id
+LEFT JOIN
+  recipe_favorites f ON r.id = f.recipe_id AND r.user_id = f.user_id
+WHERE
+  r.user_id = @user_id AND r.deleted_at IS NULL
+ORDER BY
+  CASE WHEN @sort::TEXT = 'last_cooked' THEN s.last_cooked_at END DESC NULLS LAST,
+  CASE WHEN @sort::TEXT = 'times_cooked' THEN s.times_cooked END DESC NULLS LAST,
+  CASE WHEN @sort::TEXT = 'rating' THEN s.average_rating END DESC NULLS LAST,
+  CASE WHEN @sort::TEXT = 'favorite' THEN f.recipe_id IS NOT NULL END DESC,
+  r.name
 LIMIT
-  $2
-  OFFSET $3;
+  sqlc.arg('limit')
+  OFFSET sqlc.arg('offset');
 
 -- name: ListRecipesWithCuisinesByUserID :many
 SELECT
   r.*,
-  string_agg(c.name, ', ') AS cuisines
+  string_agg(c.name, ', ') AS cuisines,
+  s.last_cooked_at,
+  coalesce(s.times_cooked, 0)::BIGINT AS times_cooked,
+  s.average_rating,
+  (f.recipe_id IS NOT NULL)::BOOLEAN AS favorite
 FROM
   recipes r
 LEFT JOIN
   recipe_cuisine rc ON r.id = rc.recipe_id
 LEFT JOIN
   cuisines c ON rc.cuisine_id = c.id
+LEFT JOIN
+  recipe_cook_stats s ON r.id = s.recipe_id
+LEFT JOIN
+  recipe_favorites f ON r.id = f.recipe_id AND r.user_id = f.user_id
 WHERE
-  r.user_id = $1
+  r.user_id = @user_id AND r.deleted_at IS NULL
 GROUP BY
-  r.id
+  r.id, s.last_cooked_at, s.times_cooked, s.average_rating, f.recipe_id
+ORDER BY
+  CASE WHEN @sort::TEXT = 'last_cooked' THEN s.last_cooked_at END DESC NULLS LAST,
+  CASE WHEN @sort::TEXT = 'times_cooked' THEN s.times_cooked END DESC NULLS LAST,
+  CASE WHEN @sort::TEXT = 'rating' THEN s.average_rating END DESC NULLS LAST,
+  CASE WHEN @sort::TEXT = 'favorite' THEN f.recipe_id IS NOT NULL END DESC,
+  r.name
 LIMIT
-  $2
-  OFFSET $3;
+  sqlc.arg('limit')
+  OFFSET sqlc.arg('offset');
 
 -- name: DeleteRecipe :exec
 DELETE FROM recipes
 WHERE id = $1;
 
--- name: SaveExternalImageURL :exec
+-- name: SoftDeleteRecipe :execrows
 UPDATE recipes
-SET external_image_url = $2
+SET deleted_at = $2
+WHERE id = $1 AND user_id = $3 AND deleted_at IS NULL;
+
+-- name: ListDeletedRecipesByUserID :many
+SELECT *
+FROM recipes
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: RestoreDeletedRecipe :execrows
+UPDATE recipes
+SET deleted_at = NULL
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: DeleteDeletedRecipe :execrows
+DELETE FROM recipes
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedRecipes :many
+DELETE FROM recipes
+WHERE deleted_at < $1
+RETURNING id;
+
+-- name: SaveExternalMetadata :exec
+UPDATE recipes
+SET
+  external_image_url = $2,
+  site_name = $3,
+  video_url = $4
 WHERE id = $1;
@@ -0,0 +1,4 @@
+-- name: CountSessions :one
+SELECT count(*)
+FROM sessions
+WHERE expiry > now();
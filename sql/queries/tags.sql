@@ -0,0 +1,39 @@
+-- name: CreateTag :one
+INSERT INTO tags (id, created_at, updated_at, user_id, name)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetTagByName :one
+SELECT * FROM tags
+WHERE user_id = $1 AND lower(name) = lower($2);
+
+-- name: ListTagsByUserID :many
+SELECT
+  t.*,
+  count(r.id) AS recipe_count
+FROM
+  tags t
+LEFT JOIN
+  recipe_tag rt ON t.id = rt.tag_id
+LEFT JOIN
+  recipes r ON rt.recipe_id = r.id AND r.deleted_at IS NULL
+WHERE
+  t.user_id = @user_id AND lower(t.name) LIKE lower(@prefix::TEXT) || '%'
+GROUP BY
+  t.id
+ORDER BY
+  lower(t.name)
+LIMIT
+  @max_tags;
+
+-- name: UpdateTagByID :one
+UPDATE tags
+SET
+  name = $3,
+  updated_at = $4
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteTagByID :execrows
+DELETE FROM tags
+WHERE id = $1 AND user_id = $2;
@@ -20,3 +20,8 @@ RETURNING *;
 -- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = $1;
+
+-- name: UpdateUserAdminByID :exec
+UPDATE users
+SET is_admin = $2, updated_at = $3
+WHERE id = $1;
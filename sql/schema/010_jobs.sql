@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE jobs (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  kind TEXT NOT NULL,
+  unique_key TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  trace_context JSONB NOT NULL DEFAULT '{}',
+  status TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'running', 'succeeded', 'cancelled', 'dead')),
+  attempts INT NOT NULL DEFAULT 0,
+  max_attempts INT NOT NULL DEFAULT 5,
+  last_error TEXT,
+  run_at TIMESTAMP NOT NULL,
+  locked_at TIMESTAMP,
+  recipe_id UUID REFERENCES recipes (id) ON DELETE CASCADE
+);
+
+-- At most one pending job per unique key
+CREATE UNIQUE INDEX jobs_pending_unique_key_idx ON jobs (unique_key) WHERE status = 'pending';
+CREATE INDEX jobs_pending_run_at_idx ON jobs (run_at) WHERE status = 'pending';
+CREATE INDEX jobs_recipe_id_idx ON jobs (recipe_id);
+
+-- +goose Down
+DROP TABLE jobs;
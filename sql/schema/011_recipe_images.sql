@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE recipe_images (
+  recipe_id UUID PRIMARY KEY REFERENCES recipes (id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  source_url TEXT NOT NULL,
+  status TEXT NOT NULL CHECK (status IN ('ok', 'failed')),
+  last_error TEXT,
+  fetched_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX recipe_images_fetched_at_idx ON recipe_images (fetched_at);
+
+-- +goose Down
+DROP TABLE recipe_images;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE recipes
+ADD COLUMN site_name text,
+ADD COLUMN video_url text;
+
+-- +goose Down
+ALTER TABLE recipes
+DROP COLUMN video_url,
+DROP COLUMN site_name;
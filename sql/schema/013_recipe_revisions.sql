@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE recipe_revisions (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  recipe_id UUID NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  revision INT NOT NULL,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  recipe JSONB NOT NULL,
+  restored_from INT,
+  UNIQUE (recipe_id, revision)
+);
+
+-- +goose Down
+DROP TABLE recipe_revisions;
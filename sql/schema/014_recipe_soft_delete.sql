@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE recipes
+ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX recipes_deleted_at_idx ON recipes (deleted_at)
+WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX recipes_deleted_at_idx;
+
+ALTER TABLE recipes
+DROP COLUMN deleted_at;
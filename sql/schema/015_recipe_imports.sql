@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE recipe_imports (
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  source TEXT NOT NULL,
+  source_id TEXT NOT NULL,
+  recipe_id UUID NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, source, source_id)
+);
+
+-- +goose Down
+DROP TABLE recipe_imports;
@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE TABLE tags (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name VARCHAR(64) NOT NULL
+);
+
+CREATE UNIQUE INDEX tags_user_id_name_idx ON tags (user_id, lower(name));
+
+CREATE TABLE recipe_tag (
+  created_at TIMESTAMP NOT NULL,
+  tag_id UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+  recipe_id UUID NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  PRIMARY KEY (recipe_id, tag_id)
+);
+
+CREATE INDEX recipe_tag_tag_id_idx ON recipe_tag (tag_id);
+
+-- filter is the saved query of a smart collection, whose recipes are found
+-- when it is read. Manual collections have none and list collection_recipe.
+CREATE TABLE collections (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name VARCHAR(255) NOT NULL,
+  description TEXT,
+  filter JSONB
+);
+
+CREATE INDEX collections_user_id_idx ON collections (user_id);
+
+CREATE TABLE collection_recipe (
+  created_at TIMESTAMP NOT NULL,
+  collection_id UUID NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+  recipe_id UUID NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  position INT NOT NULL,
+  PRIMARY KEY (collection_id, recipe_id)
+);
+
+-- +goose Down
+DROP TABLE collection_recipe;
+DROP TABLE collections;
+DROP TABLE recipe_tag;
+DROP TABLE tags;
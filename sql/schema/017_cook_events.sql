@@ -0,0 +1,42 @@
+-- +goose Up
+-- cook_events is the log of the times a recipe was cooked. cooked_by is
+-- free text, as whoever cooked may not have an account.
+CREATE TABLE cook_events (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  recipe_id UUID NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  cooked_at TIMESTAMP NOT NULL,
+  cooked_by VARCHAR(255),
+  rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+  notes TEXT,
+  photo_url TEXT
+);
+
+CREATE INDEX cook_events_recipe_id_idx ON cook_events (recipe_id, cooked_at);
+
+CREATE VIEW recipe_cook_stats AS
+SELECT
+  recipe_id,
+  max(cooked_at) AS last_cooked_at,
+  count(*) AS times_cooked,
+  avg(rating)::FLOAT8 AS average_rating
+FROM
+  cook_events
+GROUP BY
+  recipe_id;
+
+CREATE TABLE recipe_favorites (
+  created_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  recipe_id UUID NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  PRIMARY KEY (user_id, recipe_id)
+);
+
+CREATE INDEX recipe_favorites_recipe_id_idx ON recipe_favorites (recipe_id);
+
+-- +goose Down
+DROP TABLE recipe_favorites;
+DROP VIEW recipe_cook_stats;
+DROP TABLE cook_events;
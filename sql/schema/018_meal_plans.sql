@@ -0,0 +1,28 @@
+-- +goose Up
+-- A meal plan is a run of days starting on start_date, with the dinners
+-- planned on them. serve_time is the time of day as HH:MM, when known.
+CREATE TABLE meal_plans (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name VARCHAR(255) NOT NULL,
+  start_date TIMESTAMP NOT NULL
+);
+
+CREATE INDEX meal_plans_user_id_idx ON meal_plans (user_id, start_date);
+
+CREATE TABLE planned_meals (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  meal_plan_id UUID NOT NULL REFERENCES meal_plans (id) ON DELETE CASCADE,
+  recipe_id UUID NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  day TIMESTAMP NOT NULL,
+  serve_time VARCHAR(5)
+);
+
+CREATE INDEX planned_meals_meal_plan_id_idx ON planned_meals (meal_plan_id, day);
+
+-- +goose Down
+DROP TABLE planned_meals;
+DROP TABLE meal_plans;
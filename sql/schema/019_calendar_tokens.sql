@@ -0,0 +1,11 @@
+-- +goose Up
+-- calendar_tokens holds the secret in the URL of the iCalendar feed of a
+-- user, which calendar apps fetch without logging in.
+CREATE TABLE calendar_tokens (
+  user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL,
+  value TEXT NOT NULL UNIQUE
+);
+
+-- +goose Down
+DROP TABLE calendar_tokens;
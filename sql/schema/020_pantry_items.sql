@@ -0,0 +1,19 @@
+-- +goose Up
+-- pantry_items is what a household has in stock. A household is a single
+-- user for now, so items belong to a user. quantity and unit are left out
+-- for what is simply there, like salt.
+CREATE TABLE pantry_items (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  ingredient_id UUID NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+  quantity FLOAT8 CHECK (quantity >= 0),
+  unit VARCHAR(32),
+  expires_at TIMESTAMP
+);
+
+CREATE INDEX pantry_items_user_id_idx ON pantry_items (user_id, ingredient_id);
+
+-- +goose Down
+DROP TABLE pantry_items;
@@ -0,0 +1,8 @@
+// Package schema embeds the goose migrations so that the binary can migrate
+// the database without the sql directory being shipped next to it.
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS